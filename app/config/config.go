@@ -0,0 +1,188 @@
+// Package config loads the settings of the application once at startup.
+// Every value has a default, can be set in an optional YAML or TOML file
+// named by CONFIG_FILE and is overridden by its environment variable, the
+// variables may also come from a .env file.
+package config
+
+import (
+	"time"
+
+	"tugaskita/utils/imageproc"
+	"tugaskita/utils/mail"
+	"tugaskita/utils/prayertime"
+	"tugaskita/utils/storage"
+)
+
+// Config is the complete configuration of the application.
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Database  Database  `yaml:"database"`
+	JWT       JWT       `yaml:"jwt"`
+	Mail      Mail      `yaml:"mail"`
+	Storage   Storage   `yaml:"storage"`
+	Image     Image     `yaml:"image"`
+	Prayer    Prayer    `yaml:"prayer"`
+	Scheduler Scheduler `yaml:"scheduler"`
+}
+
+// Environments accepted by Server.Environment.
+const (
+	Development = "development"
+	Production  = "production"
+)
+
+// Server configures the HTTP server. In the development environment the
+// tables are created by AutoMigrate instead of the versioned migrations.
+type Server struct {
+	Port        int    `yaml:"port" env:"SERVERPORT" default:"8081"`
+	Environment string `yaml:"environment" env:"APP_ENV" default:"production"`
+}
+
+// Database configures the connection. Driver is mysql, postgres or
+// sqlite, for sqlite Name is the path of the database file, ":memory:"
+// keeps it in memory. Port defaults to the one of the driver.
+type Database struct {
+	Driver   string `yaml:"driver" env:"DBDRIVER" default:"mysql"`
+	Host     string `yaml:"host" env:"DBHOST" default:"127.0.0.1"`
+	Port     int    `yaml:"port" env:"DBPORT"`
+	User     string `yaml:"user" env:"DBUSER"`
+	Password string `yaml:"password" env:"DBPASS"`
+	Name     string `yaml:"name" env:"DBNAME"`
+	SSLMode  string `yaml:"ssl_mode" env:"DBSSLMODE" default:"prefer"`
+}
+
+// JWT configures the signing of access tokens.
+type JWT struct {
+	SecretKey string `yaml:"secret_key" env:"JWT_SECRET_KEY"`
+}
+
+// Mail configures outgoing email. The "smtp" driver sends through Host,
+// "log" writes messages to Dir or the log so the flow can be tested
+// locally.
+type Mail struct {
+	Driver   string `yaml:"driver" env:"MAIL_DRIVER" default:"log"`
+	Host     string `yaml:"host" env:"MAIL_HOST"`
+	Port     int    `yaml:"port" env:"MAIL_PORT" default:"587"`
+	Username string `yaml:"username" env:"MAIL_USERNAME"`
+	Password string `yaml:"password" env:"MAIL_PASSWORD"`
+	From     string `yaml:"from" env:"MAIL_FROM"`
+	Dir      string `yaml:"dir" env:"MAIL_DIR"`
+}
+
+// Sender returns the sender of the configured driver.
+func (c Mail) Sender() mail.Sender {
+	return mail.New(mail.Config(c))
+}
+
+// Storage configures where uploaded files are kept. Driver is local, s3
+// or cloudinary. Local files are written to LocalDir and their URLs start
+// with LocalURL. S3 works with any S3 compatible service such as MinIO,
+// S3PublicURL defaults to the bucket URL on the endpoint.
+type Storage struct {
+	Driver           string `yaml:"driver" env:"STORAGE_DRIVER" default:"local"`
+	LocalDir         string `yaml:"local_dir" env:"STORAGE_LOCAL_DIR" default:"public"`
+	LocalURL         string `yaml:"local_url" env:"STORAGE_LOCAL_URL" default:"public"`
+	S3Endpoint       string `yaml:"s3_endpoint" env:"S3_ENDPOINT"`
+	S3Region         string `yaml:"s3_region" env:"S3_REGION"`
+	S3Bucket         string `yaml:"s3_bucket" env:"S3_BUCKET"`
+	S3AccessKey      string `yaml:"s3_access_key" env:"S3_ACCESS_KEY"`
+	S3SecretKey      string `yaml:"s3_secret_key" env:"S3_SECRET_KEY"`
+	S3UseSSL         bool   `yaml:"s3_use_ssl" env:"S3_USE_SSL" default:"true"`
+	S3PublicURL      string `yaml:"s3_public_url" env:"S3_PUBLIC_URL"`
+	CloudinaryName   string `yaml:"cloudinary_name" env:"API_NAME"`
+	CloudinaryKey    string `yaml:"cloudinary_key" env:"API_KEY"`
+	CloudinarySecret string `yaml:"cloudinary_secret" env:"API_SECRET"`
+}
+
+// Open returns the storage of the configured driver.
+func (c Storage) Open() (storage.Storage, error) {
+	switch c.Driver {
+	case "s3":
+		return storage.NewS3(c.S3Endpoint, c.S3Region, c.S3Bucket, c.S3AccessKey, c.S3SecretKey, c.S3UseSSL, c.S3PublicURL)
+	case "cloudinary":
+		return storage.NewCloudinary(c.CloudinaryName, c.CloudinaryKey, c.CloudinarySecret)
+	}
+	return storage.NewLocal(c.LocalDir, c.LocalURL), nil
+}
+
+// Image configures the processing of uploaded images. Larger images are
+// scaled down to MaxDimension pixels on their longest side and thumbnails
+// to ThumbnailSize, Quality is the JPEG quality from 1 to 100. Proof
+// photos whose difference hashes differ in at most DuplicateDistance of
+// 64 bits are flagged as the same photo, when they were uploaded for the
+// same task or by the same student less than DuplicateWindow apart.
+type Image struct {
+	MaxDimension      int           `yaml:"max_dimension" env:"IMAGE_MAX_DIMENSION" default:"2048"`
+	ThumbnailSize     int           `yaml:"thumbnail_size" env:"IMAGE_THUMBNAIL_SIZE" default:"320"`
+	Quality           int           `yaml:"quality" env:"IMAGE_QUALITY" default:"85"`
+	DuplicateDistance int           `yaml:"duplicate_distance" env:"IMAGE_DUPLICATE_DISTANCE" default:"6"`
+	DuplicateWindow   time.Duration `yaml:"duplicate_window" env:"IMAGE_DUPLICATE_WINDOW" default:"720h"`
+}
+
+// Options returns the image processing options.
+func (c Image) Options() imageproc.Options {
+	options := imageproc.DefaultOptions
+	options.MaxDimension = c.MaxDimension
+	options.ThumbnailSize = c.ThumbnailSize
+	options.Quality = c.Quality
+	options.DuplicateDistance = c.DuplicateDistance
+	options.DuplicateWindow = c.DuplicateWindow
+	return options
+}
+
+// Prayer configures the prayer time calculation. Without a location
+// prayer tasks are open the whole day. Timezone is an IANA name, the
+// server time zone when empty. AsrFactor is 1 (Shafi'i) or 2 (Hanafi).
+type Prayer struct {
+	Latitude  *float64 `yaml:"latitude" env:"PRAYER_LATITUDE"`
+	Longitude *float64 `yaml:"longitude" env:"PRAYER_LONGITUDE"`
+	Timezone  string   `yaml:"timezone" env:"PRAYER_TIMEZONE"`
+	Method    string   `yaml:"method" env:"PRAYER_METHOD" default:"kemenag"`
+	AsrFactor float64  `yaml:"asr_factor" env:"PRAYER_ASR_FACTOR" default:"1"`
+}
+
+func (c Prayer) calculator() (*prayertime.Calculator, error) {
+	if c.Latitude == nil && c.Longitude == nil {
+		return nil, nil
+	}
+
+	loc := time.Local
+	if c.Timezone != "" {
+		parsed, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		loc = parsed
+	}
+
+	calculator, err := prayertime.NewCalculator(c.Method, *c.Latitude, *c.Longitude, loc)
+	if err != nil {
+		return nil, err
+	}
+	calculator.AsrFactor = c.AsrFactor
+
+	return calculator, nil
+}
+
+// Calculator returns the prayer time calculator, nil when no location is
+// set. The section is checked by Load so it can't fail afterwards.
+func (c Prayer) Calculator() *prayertime.Calculator {
+	calculator, _ := c.calculator()
+	return calculator
+}
+
+// Scheduler configures the background jobs. Schedules are cron
+// expressions, "off" disables a job. CatchUp is how long after a missed
+// activation a job still runs on startup. A failed run is retried Retries
+// times starting RetryBackoff later, LockTTL is how long an instance holds
+// an activation.
+type Scheduler struct {
+	MonthlyReset string        `yaml:"monthly_reset" env:"RESET_MONTHLY_SCHEDULE" default:"0 0 1 * *"`
+	AnnualReset  string        `yaml:"annual_reset" env:"RESET_ANNUAL_SCHEDULE" default:"0 0 1 7 *"`
+	TaskSeries   string        `yaml:"task_series" env:"TASK_SERIES_SCHEDULE" default:"0 1 * * *"`
+	ReligionTask string        `yaml:"religion_task" env:"RELIGION_TASK_SCHEDULE" default:"5 0 * * *"`
+	CatchUp      time.Duration `yaml:"catch_up" env:"RESET_CATCHUP" default:"24h"`
+	Retries      int           `yaml:"retries" env:"JOB_RETRIES" default:"3"`
+	RetryBackoff time.Duration `yaml:"retry_backoff" env:"JOB_RETRY_BACKOFF" default:"1m"`
+	LockTTL      time.Duration `yaml:"lock_ttl" env:"JOB_LOCK_TTL" default:"1h"`
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Load returns the configuration from the defaults, the file named by
+// CONFIG_FILE and the environment, in increasing priority. It fails when
+// a value can't be parsed or is missing or insecure.
+func Load() (*Config, error) {
+	godotenv.Load(".env")
+
+	cfg := new(Config)
+	if err := walk(reflect.ValueOf(cfg).Elem(), func(field reflect.Value, tag reflect.StructTag) error {
+		value, ok := tag.Lookup("default")
+		if !ok {
+			return nil
+		}
+		return set(field, value)
+	}); err != nil {
+		return nil, err
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	if err := walk(reflect.ValueOf(cfg).Elem(), func(field reflect.Value, tag reflect.StructTag) error {
+		env := tag.Get("env")
+		value, ok := os.LookupEnv(env)
+		if env == "" || !ok || value == "" {
+			return nil
+		}
+		if err := set(field, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", env, err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// walk calls fn with every field of the sections of v.
+func walk(v reflect.Value, fn func(field reflect.Value, tag reflect.StructTag) error) error {
+	for i := 0; i < v.NumField(); i++ {
+		section := v.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			if err := fn(section.Field(j), section.Type().Field(j).Tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func set(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Pointer {
+		elem := reflect.New(field.Type().Elem())
+		if err := set(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	if field.Type() == durationType {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(parsed))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetInt(int64(parsed))
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		field.SetBool(parsed)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// loadFile reads a YAML file, or a TOML file that is turned into YAML,
+// over cfg. Keys are the yaml tags of Config, unknown keys are an error.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	case ".toml":
+		values := map[string]any{}
+		if _, errToml := toml.Decode(string(data), &values); errToml != nil {
+			return errToml
+		}
+		data, err = yaml.Marshal(values)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.New("only .yaml, .yml and .toml files are supported")
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"tugaskita/utils/scheduler"
+)
+
+// MinSecretKeyLength is the shortest accepted JWT_SECRET_KEY, 32 bytes
+// matches the output of the HS256 hash.
+const MinSecretKeyLength = 32
+
+var localHosts = map[string]bool{"localhost": true, "127.0.0.1": true, "::1": true}
+
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+// validate returns every problem of the configuration at once so they can
+// be fixed together.
+func (c *Config) validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validPort(c.Server.Port), "SERVERPORT must be between 1 and 65535")
+	check(c.Server.Environment == Development || c.Server.Environment == Production,
+		"APP_ENV must be development or production")
+
+	db := c.Database
+	switch db.Driver {
+	case "sqlite":
+		check(db.Name != "", "DBNAME is required, the path of the sqlite database")
+	case "mysql", "postgres":
+		check(db.Host != "", "DBHOST is required")
+		check(db.Port == 0 || validPort(db.Port), "DBPORT must be between 1 and 65535")
+		check(db.User != "", "DBUSER is required")
+		check(db.Name != "", "DBNAME is required")
+		check(db.Password != "" || localHosts[db.Host],
+			"DBPASS is required when the database is not on localhost")
+	default:
+		check(false, "DBDRIVER must be mysql, postgres or sqlite")
+	}
+	if db.Driver == "postgres" {
+		check(sslModes[db.SSLMode], "DBSSLMODE must be disable, allow, prefer, require, verify-ca or verify-full")
+	}
+
+	check(c.JWT.SecretKey != "", "JWT_SECRET_KEY is required")
+	check(c.JWT.SecretKey == "" || len(c.JWT.SecretKey) >= MinSecretKeyLength,
+		"JWT_SECRET_KEY must be at least %d characters", MinSecretKeyLength)
+
+	switch c.Mail.Driver {
+	case "log":
+	case "smtp":
+		check(c.Mail.Host != "", "MAIL_HOST is required by the smtp driver")
+		check(validPort(c.Mail.Port), "MAIL_PORT must be between 1 and 65535")
+		check(c.Mail.From != "", "MAIL_FROM is required by the smtp driver")
+		check(c.Mail.Username == "" || c.Mail.Password != "", "MAIL_PASSWORD is required with MAIL_USERNAME")
+	default:
+		check(false, "MAIL_DRIVER must be smtp or log")
+	}
+
+	store := c.Storage
+	switch store.Driver {
+	case "local":
+		check(store.LocalDir != "", "STORAGE_LOCAL_DIR is required by the local driver")
+	case "s3":
+		check(store.S3Endpoint != "", "S3_ENDPOINT is required by the s3 driver")
+		check(store.S3Bucket != "", "S3_BUCKET is required by the s3 driver")
+		check(store.S3AccessKey != "" && store.S3SecretKey != "", "S3_ACCESS_KEY and S3_SECRET_KEY are required by the s3 driver")
+	case "cloudinary":
+		check(store.CloudinaryName != "" && store.CloudinaryKey != "" && store.CloudinarySecret != "",
+			"API_NAME, API_KEY and API_SECRET are required by the cloudinary driver")
+	default:
+		check(false, "STORAGE_DRIVER must be local, s3 or cloudinary")
+	}
+
+	check(c.Image.ThumbnailSize > 0, "IMAGE_THUMBNAIL_SIZE must be positive")
+	check(c.Image.MaxDimension >= c.Image.ThumbnailSize, "IMAGE_MAX_DIMENSION can't be smaller than IMAGE_THUMBNAIL_SIZE")
+	check(c.Image.Quality >= 1 && c.Image.Quality <= 100, "IMAGE_QUALITY must be between 1 and 100")
+	check(c.Image.DuplicateDistance >= 0 && c.Image.DuplicateDistance <= 32, "IMAGE_DUPLICATE_DISTANCE must be between 0 and 32")
+	check(c.Image.DuplicateWindow > 0, "IMAGE_DUPLICATE_WINDOW must be positive")
+
+	if (c.Prayer.Latitude == nil) != (c.Prayer.Longitude == nil) {
+		check(false, "PRAYER_LATITUDE and PRAYER_LONGITUDE must be set together")
+	} else {
+		_, err := c.Prayer.calculator()
+		check(err == nil, "invalid prayer time config: %v", err)
+	}
+	check(c.Prayer.AsrFactor == 1 || c.Prayer.AsrFactor == 2, "PRAYER_ASR_FACTOR must be 1 or 2")
+
+	for _, schedule := range []struct{ env, spec string }{
+		{"RESET_MONTHLY_SCHEDULE", c.Scheduler.MonthlyReset},
+		{"RESET_ANNUAL_SCHEDULE", c.Scheduler.AnnualReset},
+		{"TASK_SERIES_SCHEDULE", c.Scheduler.TaskSeries},
+		{"RELIGION_TASK_SCHEDULE", c.Scheduler.ReligionTask},
+	} {
+		if schedule.spec == "off" {
+			continue
+		}
+		_, err := scheduler.Parse(schedule.spec)
+		check(err == nil, "invalid %s: %v", schedule.env, err)
+	}
+	check(c.Scheduler.CatchUp >= 0, "RESET_CATCHUP can't be negative")
+	check(c.Scheduler.Retries >= 0, "JOB_RETRIES can't be negative")
+	check(c.Scheduler.RetryBackoff >= 0, "JOB_RETRY_BACKOFF can't be negative")
+	check(c.Scheduler.LockTTL > 0, "JOB_LOCK_TTL must be positive")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -1,25 +1,70 @@
 package database
 
 import (
+	"fmt"
 	"log"
-	"os"
+	"net"
+	"net/url"
+	"strconv"
+	"tugaskita/app/config"
 
-	"github.com/joho/godotenv"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func Init() *gorm.DB {
-	godotenv.Load(".env")
-	host := os.Getenv("DBHOST")
-	user := os.Getenv("DBUSER")
-	port := os.Getenv("DBPORT")
-	pass := os.Getenv("DBPASS")
-	name := os.Getenv("DBNAME")
+var defaultPorts = map[string]int{"mysql": 3306, "postgres": 5432}
 
-	dsn := user + ":" + pass + "@tcp(" + host + ":" + port + ")/" + name + "?charset=utf8mb4&parseTime=True&loc=Local"
+// Open connects to the database of cfg. SQLite needs no server, which
+// makes it handy for local development and tests.
+func Open(cfg config.Database) (*gorm.DB, error) {
+	port := cfg.Port
+	if port == 0 {
+		port = defaultPorts[cfg.Driver]
+	}
+
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case "mysql":
+		dsn := cfg.User + ":" + cfg.Password + "@tcp(" + cfg.Host + ":" + strconv.Itoa(port) + ")/" + cfg.Name + "?charset=utf8mb4&parseTime=True&loc=Local"
+		dialector = mysql.Open(dsn)
+	case "postgres":
+		//a URL escapes spaces and quotes in the password and other values
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.User, cfg.Password),
+			Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+			Path:     "/" + cfg.Name,
+			RawQuery: url.Values{"sslmode": {cfg.SSLMode}}.Encode(),
+		}
+		dialector = postgres.Open(dsn.String())
+	case "sqlite":
+		dialector = sqlite.Open(cfg.Name + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	//sqlite allows a single writer, and every connection to ":memory:"
+	//would open a database of its own
+	if cfg.Driver == "sqlite" {
+		sqlDB, errDB := db.DB()
+		if errDB != nil {
+			return nil, errDB
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	return db, nil
+}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+func Init(cfg config.Database) *gorm.DB {
+	db, err := Open(cfg)
 
 	if err != nil {
 		log.Fatalln(err)
@@ -0,0 +1,101 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+)
+
+// Usage describes the arguments of Command.
+const Usage = `usage: migrate <command>
+
+  status        list the migrations and when they were applied
+  up            apply every pending migration
+  down [steps]  roll back the last steps migrations, 1 by default
+  to <version>  apply or roll back until version, 0 rolls back everything`
+
+// Command runs the migrate subcommand with args and writes its report to
+// out.
+func Command(db *gorm.DB, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(Usage)
+	}
+
+	before, err := applied(db)
+	if err != nil {
+		return err
+	}
+
+	var ran []Migration
+	switch args[0] {
+	case "status":
+		if len(args) != 1 {
+			return errors.New(Usage)
+		}
+		return printStatus(db, out)
+	case "up":
+		if len(args) != 1 {
+			return errors.New(Usage)
+		}
+		ran, err = Up(db)
+	case "down":
+		steps := 1
+		if len(args) == 2 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return errors.New("steps must be a positive number")
+			}
+		} else if len(args) != 1 {
+			return errors.New(Usage)
+		}
+		ran, err = Down(db, steps)
+	case "to":
+		if len(args) != 2 {
+			return errors.New(Usage)
+		}
+		version, errVersion := strconv.Atoi(args[1])
+		if errVersion != nil || version < 0 {
+			return errors.New("version must be a number")
+		}
+		ran, err = To(db, version)
+	default:
+		return errors.New(Usage)
+	}
+
+	for _, v := range ran {
+		action := "applied"
+		if _, ok := before[v.Version]; ok {
+			action = "rolled back"
+		}
+		fmt.Fprintf(out, "%s %d %s\n", action, v.Version, v.Name)
+	}
+	if err == nil && len(ran) == 0 {
+		fmt.Fprintln(out, "nothing to migrate")
+	}
+	return err
+}
+
+func printStatus(db *gorm.DB, out io.Writer) error {
+	statuses, err := Statuses(db)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, v := range statuses {
+		applied := "pending"
+		if v.AppliedAt != nil {
+			applied = v.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if v.Unknown {
+			applied += " (unknown to this build)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", v.Version, v.Name, applied)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,512 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// initialSchema creates the tables that existed before versioned
+// migrations, and completes databases that were kept up to date by
+// AutoMigrate so they can switch to migrations. Columns whose type changed
+// since are created with their old type, the migrations changing them
+// check the schema first instead of assuming the old one.
+var initialSchema = Migration{
+	Version: 1,
+	Name:    "initial schema",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(initialTables...)
+	},
+	Down: func(tx *gorm.DB) error {
+		for i := len(initialTables) - 1; i >= 0; i-- {
+			if err := tx.Migrator().DropTable(initialTables[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var initialTables = []any{
+	&initialSchool{},
+	&initialClass{},
+	&initialUser{},
+	&initialUserPoint{},
+	&initialRefreshToken{},
+	&initialRevokedToken{},
+	&initialPasswordReset{},
+	&initialPointReset{},
+	&initialLeaderboardSnapshot{},
+	&initialLeaderboardEntry{},
+	&initialTask{},
+	&initialTaskSeries{},
+	&initialTaskUpload{},
+	&initialTaskSubmission{},
+	&initialReward{},
+	&initialRewardRequest{},
+	&initialPenalty{},
+	&initialReligionTask{},
+	&initialReligionTemplate{},
+	&initialReligionUpload{},
+	&initialReligionRequest{},
+	&initialRole{},
+	&initialPermission{},
+	&initialRolePermission{},
+	&initialParentStudent{},
+	&initialHoliday{},
+	&initialJobRun{},
+	&initialJobLock{},
+}
+
+type initialSchool struct {
+	Id        uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Name      string    `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Address   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (initialSchool) TableName() string {
+	return "schools"
+}
+
+type initialClass struct {
+	Id                uuid.UUID      `gorm:"type:varchar(50);primaryKey;not null"`
+	SchoolId          string         `gorm:"type:varchar(50);not null;uniqueIndex:idx_class_school_name_year"`
+	School            *initialSchool `gorm:"foreignKey:SchoolId;constraint:OnDelete:RESTRICT"`
+	Name              string         `gorm:"type:varchar(25);not null;uniqueIndex:idx_class_school_name_year"`
+	Grade             int            `gorm:"not null;index"`
+	AcademicYear      string         `gorm:"type:varchar(9);not null;uniqueIndex:idx_class_school_name_year"`
+	HomeroomTeacherId string         `gorm:"type:varchar(50);index"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (initialClass) TableName() string {
+	return "classes"
+}
+
+// initialUser keeps the points as text, integerPoints changes them.
+type initialUser struct {
+	ID                string `gorm:"type:varchar(50);primaryKey;not null"`
+	Name              string `gorm:"not null"`
+	Address           string
+	School            string
+	Class             string
+	SchoolId          *string        `gorm:"type:varchar(50);index"`
+	SchoolData        *initialSchool `gorm:"foreignKey:SchoolId;constraint:OnDelete:SET NULL"`
+	ClassId           *string        `gorm:"type:varchar(50);index"`
+	ClassData         *initialClass  `gorm:"foreignKey:ClassId;constraint:OnDelete:SET NULL"`
+	Image             string
+	Email             string `gorm:"not null"`
+	Password          string `gorm:"not null"`
+	Role              string `gorm:"not null"`
+	Religion          string
+	Point             string `gorm:"not null"`
+	TotalPoint        string `gorm:"not null"`
+	SessionsRevokedAt *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (initialUser) TableName() string {
+	return "users"
+}
+
+type initialUserPoint struct {
+	Id                string `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId            string `gorm:"type:varchar(50);index;not null"`
+	Type              string `gorm:"type:varchar(25);not null"`
+	TaskName          string
+	SourceId          string `gorm:"type:varchar(50);index"`
+	Point             int
+	PointDelta        int
+	TotalPointDelta   int
+	PointBalance      int
+	TotalPointBalance int
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (initialUserPoint) TableName() string {
+	return "user_points"
+}
+
+type initialRefreshToken struct {
+	Id         string `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId     string `gorm:"type:varchar(50);index;not null"`
+	FamilyId   string `gorm:"type:varchar(50);index;not null"`
+	TokenHash  string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ReplacedBy string `gorm:"type:varchar(50)"`
+	ExpiredAt  time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (initialRefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+type initialRevokedToken struct {
+	Id        string    `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId    string    `gorm:"type:varchar(50);index;not null"`
+	ExpiredAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (initialRevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+type initialPasswordReset struct {
+	Id             string `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId         string `gorm:"type:varchar(50);index;not null"`
+	CodeHash       string `gorm:"not null"`
+	ResetTokenHash string `gorm:"type:varchar(64);index"`
+	Attempts       int    `gorm:"not null;default:0"`
+	ExpiredAt      time.Time
+	VerifiedAt     *time.Time
+	UsedAt         *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (initialPasswordReset) TableName() string {
+	return "password_resets"
+}
+
+type initialPointReset struct {
+	Id           string `gorm:"type:varchar(50);primaryKey;not null"`
+	Kind         string `gorm:"type:varchar(25);not null;uniqueIndex:idx_point_reset_period"`
+	Period       string `gorm:"type:varchar(25);not null;uniqueIndex:idx_point_reset_period"`
+	Trigger      string `gorm:"type:varchar(25);not null"`
+	SnapshotId   string `gorm:"type:varchar(50)"`
+	UserCount    int
+	ClearedPoint int
+	CreatedAt    time.Time
+}
+
+func (initialPointReset) TableName() string {
+	return "point_resets"
+}
+
+type initialLeaderboardSnapshot struct {
+	Id        string `gorm:"type:varchar(50);primaryKey;not null"`
+	Kind      string `gorm:"type:varchar(25);not null;index"`
+	Period    string `gorm:"type:varchar(25);not null;index"`
+	CreatedAt time.Time
+}
+
+func (initialLeaderboardSnapshot) TableName() string {
+	return "leaderboard_snapshots"
+}
+
+type initialLeaderboardEntry struct {
+	Id         string `gorm:"type:varchar(50);primaryKey;not null"`
+	SnapshotId string `gorm:"type:varchar(50);not null;index"`
+	UserId     string `gorm:"type:varchar(50);not null;index"`
+	Name       string
+	SchoolId   string `gorm:"type:varchar(50)"`
+	ClassId    string `gorm:"type:varchar(50)"`
+	Class      string `gorm:"type:varchar(25)"`
+	Position   int    `gorm:"not null;index"`
+	Point      int
+	TotalPoint int
+}
+
+func (initialLeaderboardEntry) TableName() string {
+	return "leaderboard_entries"
+}
+
+// initialTask keeps the dates as text, taskDateColumns changes them.
+type initialTask struct {
+	ID             uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	AdminId        string
+	Title          string `gorm:"not null"`
+	Description    string
+	Point          int
+	Message        string
+	Status         string `gorm:"type:varchar(20);default:'Active'"`
+	Type           string `gorm:"default:'Task'"`
+	Start_date     string
+	End_date       string
+	SeriesId       *string `gorm:"type:varchar(50);uniqueIndex:idx_task_series_occurrence"`
+	OccurrenceDate string  `gorm:"type:varchar(10);uniqueIndex:idx_task_series_occurrence"`
+	Detached       bool    `gorm:"not null;default:false"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (initialTask) TableName() string {
+	return "tasks"
+}
+
+type initialTaskSeries struct {
+	Id             uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	AdminId        string
+	Title          string `gorm:"not null"`
+	Description    string
+	Point          int
+	Rule           string `gorm:"type:varchar(255);not null"`
+	StartDate      string `gorm:"type:varchar(10);not null"`
+	DurationDays   int    `gorm:"not null;default:1"`
+	GeneratedUntil string `gorm:"type:varchar(10)"`
+	Status         string `gorm:"type:varchar(20);default:'Active'"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (initialTaskSeries) TableName() string {
+	return "task_series"
+}
+
+type initialTaskUpload struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	TaskId      string
+	UserId      string
+	Image       string
+	Description string
+	Status      string `gorm:"type:varchar(20);default:'Perlu Review'"`
+	Type        string `gorm:"default:'Task'"`
+	Message     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (initialTaskUpload) TableName() string {
+	return "user_task_uploads"
+}
+
+type initialTaskSubmission struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Title       string
+	UserId      string
+	Image       string
+	Type        string `gorm:"type:varchar(20);default:'Submission'"`
+	Description string
+	Point       int
+	Status      string `gorm:"type:varchar(20);default:'Perlu Review'"`
+	Message     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (initialTaskSubmission) TableName() string {
+	return "user_task_submissions"
+}
+
+type initialReward struct {
+	ID        uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Name      string
+	Stock     int
+	Price     int
+	Image     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (initialReward) TableName() string {
+	return "rewards"
+}
+
+type initialRewardRequest struct {
+	Id         uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	RewardId   string
+	Price      int
+	Amount     int
+	TotalPrice int
+	UserId     string
+	Status     string `gorm:"type:varchar(20);default:'Perlu Review'"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (initialRewardRequest) TableName() string {
+	return "user_reward_requests"
+}
+
+type initialPenalty struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId      string
+	Point       int
+	Description string
+	Date        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (initialPenalty) TableName() string {
+	return "penalties"
+}
+
+// initialReligionTask keeps the dates as text, taskDateColumns changes
+// them.
+type initialReligionTask struct {
+	Id             uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Title          string
+	Description    string
+	Religion       string
+	Type           string `gorm:"type:varchar(20);default:'Religion'"`
+	Point          int
+	Start_date     string
+	End_date       string
+	TemplateId     *string `gorm:"type:varchar(50);uniqueIndex:idx_religion_task_template_occurrence"`
+	OccurrenceDate string  `gorm:"type:varchar(10);uniqueIndex:idx_religion_task_template_occurrence"`
+	StartAt        *time.Time
+	EndAt          *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (initialReligionTask) TableName() string {
+	return "religion_tasks"
+}
+
+type initialReligionTemplate struct {
+	Id           uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Religion     string    `gorm:"type:varchar(25);not null;index"`
+	Title        string    `gorm:"not null"`
+	Description  string
+	Point        int
+	Rule         string `gorm:"type:varchar(255);not null"`
+	StartDate    string `gorm:"type:varchar(10);not null"`
+	DurationDays int    `gorm:"not null;default:0"`
+	PrayerWindow string `gorm:"type:varchar(10)"`
+	Holiday      string `gorm:"type:varchar(50);not null;default:''"`
+	Active       bool   `gorm:"not null;default:true"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (initialReligionTemplate) TableName() string {
+	return "religion_task_templates"
+}
+
+type initialReligionUpload struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	TaskId      string
+	UserId      string
+	Image       string
+	Type        string `gorm:"type:varchar(20);default:'Religion'"`
+	Description string
+	Status      string `gorm:"type:varchar(20);default:'Perlu Review'"`
+	Message     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (initialReligionUpload) TableName() string {
+	return "user_religion_task_uploads"
+}
+
+type initialReligionRequest struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Title       string
+	UserId      string
+	Image       string
+	Type        string `gorm:"type:varchar(20);default:'Religion Request'"`
+	Description string
+	Point       int
+	Status      string `gorm:"type:varchar(20);default:'Perlu Review'"`
+	Message     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (initialReligionRequest) TableName() string {
+	return "user_religion_req_tasks"
+}
+
+type initialRole struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Name        string    `gorm:"type:varchar(25);uniqueIndex;not null"`
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (initialRole) TableName() string {
+	return "roles"
+}
+
+type initialPermission struct {
+	Name        string `gorm:"type:varchar(50);primaryKey;not null"`
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (initialPermission) TableName() string {
+	return "permissions"
+}
+
+type initialRolePermission struct {
+	RoleId         uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	PermissionName string    `gorm:"type:varchar(50);primaryKey;not null"`
+}
+
+func (initialRolePermission) TableName() string {
+	return "role_permissions"
+}
+
+type initialParentStudent struct {
+	Id        uuid.UUID    `gorm:"type:varchar(50);primaryKey;not null"`
+	ParentId  string       `gorm:"type:varchar(50);not null;uniqueIndex:idx_parent_student"`
+	Parent    *initialUser `gorm:"foreignKey:ParentId;constraint:OnDelete:CASCADE"`
+	StudentId string       `gorm:"type:varchar(50);not null;uniqueIndex:idx_parent_student;index"`
+	Student   *initialUser `gorm:"foreignKey:StudentId;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time
+}
+
+func (initialParentStudent) TableName() string {
+	return "parent_students"
+}
+
+type initialHoliday struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Key         string    `gorm:"column:holiday_key;type:varchar(50);not null;uniqueIndex:idx_holiday_key_period"`
+	Period      string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_holiday_key_period"`
+	Name        string    `gorm:"type:varchar(100);not null"`
+	Religion    string    `gorm:"type:varchar(25);not null;index"`
+	StartDate   string    `gorm:"type:varchar(10);not null;index"`
+	EndDate     string    `gorm:"type:varchar(10);not null"`
+	Description string
+	Computed    bool `gorm:"not null;default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (initialHoliday) TableName() string {
+	return "holidays"
+}
+
+type initialJobRun struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	Name        string    `gorm:"type:varchar(100);not null;index"`
+	ScheduledAt time.Time `gorm:"not null;index"`
+	Attempt     int       `gorm:"not null"`
+	Status      string    `gorm:"type:varchar(20);not null;index"`
+	Created     int       `gorm:"not null;default:0"`
+	Error       string    `gorm:"type:text"`
+	Holder      string    `gorm:"type:varchar(100)"`
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
+
+func (initialJobRun) TableName() string {
+	return "job_runs"
+}
+
+type initialJobLock struct {
+	Name        string `gorm:"type:varchar(100);primaryKey;not null"`
+	Holder      string `gorm:"type:varchar(100);not null"`
+	Activation  string `gorm:"type:varchar(35);not null"`
+	Done        bool   `gorm:"not null;default:false"`
+	LockedUntil time.Time
+}
+
+func (initialJobLock) TableName() string {
+	return "job_locks"
+}
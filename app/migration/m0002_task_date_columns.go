@@ -0,0 +1,95 @@
+package migration
+
+import (
+	"strings"
+	task "tugaskita/features/task/model"
+
+	"gorm.io/gorm"
+)
+
+// taskDateColumns turns the start and end dates of tasks into YYYY-MM-DD
+// columns. Queries compare them as strings, so values written with a time
+// are cut to their date first.
+var taskDateColumns = Migration{
+	Version: 2,
+	Name:    "task date columns",
+	Up: func(tx *gorm.DB) error {
+		return alterTaskDates(tx, &taskDates{}, true)
+	},
+	Down: func(tx *gorm.DB) error {
+		return alterTaskDates(tx, &taskDatesText{}, false)
+	},
+}
+
+type taskDates struct {
+	Start_date string `gorm:"type:varchar(10)"`
+	End_date   string `gorm:"type:varchar(10)"`
+}
+
+type taskDatesText struct {
+	Start_date string `gorm:"type:text"`
+	End_date   string `gorm:"type:text"`
+}
+
+// taskDateTables are the tables with date columns and the index SQLite
+// loses when it rebuilds them to alter a column.
+var taskDateTables = []struct {
+	model any
+	index string
+}{
+	{&task.Task{}, "idx_task_series_occurrence"},
+	{&task.ReligionTask{}, "idx_religion_task_template_occurrence"},
+}
+
+func isDateColumn(tx *gorm.DB, model any, column string) (bool, error) {
+	columns, err := tx.Migrator().ColumnTypes(model)
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range columns {
+		if v.Name() != column {
+			continue
+		}
+		length, _ := v.Length()
+		return strings.Contains(strings.ToLower(v.DatabaseTypeName()), "char") && length == 10, nil
+	}
+	return false, nil
+}
+
+func alterTaskDates(tx *gorm.DB, columns any, up bool) error {
+	for _, table := range taskDateTables {
+		for _, column := range []string{"start_date", "end_date"} {
+			if up {
+				err := tx.Model(table.model).Where("LENGTH("+column+") > 10").
+					UpdateColumn(column, gorm.Expr("SUBSTR("+column+", 1, 10)")).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			done, err := isDateColumn(tx, table.model, column)
+			if err != nil {
+				return err
+			}
+			if done == up {
+				continue
+			}
+
+			stmt := &gorm.Statement{DB: tx}
+			if err := stmt.Parse(table.model); err != nil {
+				return err
+			}
+			if err := tx.Table(stmt.Table).Migrator().AlterColumn(columns, column); err != nil {
+				return err
+			}
+		}
+
+		if !tx.Migrator().HasIndex(table.model, table.index) {
+			if err := tx.Migrator().CreateIndex(table.model, table.index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package migration
+
+import "gorm.io/gorm"
+
+// imageThumbnails adds the URL of the thumbnail next to the uploaded
+// images. Images uploaded before have none, their thumbnail is the image
+// itself so clients can always show it.
+var imageThumbnails = Migration{
+	Version: 3,
+	Name:    "image thumbnails",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range thumbnailTables {
+			if !tx.Migrator().HasColumn(table, "thumbnail") {
+				if err := tx.Table(table).Migrator().AddColumn(&thumbnail{}, "Thumbnail"); err != nil {
+					return err
+				}
+			}
+
+			err := tx.Table(table).Where("thumbnail IS NULL OR thumbnail = ''").
+				UpdateColumn("thumbnail", gorm.Expr("image")).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range thumbnailTables {
+			if !tx.Migrator().HasColumn(table, "thumbnail") {
+				continue
+			}
+			err := keepIndexes(tx, table, func() error {
+				return tx.Table(table).Migrator().DropColumn(&thumbnail{}, "Thumbnail")
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+type thumbnail struct {
+	Thumbnail string
+}
+
+var thumbnailTables = []string{
+	"users",
+	"rewards",
+	"user_task_uploads",
+	"user_task_submissions",
+	"user_religion_task_uploads",
+	"user_religion_req_tasks",
+}
+
+// keepIndexes runs fn and creates the indexes of table again that it
+// dropped. SQLite rebuilds a table to drop or alter a column and loses
+// them, dropping the old table also runs the foreign keys referencing it,
+// so the rows of those tables are restored as well.
+func keepIndexes(tx *gorm.DB, table string, fn func() error) error {
+	if tx.Dialector.Name() != "sqlite" {
+		return fn()
+	}
+
+	var references []string
+	err := tx.Raw("SELECT DISTINCT m.name FROM sqlite_master m, pragma_foreign_key_list(m.name) f WHERE m.type = 'table' AND f.\"table\" = ?", table).
+		Scan(&references).Error
+	if err != nil {
+		return err
+	}
+
+	for _, v := range references {
+		if err := tx.Exec("CREATE TEMP TABLE `" + v + "__kept` AS SELECT * FROM `" + v + "`").Error; err != nil {
+			return err
+		}
+	}
+
+	var indexes []struct {
+		Name string
+		SQL  string
+	}
+	err = tx.Raw("SELECT name, sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", table).
+		Scan(&indexes).Error
+	if err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		if tx.Migrator().HasIndex(table, index.Name) {
+			continue
+		}
+		if err := tx.Exec(index.SQL).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, v := range references {
+		if err := tx.Exec("INSERT OR REPLACE INTO `" + v + "` SELECT * FROM `" + v + "__kept`").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DROP TABLE `" + v + "__kept`").Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
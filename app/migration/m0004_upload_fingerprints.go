@@ -0,0 +1,55 @@
+package migration
+
+import "gorm.io/gorm"
+
+// uploadFingerprints adds the fingerprints of proof images that reveal
+// photos reused for several uploads. Earlier uploads have none and are
+// not compared.
+var uploadFingerprints = Migration{
+	Version: 4,
+	Name:    "upload fingerprints",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range fingerprintTables {
+			for _, column := range fingerprintColumns {
+				if tx.Table(table).Migrator().HasColumn(&fingerprint{}, column) {
+					continue
+				}
+				if err := tx.Table(table).Migrator().AddColumn(&fingerprint{}, column); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range fingerprintTables {
+			err := keepIndexes(tx, table, func() error {
+				for _, column := range fingerprintColumns {
+					if !tx.Table(table).Migrator().HasColumn(&fingerprint{}, column) {
+						continue
+					}
+					if err := tx.Table(table).Migrator().DropColumn(&fingerprint{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+type fingerprint struct {
+	ImageHash         string `gorm:"type:varchar(64)"`
+	ImageDhash        string `gorm:"type:varchar(16)"`
+	DuplicateOf       string `gorm:"type:varchar(50)"`
+	DuplicateType     string `gorm:"type:varchar(20)"`
+	DuplicateDistance int    `gorm:"not null;default:0"`
+}
+
+var fingerprintColumns = []string{"ImageHash", "ImageDhash", "DuplicateOf", "DuplicateType", "DuplicateDistance"}
+
+var fingerprintTables = []string{"user_task_uploads", "user_religion_task_uploads"}
@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// submissionReviews adds the review history of submissions. Reviewers
+// could set any status before, those outside of the review workflow are
+// sent back to review.
+var submissionReviews = Migration{
+	Version: 5,
+	Name:    "submission reviews",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().AutoMigrate(&submissionRevision{}); err != nil {
+			return err
+		}
+
+		for _, table := range submissionTables {
+			err := tx.Table(table).
+				Where("status IS NULL OR status NOT IN ?", []string{"Perlu Review", "Perlu Revisi", "Direvisi", "Diterima", "Ditolak"}).
+				Update("status", "Perlu Review").Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range submissionTables {
+			err := tx.Table(table).
+				Where("status IN ?", []string{"Perlu Revisi", "Direvisi"}).
+				Update("status", "Perlu Review").Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropTable(&submissionRevision{})
+	},
+}
+
+type submissionRevision struct {
+	Id           string `gorm:"type:varchar(50);primaryKey;not null"`
+	SubmissionId string `gorm:"type:varchar(50);not null;index"`
+	Type         string `gorm:"type:varchar(20);not null"`
+	UserId       string `gorm:"type:varchar(50)"`
+	FromStatus   string `gorm:"type:varchar(20)"`
+	Status       string `gorm:"type:varchar(20);not null"`
+	Message      string
+	Image        string
+	Thumbnail    string
+	Description  string
+	CreatedAt    time.Time
+}
+
+func (submissionRevision) TableName() string {
+	return "submission_revisions"
+}
+
+var submissionTables = []string{"user_task_uploads", "user_task_submissions", "user_religion_task_uploads", "user_religion_req_tasks"}
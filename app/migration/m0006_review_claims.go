@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reviewClaims adds the claims reviewers take on the review queue.
+var reviewClaims = Migration{
+	Version: 6,
+	Name:    "review claims",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(&reviewClaim{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&reviewClaim{})
+	},
+}
+
+type reviewClaim struct {
+	SubmissionId string    `gorm:"type:varchar(50);primaryKey;not null"`
+	Type         string    `gorm:"type:varchar(20);primaryKey;not null"`
+	ReviewerId   string    `gorm:"type:varchar(50);not null;index"`
+	ExpiredAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+func (reviewClaim) TableName() string {
+	return "review_claims"
+}
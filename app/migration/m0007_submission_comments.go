@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// submissionComments adds the comment threads of submissions and how far
+// each user read them.
+var submissionComments = Migration{
+	Version: 7,
+	Name:    "submission comments",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(&submissionComment{}, &submissionCommentRead{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&submissionCommentRead{}, &submissionComment{})
+	},
+}
+
+type submissionComment struct {
+	Id           uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	SubmissionId string    `gorm:"type:varchar(50);not null;index"`
+	Type         string    `gorm:"type:varchar(20);not null"`
+	UserId       string    `gorm:"type:varchar(50);not null"`
+	Body         string    `gorm:"type:text"`
+	Image        string
+	Thumbnail    string
+	CreatedAt    time.Time
+}
+
+func (submissionComment) TableName() string {
+	return "submission_comments"
+}
+
+type submissionCommentRead struct {
+	SubmissionId string    `gorm:"type:varchar(50);primaryKey;not null"`
+	Type         string    `gorm:"type:varchar(20);primaryKey;not null"`
+	UserId       string    `gorm:"type:varchar(50);primaryKey;not null"`
+	ReadAt       time.Time `gorm:"not null"`
+}
+
+func (submissionCommentRead) TableName() string {
+	return "submission_comment_reads"
+}
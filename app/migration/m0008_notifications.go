@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// notifications adds the in-app notifications of users.
+var notifications = Migration{
+	Version: 8,
+	Name:    "notifications",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AutoMigrate(&userNotification{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&userNotification{})
+	},
+}
+
+type userNotification struct {
+	Id        uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId    string    `gorm:"type:varchar(50);not null;index"`
+	Type      string    `gorm:"type:varchar(50);not null"`
+	Title     string    `gorm:"type:varchar(100);not null"`
+	Message   string    `gorm:"type:text"`
+	RefType   string    `gorm:"type:varchar(20)"`
+	RefId     string    `gorm:"type:varchar(50)"`
+	ReadAt    *time.Time
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (userNotification) TableName() string {
+	return "notifications"
+}
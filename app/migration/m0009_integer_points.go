@@ -0,0 +1,211 @@
+package migration
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// integerPoints turns the point balances of users into integer columns,
+// values that aren't a number are set to 0 first. Point history entries
+// written before the ledger have no deltas and balances, the deltas are
+// derived from their type and the balances walked back from the current
+// balance of the user.
+var integerPoints = Migration{
+	Version: 9,
+	Name:    "integer points",
+	Up: func(tx *gorm.DB) error {
+		done, err := isIntegerColumn(tx, &userBalance{}, "point")
+		if err != nil {
+			return err
+		}
+
+		if !done {
+			if err := cleanBalances(tx); err != nil {
+				return err
+			}
+			if err := alterBalances(tx, &userBalance{}); err != nil {
+				return err
+			}
+		}
+
+		return backfillLedger(tx)
+	},
+	Down: func(tx *gorm.DB) error {
+		done, err := isIntegerColumn(tx, &userBalance{}, "point")
+		if err != nil || !done {
+			return err
+		}
+		return alterBalances(tx, &userBalanceText{})
+	},
+}
+
+type userBalance struct {
+	Point      int `gorm:"not null;default:0"`
+	TotalPoint int `gorm:"not null;default:0"`
+}
+
+func (userBalance) TableName() string {
+	return "users"
+}
+
+type userBalanceText struct {
+	Point      string `gorm:"not null"`
+	TotalPoint string `gorm:"not null"`
+}
+
+func (userBalanceText) TableName() string {
+	return "users"
+}
+
+// legacyEntry matches the entries without deltas, the columns were added
+// to the ledger without a default.
+const legacyEntry = "COALESCE(point_delta, 0) = 0 AND COALESCE(total_point_delta, 0) = 0 AND point <> 0"
+
+type ledgerEntry struct {
+	Id                string
+	UserId            string
+	Type              string
+	Point             int
+	PointDelta        int
+	TotalPointDelta   int
+	PointBalance      int
+	TotalPointBalance int
+	CreatedAt         time.Time
+}
+
+func (ledgerEntry) TableName() string {
+	return "user_points"
+}
+
+func isIntegerColumn(tx *gorm.DB, model any, column string) (bool, error) {
+	columns, err := tx.Migrator().ColumnTypes(model)
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range columns {
+		if v.Name() == column {
+			return strings.Contains(strings.ToLower(v.DatabaseTypeName()), "int"), nil
+		}
+	}
+	return false, nil
+}
+
+func alterBalances(tx *gorm.DB, columns any) error {
+	return keepIndexes(tx, "users", func() error {
+		for _, column := range []string{"Point", "TotalPoint"} {
+			if err := tx.Migrator().AlterColumn(columns, column); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// pointValue reads a balance stored as text, decimals are cut and
+// anything else is 0.
+func pointValue(value *string) int {
+	if value == nil {
+		return 0
+	}
+
+	text := strings.TrimSpace(*value)
+	if point, err := strconv.Atoi(text); err == nil {
+		return point
+	}
+	if point, err := strconv.ParseFloat(text, 64); err == nil {
+		return int(point)
+	}
+	return 0
+}
+
+// cleanBalances rewrites the balances that aren't plain integers so the
+// columns can be altered on every database.
+func cleanBalances(tx *gorm.DB) error {
+	var rows []struct {
+		Id         string
+		Point      *string
+		TotalPoint *string
+	}
+	err := tx.Table("users").Select("id, point, total_point").Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, v := range rows {
+		point, totalPoint := strconv.Itoa(pointValue(v.Point)), strconv.Itoa(pointValue(v.TotalPoint))
+		if v.Point != nil && *v.Point == point && v.TotalPoint != nil && *v.TotalPoint == totalPoint {
+			continue
+		}
+
+		err := tx.Table("users").Where("id = ?", v.Id).
+			UpdateColumns(map[string]any{"point": point, "total_point": totalPoint}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyDeltas are the changes an entry without deltas made to the
+// balances. Rewards were paid from the total point only.
+func legacyDeltas(entry ledgerEntry) (int, int) {
+	switch entry.Type {
+	case "Penalty":
+		return -entry.Point, -entry.Point
+	case "Reward":
+		return 0, -entry.Point
+	default:
+		return entry.Point, entry.Point
+	}
+}
+
+// backfillLedger sets the deltas and balances of the entries written before
+// the ledger. They are walked from the newest entry of a user back, the
+// newest balance being the current one, so the history ends at the
+// balance users see even where old changes were never recorded.
+func backfillLedger(tx *gorm.DB) error {
+	var userIds []string
+	err := tx.Model(&ledgerEntry{}).Where(legacyEntry).Distinct().Pluck("user_id", &userIds).Error
+	if err != nil {
+		return err
+	}
+
+	for _, userId := range userIds {
+		var balance userBalance
+		err := tx.Model(&userBalance{}).Select("point, total_point").Where("id = ?", userId).Scan(&balance).Error
+		if err != nil {
+			return err
+		}
+
+		var entries []ledgerEntry
+		err = tx.Where("user_id = ?", userId).Order("created_at desc, id desc").Find(&entries).Error
+		if err != nil {
+			return err
+		}
+
+		point, totalPoint := balance.Point, balance.TotalPoint
+		for _, v := range entries {
+			if v.PointDelta == 0 && v.TotalPointDelta == 0 && v.Point != 0 {
+				v.PointDelta, v.TotalPointDelta = legacyDeltas(v)
+				v.PointBalance, v.TotalPointBalance = point, totalPoint
+
+				err := tx.Model(&ledgerEntry{}).Where("id = ?", v.Id).UpdateColumns(map[string]any{
+					"point_delta":         v.PointDelta,
+					"total_point_delta":   v.TotalPointDelta,
+					"point_balance":       v.PointBalance,
+					"total_point_balance": v.TotalPointBalance,
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			point, totalPoint = v.PointBalance-v.PointDelta, v.TotalPointBalance-v.TotalPointDelta
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package migration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:?_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+func TestIntegerPointsBackfillsLedger(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := To(db, integerPoints.Version-1); err != nil {
+		t.Fatal(err)
+	}
+
+	users := []initialUser{
+		{ID: "student", Name: "Student", Email: "student@x.id", Role: "user", Point: "90", TotalPoint: "50"},
+		{ID: "decimal", Name: "Decimal", Email: "decimal@x.id", Role: "user", Point: " 12.7", TotalPoint: "abc"},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	//entries written before the ledger have no deltas nor balances
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	legacy := []struct {
+		id, kind string
+		point    int
+	}{
+		{"e1", "Task", 50},
+		{"e2", "Religion", 50},
+		{"e3", "Penalty", 10},
+		{"e4", "Reward", 40},
+	}
+	for i, v := range legacy {
+		err := db.Exec("INSERT INTO user_points (id, user_id, type, point, point_delta, total_point_delta, created_at) VALUES (?, ?, ?, ?, NULL, NULL, ?)",
+			v.id, "student", v.kind, v.point, start.Add(time.Duration(i)*time.Hour)).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := To(db, integerPoints.Version); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][4]int{
+		"e1": {50, 50, 50, 50},
+		"e2": {50, 50, 100, 100},
+		"e3": {-10, -10, 90, 90},
+		"e4": {0, -40, 90, 50},
+	}
+	var entries []ledgerEntry
+	if err := db.Order("created_at").Find(&entries).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for _, v := range entries {
+		got := [4]int{v.PointDelta, v.TotalPointDelta, v.PointBalance, v.TotalPointBalance}
+		if got != want[v.Id] {
+			t.Errorf("entry %s: deltas and balances %v, want %v", v.Id, got, want[v.Id])
+		}
+	}
+
+	//the newest entry ends at the balance of the user
+	last := entries[len(entries)-1]
+	var balance userBalance
+	if err := db.Where("id = ?", "student").Take(&balance).Error; err != nil {
+		t.Fatal(err)
+	}
+	if last.PointBalance != balance.Point || last.TotalPointBalance != balance.TotalPoint {
+		t.Errorf("last balances %d/%d, user balances %d/%d", last.PointBalance, last.TotalPointBalance, balance.Point, balance.TotalPoint)
+	}
+
+	if err := db.Where("id = ?", "decimal").Take(&balance).Error; err != nil {
+		t.Fatal(err)
+	}
+	if balance.Point != 12 || balance.TotalPoint != 0 {
+		t.Errorf("balances of decimal are %d/%d, want 12/0", balance.Point, balance.TotalPoint)
+	}
+
+	done, err := isIntegerColumn(db, &userBalance{}, "point")
+	if err != nil || !done {
+		t.Errorf("point column isn't an integer column, err %v", err)
+	}
+}
+
+func TestIntegerPointsKeepsLedgerEntries(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := To(db, integerPoints.Version-1); err != nil {
+		t.Fatal(err)
+	}
+
+	user := initialUser{ID: "student", Name: "Student", Email: "student@x.id", Role: "user", Point: "30", TotalPoint: "30"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	//an entry written by the ledger is left as it is, the legacy entry
+	//before it is walked back from its balances
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := db.Exec("INSERT INTO user_points (id, user_id, type, point, created_at) VALUES (?, ?, ?, ?, ?)",
+		"old", "student", "Task", 20, start).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Exec("INSERT INTO user_points (id, user_id, type, point, point_delta, total_point_delta, point_balance, total_point_balance, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		"new", "student", "Task", 10, 10, 10, 30, 30, start.Add(time.Hour)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := To(db, integerPoints.Version); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []ledgerEntry
+	if err := db.Order("created_at").Find(&entries).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][4]int{{20, 20, 20, 20}, {10, 10, 30, 30}}
+	for i, v := range entries {
+		got := [4]int{v.PointDelta, v.TotalPointDelta, v.PointBalance, v.TotalPointBalance}
+		if got != want[i] {
+			t.Errorf("entry %s: deltas and balances %v, want %v", v.Id, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fingerprintIndexes indexes the uploads by image hash, to find the same
+// file, and by creation time, to compare the difference hashes of recent
+// uploads only.
+var fingerprintIndexes = Migration{
+	Version: 10,
+	Name:    "fingerprint indexes",
+	Up: func(tx *gorm.DB) error {
+		return createIndexes(tx, fingerprintTables, "image_hash", "created_at")
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropIndexes(tx, fingerprintTables, "image_hash", "created_at")
+	},
+}
+
+// createIndexes creates the single column indexes of tables that are
+// missing, named like the index tag of gorm names them.
+func createIndexes(tx *gorm.DB, tables []string, columns ...string) error {
+	for _, table := range tables {
+		for _, column := range columns {
+			name := "idx_" + table + "_" + column
+			if tx.Migrator().HasIndex(table, name) {
+				continue
+			}
+			err := tx.Exec("CREATE INDEX ? ON ? (?)", clause.Column{Name: name}, clause.Table{Name: table}, clause.Column{Name: column}).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func dropIndexes(tx *gorm.DB, tables []string, columns ...string) error {
+	for _, table := range tables {
+		for _, column := range columns {
+			name := "idx_" + table + "_" + column
+			if !tx.Migrator().HasIndex(table, name) {
+				continue
+			}
+			if err := tx.Migrator().DropIndex(table, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
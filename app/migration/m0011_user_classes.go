@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// userClasses links the users that only have a school and class name to
+// the school and class rows with those names. Nothing is created, names
+// without a row are left for admins to assign. Rolling back keeps the
+// links, they are valid in the earlier schema.
+var userClasses = Migration{
+	Version: 11,
+	Name:    "user classes",
+	Up: func(tx *gorm.DB) error {
+		var names []struct {
+			School string
+			Class  string
+		}
+		err := tx.Table("users").Select("school, COALESCE(class, '') AS class").
+			Where("school_id IS NULL AND school IS NOT NULL AND school <> ''").
+			Group("school, COALESCE(class, '')").Scan(&names).Error
+		if err != nil {
+			return err
+		}
+
+		for _, v := range names {
+			schoolId, classId, err := linkUserClass(tx, v.School, v.Class)
+			if err != nil {
+				return err
+			}
+			if schoolId == "" {
+				continue
+			}
+
+			values := map[string]any{"school_id": schoolId}
+			if classId != "" {
+				values["class_id"] = classId
+			}
+			err = tx.Table("users").Where("school_id IS NULL AND school = ? AND COALESCE(class, '') = ?", v.School, v.Class).
+				UpdateColumns(values).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return nil
+	},
+}
+
+// linkUserClass returns the ids of the school and class with the names,
+// empty when there is none.
+func linkUserClass(tx *gorm.DB, schoolName string, className string) (string, string, error) {
+	schoolName, className = strings.TrimSpace(schoolName), strings.TrimSpace(className)
+
+	var schools []initialSchool
+	err := tx.Where("LOWER(name) = LOWER(?)", schoolName).Limit(1).Find(&schools).Error
+	if err != nil || len(schools) == 0 {
+		return "", "", err
+	}
+	schoolId := schools[0].Id.String()
+
+	if className == "" {
+		return schoolId, "", nil
+	}
+
+	var classes []initialClass
+	err = tx.Where("school_id = ? AND LOWER(name) = LOWER(?)", schoolId, className).
+		Order("academic_year desc").Limit(1).Find(&classes).Error
+	if err != nil || len(classes) == 0 {
+		return schoolId, "", err
+	}
+
+	return schoolId, classes[0].Id.String(), nil
+}
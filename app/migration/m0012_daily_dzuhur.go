@@ -0,0 +1,23 @@
+package migration
+
+import "gorm.io/gorm"
+
+// dailyDzuhur makes the seeded Dzuhur template daily again, it was left
+// out on Friday although Jum'at doesn't replace it for every student.
+// Templates whose rule was changed by an admin are kept.
+var dailyDzuhur = Migration{
+	Version: 12,
+	Name:    "daily dzuhur",
+	Up: func(tx *gorm.DB) error {
+		return updateDzuhurRule(tx, "FREQ=WEEKLY;BYDAY=SA,SU,MO,TU,WE,TH", "FREQ=DAILY")
+	},
+	Down: func(tx *gorm.DB) error {
+		return updateDzuhurRule(tx, "FREQ=DAILY", "FREQ=WEEKLY;BYDAY=SA,SU,MO,TU,WE,TH")
+	},
+}
+
+func updateDzuhurRule(tx *gorm.DB, from string, to string) error {
+	return tx.Table("religion_task_templates").
+		Where("religion = ? AND title = ? AND holiday = '' AND rule = ?", "Islam", "Dzuhur", from).
+		UpdateColumn("rule", to).Error
+}
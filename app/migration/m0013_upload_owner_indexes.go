@@ -0,0 +1,36 @@
+package migration
+
+import "gorm.io/gorm"
+
+// uploadOwnerIndexes indexes the uploads by task and by student, similar
+// images are looked for among the recent uploads of the same task and of
+// the same student. The columns become varchar first, MySQL can't index
+// text.
+var uploadOwnerIndexes = Migration{
+	Version: 13,
+	Name:    "upload owner indexes",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range fingerprintTables {
+			err := keepIndexes(tx, table, func() error {
+				for _, column := range []string{"TaskId", "UserId"} {
+					if err := tx.Table(table).Migrator().AlterColumn(&uploadOwner{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return createIndexes(tx, fingerprintTables, "task_id", "user_id")
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropIndexes(tx, fingerprintTables, "task_id", "user_id")
+	},
+}
+
+type uploadOwner struct {
+	TaskId string `gorm:"type:varchar(50)"`
+	UserId string `gorm:"type:varchar(50)"`
+}
@@ -1,24 +1,66 @@
 package migration
 
 import (
+	"errors"
+	calendar "tugaskita/features/calendar/model"
+	job "tugaskita/features/job/model"
+	notification "tugaskita/features/notification/model"
+	parent "tugaskita/features/parent/model"
 	penalty "tugaskita/features/penalty/model"
 	reward "tugaskita/features/reward/model"
+	role "tugaskita/features/role/model"
+	school "tugaskita/features/school/model"
 	task "tugaskita/features/task/model"
 	users "tugaskita/features/user/model"
 
 	"gorm.io/gorm"
 )
 
-func InitMigration(db *gorm.DB) {
-	db.AutoMigrate(&users.Users{})
-	db.AutoMigrate(&users.UserPoint{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&task.UserTaskUpload{})
-	db.AutoMigrate(&task.UserTaskSubmission{})
-	db.AutoMigrate(&reward.Reward{})
-	db.AutoMigrate(&reward.UserRewardRequest{})
-	db.AutoMigrate(&penalty.Penalty{})
-	db.AutoMigrate(&task.ReligionTask{})
-	db.AutoMigrate(&task.UserReligionTaskUpload{})
-	db.AutoMigrate(&task.UserReligionReqTask{})
+// models are every table of the application in creation order.
+var models = []any{
+	&school.School{},
+	&school.Class{},
+	&users.Users{},
+	&users.UserPoint{},
+	&users.RefreshToken{},
+	&users.RevokedToken{},
+	&users.PasswordReset{},
+	&users.PointReset{},
+	&users.LeaderboardSnapshot{},
+	&users.LeaderboardEntry{},
+	&task.Task{},
+	&task.TaskSeries{},
+	&task.UserTaskUpload{},
+	&task.UserTaskSubmission{},
+	&reward.Reward{},
+	&reward.UserRewardRequest{},
+	&penalty.Penalty{},
+	&task.ReligionTask{},
+	&task.ReligionTaskTemplate{},
+	&task.UserReligionTaskUpload{},
+	&task.UserReligionReqTask{},
+	&role.Role{},
+	&role.Permission{},
+	&role.RolePermission{},
+	&parent.ParentStudent{},
+	&calendar.Holiday{},
+	&job.JobRun{},
+	&job.JobLock{},
+	&task.SubmissionRevision{},
+	&task.ReviewClaim{},
+	&task.SubmissionComment{},
+	&task.SubmissionCommentRead{},
+	&notification.Notification{},
+}
+
+// AutoMigrate creates and alters the tables to match the models. It can't
+// change column types safely nor migrate data, so it is only used in
+// development, deployed databases are changed by the versioned migrations.
+func AutoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(models...)
+}
+
+// Seed adds the built in roles, permissions and religion task templates.
+func Seed(db *gorm.DB) error {
+	return errors.Join(seedRoles(db), seedReligionTemplates(db))
 }
@@ -0,0 +1,119 @@
+package migration
+
+import (
+	role "tugaskita/features/role/model"
+	task "tugaskita/features/task/model"
+	"tugaskita/utils/authz"
+	"tugaskita/utils/prayertime"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedRoles makes sure every permission and built in role exists. Default
+// permissions are only granted when a role is created so changes made by
+// an admin are kept, except admin which always gets every permission.
+func seedRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, v := range authz.Permissions() {
+			permission := role.Permission{Name: v.Name, Description: v.Description}
+			err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&permission).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for name, permissions := range authz.DefaultRoles() {
+			data := role.Role{}
+			err := tx.Where("name = ?", name).First(&data).Error
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return err
+			}
+
+			if err == gorm.ErrRecordNotFound {
+				data = role.Role{Id: uuid.New(), Name: name}
+				if errCreate := tx.Create(&data).Error; errCreate != nil {
+					return errCreate
+				}
+			} else if name != authz.RoleAdmin {
+				continue
+			}
+
+			for _, v := range permissions {
+				grant := role.RolePermission{RoleId: data.Id, PermissionName: v}
+				errGrant := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&grant).Error
+				if errGrant != nil {
+					return errGrant
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// defaultReligionTemplates are the observances generated out of the box.
+// Dzuhur is due every day, Jum'at is added on Friday for the students
+// attending it. Prayers can only be submitted during their time when
+// prayer times are configured.
+var defaultReligionTemplates = []task.ReligionTaskTemplate{
+	{Religion: "Islam", Title: "Subuh", Description: "Tugas Shalat Subuh", Point: 250, Rule: "FREQ=DAILY", PrayerWindow: prayertime.Fajr},
+	{Religion: "Islam", Title: "Dzuhur", Description: "Tugas Shalat Dzuhur", Point: 250, Rule: "FREQ=DAILY", PrayerWindow: prayertime.Dhuhr},
+	{Religion: "Islam", Title: "Jum'at", Description: "Tugas Shalat Jum'at", Point: 250, Rule: "FREQ=WEEKLY;BYDAY=FR", PrayerWindow: prayertime.Dhuhr},
+	{Religion: "Islam", Title: "Ashar", Description: "Tugas Shalat Ashar", Point: 250, Rule: "FREQ=DAILY", PrayerWindow: prayertime.Asr},
+	{Religion: "Islam", Title: "Maghrib", Description: "Tugas Shalat Maghrib", Point: 250, Rule: "FREQ=DAILY", PrayerWindow: prayertime.Maghrib},
+	{Religion: "Islam", Title: "Isya", Description: "Tugas Shalat Isya", Point: 250, Rule: "FREQ=DAILY", PrayerWindow: prayertime.Isha},
+	{Religion: "Kristen", Title: "Ibadah Minggu", Description: "Laksanakan ibadah minggu ke gereja", Point: 300, Rule: "FREQ=WEEKLY;BYDAY=SU"},
+	{Religion: "Kristen", Title: "Ibadah Natal", Description: "Laksanakan ibadah Natal ke gereja", Point: 300, Rule: "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25"},
+	{Religion: "Katolik", Title: "Ibadah Minggu", Description: "Laksanakan ibadah minggu ke gereja", Point: 300, Rule: "FREQ=WEEKLY;BYDAY=SU"},
+	{Religion: "Katolik", Title: "Misa Natal", Description: "Laksanakan misa Natal ke gereja", Point: 300, Rule: "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25"},
+	{Religion: "Hindu", Title: "Puja Trisandhya", Description: "Laksanakan puja Trisandhya", Point: 250, Rule: "FREQ=DAILY"},
+	{Religion: "Buddha", Title: "Puja Bakti", Description: "Laksanakan puja bakti ke vihara", Point: 300, Rule: "FREQ=WEEKLY;BYDAY=SU"},
+	{Religion: "Konghucu", Title: "Kebaktian", Description: "Laksanakan kebaktian ke litang", Point: 300, Rule: "FREQ=WEEKLY;BYDAY=SU"},
+}
+
+// defaultHolidayTemplates are generated on the days of calendar holidays,
+// holidays that aren't Hijri based need their dates entered by an admin.
+var defaultHolidayTemplates = []task.ReligionTaskTemplate{
+	{Religion: "Islam", Title: "Puasa Ramadhan", Description: "Laksanakan puasa Ramadhan", Point: 300, Holiday: "ramadhan"},
+	{Religion: "Islam", Title: "Shalat Tarawih", Description: "Laksanakan shalat Tarawih", Point: 250, Holiday: "ramadhan", PrayerWindow: prayertime.Isha},
+	{Religion: "Islam", Title: "Shalat Idul Fitri", Description: "Laksanakan shalat Idul Fitri", Point: 300, Holiday: "idul-fitri"},
+	{Religion: "Islam", Title: "Puasa Arafah", Description: "Laksanakan puasa Arafah", Point: 250, Holiday: "arafah"},
+	{Religion: "Islam", Title: "Shalat Idul Adha", Description: "Laksanakan shalat Idul Adha", Point: 300, Holiday: "idul-adha"},
+	{Religion: "Hindu", Title: "Catur Brata Penyepian", Description: "Laksanakan Catur Brata Penyepian pada hari Nyepi", Point: 300, Holiday: "nyepi"},
+	{Religion: "Buddha", Title: "Puja Waisak", Description: "Laksanakan puja bakti Waisak ke vihara", Point: 300, Holiday: "waisak"},
+	{Religion: "Konghucu", Title: "Sembahyang Imlek", Description: "Laksanakan sembahyang Tahun Baru Imlek", Point: 300, Holiday: "imlek"},
+}
+
+// seedTemplates adds templates when none matching query exist yet, so
+// templates removed by an admin don't come back.
+func seedTemplates(db *gorm.DB, query string, defaults []task.ReligionTaskTemplate) error {
+	var count int64
+	if err := db.Model(&task.ReligionTaskTemplate{}).Where(query, "").Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	templates := make([]task.ReligionTaskTemplate, len(defaults))
+	for i, v := range defaults {
+		v.Id = uuid.New()
+		v.StartDate = "2024-01-01"
+		v.Active = true
+		if v.Rule == "" {
+			v.Rule = "FREQ=DAILY"
+		}
+		templates[i] = v
+	}
+
+	return db.Create(&templates).Error
+}
+
+func seedReligionTemplates(db *gorm.DB) error {
+	if err := seedTemplates(db, "holiday = ?", defaultReligionTemplates); err != nil {
+		return err
+	}
+	return seedTemplates(db, "holiday <> ?", defaultHolidayTemplates)
+}
@@ -0,0 +1,232 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a versioned change of the schema or of existing rows. Up
+// and Down run in a transaction together with the update of
+// schema_migrations, MySQL commits schema changes right away though. A
+// nil Down can't be rolled back.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// migrations are applied in order of Version, a new migration gets the
+// next version and is never changed once released.
+var migrations = []Migration{
+	initialSchema,
+	taskDateColumns,
+	imageThumbnails,
+	uploadFingerprints,
+	submissionReviews,
+	reviewClaims,
+	submissionComments,
+	notifications,
+	integerPoints,
+	fingerprintIndexes,
+	userClasses,
+	dailyDzuhur,
+	uploadOwnerIndexes,
+}
+
+// SchemaMigration records an applied migration.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// Status is a migration with the time it was applied, nil when pending.
+// Unknown is set for versions applied by a newer build.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+	Unknown   bool
+}
+
+func sorted() []Migration {
+	list := make([]Migration, len(migrations))
+	copy(list, migrations)
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list
+}
+
+func applied(db *gorm.DB) (map[int]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]SchemaMigration, len(rows))
+	for _, v := range rows {
+		result[v.Version] = v
+	}
+	return result, nil
+}
+
+// Statuses returns every migration and applied version by version.
+func Statuses(db *gorm.DB) ([]Status, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+	for _, v := range sorted() {
+		status := Status{Version: v.Version, Name: v.Name}
+		if row, ok := done[v.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(done, v.Version)
+		}
+		result = append(result, status)
+	}
+
+	for _, row := range done {
+		appliedAt := row.AppliedAt
+		result = append(result, Status{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt, Unknown: true})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+
+	return result, nil
+}
+
+// Pending returns the migrations not applied yet.
+func Pending(db *gorm.DB) ([]Migration, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for _, v := range sorted() {
+		if _, ok := done[v.Version]; !ok {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// Latest returns the version of the newest migration.
+func Latest() int {
+	list := sorted()
+	if len(list) == 0 {
+		return 0
+	}
+	return list[len(list)-1].Version
+}
+
+// Up applies every pending migration and returns them.
+func Up(db *gorm.DB) ([]Migration, error) {
+	return To(db, Latest())
+}
+
+// Down rolls back the last steps applied migrations and returns them.
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	list := sorted()
+	target := 0
+	for i := len(list) - 1; i >= 0; i-- {
+		if _, ok := done[list[i].Version]; !ok {
+			continue
+		}
+		if steps == 0 {
+			target = list[i].Version
+			break
+		}
+		steps--
+	}
+
+	return to(db, target, false)
+}
+
+// To applies or rolls back migrations until version is the last applied
+// one, 0 rolls back everything. It stops at the first failure and returns
+// the migrations run until then.
+func To(db *gorm.DB, version int) ([]Migration, error) {
+	return to(db, version, true)
+}
+
+func to(db *gorm.DB, version int, apply bool) ([]Migration, error) {
+	list := sorted()
+	if version != 0 {
+		known := false
+		for _, v := range list {
+			known = known || v.Version == version
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown migration version %d", version)
+		}
+	}
+
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, v := range list {
+		if _, ok := done[v.Version]; ok || v.Version > version || !apply {
+			continue
+		}
+		if err := run(db, v, true); err != nil {
+			return ran, err
+		}
+		ran = append(ran, v)
+	}
+
+	for i := len(list) - 1; i >= 0; i-- {
+		v := list[i]
+		if _, ok := done[v.Version]; !ok || v.Version <= version {
+			continue
+		}
+		if err := run(db, v, false); err != nil {
+			return ran, err
+		}
+		ran = append(ran, v)
+	}
+
+	return ran, nil
+}
+
+func run(db *gorm.DB, m Migration, up bool) error {
+	fn := m.Up
+	if !up {
+		fn = m.Down
+	}
+	if fn == nil {
+		return fmt.Errorf("migration %d %s can't be rolled back", m.Version, m.Name)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		if up {
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Delete(&SchemaMigration{}, m.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d %s: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
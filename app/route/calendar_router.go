@@ -0,0 +1,29 @@
+package route
+
+import (
+	"tugaskita/features/calendar/handler"
+	"tugaskita/features/calendar/repository"
+	"tugaskita/features/calendar/service"
+	"tugaskita/utils/authz"
+	m "tugaskita/utils/jwt"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func CalendarRouter(db *gorm.DB, e *echo.Group) {
+	calendarRepository := repository.NewCalendarRepository(db)
+	calendarUseCase := service.NewCalendarService(calendarRepository)
+	calendarController := handler.New(calendarUseCase)
+
+	calendar := e.Group("/calendar")
+	calendar.GET("/hijri", calendarController.HijriDate, m.JWTMiddleware())
+	calendar.GET("/holiday", calendarController.ReadAllHoliday, m.JWTMiddleware())
+
+	admin := e.Group("/admin-holiday")
+	admin.GET("", calendarController.ReadAllHoliday, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.POST("", calendarController.AddHoliday, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.GET("/:id", calendarController.ReadSpecificHoliday, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.PUT("/:id", calendarController.UpdateHoliday, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.DELETE("/:id", calendarController.DeleteHoliday, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+}
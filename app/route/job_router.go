@@ -0,0 +1,22 @@
+package route
+
+import (
+	"tugaskita/features/job/handler"
+	"tugaskita/features/job/repository"
+	"tugaskita/features/job/service"
+	"tugaskita/utils/authz"
+	m "tugaskita/utils/jwt"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func JobRouter(db *gorm.DB, e *echo.Group) {
+	jobRepository := repository.NewJobRepository(db)
+	jobUseCase := service.NewJobService(jobRepository)
+	jobController := handler.New(jobUseCase)
+
+	job := e.Group("/admin-job")
+	job.GET("/run", jobController.ReadAllJobRun, m.JWTMiddleware(), authz.Require(authz.JobRead))
+	job.GET("/lock", jobController.ReadAllJobLock, m.JWTMiddleware(), authz.Require(authz.JobRead))
+}
@@ -0,0 +1,39 @@
+package route
+
+import (
+	"log"
+	"tugaskita/features/notification/handler"
+	"tugaskita/features/notification/repository"
+	"tugaskita/features/notification/service"
+	"tugaskita/utils/event"
+	m "tugaskita/utils/jwt"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// NotificationListener stores the notifications of published events until
+// the returned function is called. It is started before the scheduler so
+// the events of its first runs aren't missed.
+func NotificationListener(db *gorm.DB) func() {
+	notificationUseCase := service.NewNotificationService(repository.NewNotificationRepository(db))
+
+	return event.Subscribe(func(e event.Event) {
+		if err := notificationUseCase.Notify(e); err != nil {
+			log.Printf("notification: %s not stored: %v", e.Type, err)
+		}
+	}, service.Types...)
+}
+
+func NotificationRouter(db *gorm.DB, e *echo.Group) {
+	notificationRepository := repository.NewNotificationRepository(db)
+	notificationUseCase := service.NewNotificationService(notificationRepository)
+	notificationController := handler.New(notificationUseCase)
+
+	notification := e.Group("/notification")
+	notification.GET("", notificationController.ReadAllNotification, m.JWTMiddleware())
+	notification.GET("/stream", notificationController.StreamEvent, m.JWTStreamMiddleware())
+	notification.GET("/unread", notificationController.CountUnreadNotification, m.JWTMiddleware())
+	notification.PUT("/read", notificationController.MarkAllNotificationRead, m.JWTMiddleware())
+	notification.PUT("/:id/read", notificationController.MarkNotificationRead, m.JWTMiddleware())
+}
@@ -0,0 +1,41 @@
+package route
+
+import (
+	"tugaskita/features/parent/handler"
+	"tugaskita/features/parent/repository"
+	"tugaskita/features/parent/service"
+	penaltyRepo "tugaskita/features/penalty/repository"
+	rewardRepo "tugaskita/features/reward/repository"
+	taskRepo "tugaskita/features/task/repository"
+	userRepo "tugaskita/features/user/repository"
+	"tugaskita/utils/authz"
+	m "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func ParentRouter(db *gorm.DB, e *echo.Group, store storage.Storage) {
+	userRepository := userRepo.New(db, store)
+	penaltyRepository := penaltyRepo.NewPenaltyRepository(db, userRepository)
+	taskRepository := taskRepo.NewTaskRepository(db, userRepository, store)
+	rewardRepository := rewardRepo.NewRewardRepository(db, userRepository, store)
+
+	parentRepository := repository.NewParentRepository(db)
+	parentUseCase := service.NewParentService(parentRepository, userRepository, penaltyRepository, taskRepository, rewardRepository)
+	parentController := handler.New(parentUseCase)
+
+	admin := e.Group("/admin-parent")
+	admin.POST("", parentController.AddParent, m.JWTMiddleware(), authz.Require(authz.UserManage))
+	admin.GET("/:id/student", parentController.ReadParentChildren, m.JWTMiddleware(), authz.Require(authz.UserRead))
+	admin.POST("/:id/student", parentController.LinkStudent, m.JWTMiddleware(), authz.Require(authz.UserManage))
+	admin.DELETE("/:id/student/:student_id", parentController.UnlinkStudent, m.JWTMiddleware(), authz.Require(authz.UserManage))
+
+	parent := e.Group("/parent")
+	parent.GET("/child", parentController.ReadChildren, m.JWTMiddleware(), authz.Require(authz.ParentMonitor))
+	parent.GET("/child/:id/point-history", parentController.ReadChildPointHistory, m.JWTMiddleware(), authz.Require(authz.ParentMonitor))
+	parent.GET("/child/:id/penalty-history", parentController.ReadChildPenaltyHistory, m.JWTMiddleware(), authz.Require(authz.ParentMonitor))
+	parent.GET("/child/:id/task-history", parentController.ReadChildTaskHistory, m.JWTMiddleware(), authz.Require(authz.ParentMonitor))
+	parent.GET("/child/:id/reward-history", parentController.ReadChildRewardHistory, m.JWTMiddleware(), authz.Require(authz.ParentMonitor))
+}
@@ -1,31 +1,34 @@
 package route
 
 import (
+	"tugaskita/app/config"
 	"tugaskita/features/penalty/handler"
 	"tugaskita/features/penalty/repository"
 	"tugaskita/features/penalty/service"
 	userRepo "tugaskita/features/user/repository"
 	userService "tugaskita/features/user/service"
+	"tugaskita/utils/authz"
 	m "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func PenaltyRouter(db *gorm.DB, e *echo.Group) {
-	userRepository := userRepo.New(db)
-	userUseCase := userService.New(userRepository)
+func PenaltyRouter(db *gorm.DB, e *echo.Group, cfg *config.Config, store storage.Storage) {
+	userRepository := userRepo.New(db, store)
+	userUseCase := userService.New(userRepository, cfg.Mail.Sender())
 
-	penaltyRepository := repository.NewPenaltyRepository(db)
+	penaltyRepository := repository.NewPenaltyRepository(db, userRepository)
 	penaltyUseCase := service.NewPenaltyService(penaltyRepository, userRepository)
 	penaltyController := handler.New(penaltyUseCase, userUseCase)
 	
 	admin := e.Group("/admin-penalty")
-	admin.POST("", penaltyController.CreatePenalty, m.JWTMiddleware())
-	admin.GET("", penaltyController.FindAllPenalty, m.JWTMiddleware())
-	admin.GET("/:id", penaltyController.FindSpecificPenalty, m.JWTMiddleware())
-	admin.PUT("/:id", penaltyController.UpdatePenalty, m.JWTMiddleware())
-	admin.DELETE("/:id", penaltyController.DeletePenalty, m.JWTMiddleware())
+	admin.POST("", penaltyController.CreatePenalty, m.JWTMiddleware(), authz.Require(authz.PenaltyManage))
+	admin.GET("", penaltyController.FindAllPenalty, m.JWTMiddleware(), authz.Require(authz.PenaltyRead))
+	admin.GET("/:id", penaltyController.FindSpecificPenalty, m.JWTMiddleware(), authz.Require(authz.PenaltyRead))
+	admin.PUT("/:id", penaltyController.UpdatePenalty, m.JWTMiddleware(), authz.Require(authz.PenaltyManage))
+	admin.DELETE("/:id", penaltyController.DeletePenalty, m.JWTMiddleware(), authz.Require(authz.PenaltyManage))
 
 	user := e.Group("/user-penalty")
 	user.GET("/:id", penaltyController.FindSpecificPenalty, m.JWTMiddleware())
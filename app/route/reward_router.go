@@ -1,23 +1,26 @@
 package route
 
 import (
+	"tugaskita/app/config"
 	"tugaskita/features/reward/handler"
 	"tugaskita/features/reward/repository"
 	"tugaskita/features/reward/service"
 
 	userR "tugaskita/features/user/repository"
 	userS "tugaskita/features/user/service"
+	"tugaskita/utils/authz"
 	m "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func RewardRouter(db *gorm.DB, e *echo.Group) {
-	userRepository := userR.New(db)
-	userUseCase := userS.New(userRepository)
+func RewardRouter(db *gorm.DB, e *echo.Group, cfg *config.Config, store storage.Storage) {
+	userRepository := userR.New(db, store)
+	userUseCase := userS.New(userRepository, cfg.Mail.Sender())
 
-	rewardRepository := repository.NewRewardRepository(db, userRepository)
+	rewardRepository := repository.NewRewardRepository(db, userRepository, store)
 	rewardUseCase := service.NewRewardService(rewardRepository, userRepository)
 	rewardController := handler.New(rewardUseCase, userUseCase)
 
@@ -25,16 +28,16 @@ func RewardRouter(db *gorm.DB, e *echo.Group) {
 	user.GET("", rewardController.ReadAllReward, m.JWTMiddleware())
 	user.GET("/:id", rewardController.ReadSpecificReward, m.JWTMiddleware())
 	user.GET("/history", rewardController.FindAllRewardHistory, m.JWTMiddleware())
-	user.POST("/exchange", rewardController.UploadRewardRequest, m.JWTMiddleware())
+	user.POST("/exchange", rewardController.UploadRewardRequest, m.JWTMiddleware(), authz.Require(authz.RewardExchange))
 
 	admin := e.Group("/admin-reward")
-	admin.GET("", rewardController.ReadAllReward, m.JWTMiddleware())
-	admin.POST("", rewardController.AddReward, m.JWTMiddleware())
-	admin.GET("/:id", rewardController.ReadSpecificReward, m.JWTMiddleware())
-	admin.PUT("/:id", rewardController.UpdateReward, m.JWTMiddleware())
-	admin.DELETE("/:id", rewardController.DeleteReward, m.JWTMiddleware())
-	admin.GET("/user", rewardController.FindAllUploadReward, m.JWTMiddleware())
-	admin.GET("/user/:id", rewardController.FindUserRewardById, m.JWTMiddleware())
-	admin.PUT("/user/:id", rewardController.UpdateReqRewardStatus, m.JWTMiddleware())
+	admin.GET("", rewardController.ReadAllReward, m.JWTMiddleware(), authz.Require(authz.RewardManage))
+	admin.POST("", rewardController.AddReward, m.JWTMiddleware(), authz.Require(authz.RewardManage))
+	admin.GET("/:id", rewardController.ReadSpecificReward, m.JWTMiddleware(), authz.Require(authz.RewardManage))
+	admin.PUT("/:id", rewardController.UpdateReward, m.JWTMiddleware(), authz.Require(authz.RewardManage))
+	admin.DELETE("/:id", rewardController.DeleteReward, m.JWTMiddleware(), authz.Require(authz.RewardManage))
+	admin.GET("/user", rewardController.FindAllUploadReward, m.JWTMiddleware(), authz.Require(authz.RewardReview))
+	admin.GET("/user/:id", rewardController.FindUserRewardById, m.JWTMiddleware(), authz.Require(authz.RewardReview))
+	admin.PUT("/user/:id", rewardController.UpdateReqRewardStatus, m.JWTMiddleware(), authz.Require(authz.RewardReview))
 
 }
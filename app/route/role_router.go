@@ -0,0 +1,31 @@
+package route
+
+import (
+	"tugaskita/features/role/handler"
+	"tugaskita/features/role/repository"
+	"tugaskita/features/role/service"
+	userRepo "tugaskita/features/user/repository"
+	"tugaskita/utils/authz"
+	m "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func RoleRouter(db *gorm.DB, e *echo.Group, store storage.Storage) {
+	userRepository := userRepo.New(db, store)
+
+	roleRepository := repository.NewRoleRepository(db)
+	roleUseCase := service.NewRoleService(roleRepository, userRepository)
+	roleController := handler.New(roleUseCase)
+
+	admin := e.Group("/admin-role")
+	admin.GET("", roleController.ReadAllRole, m.JWTMiddleware(), authz.Require(authz.RoleManage))
+	admin.POST("", roleController.AddRole, m.JWTMiddleware(), authz.Require(authz.RoleManage))
+	admin.GET("/permission", roleController.ReadAllPermission, m.JWTMiddleware(), authz.Require(authz.RoleManage))
+	admin.GET("/:id", roleController.ReadSpecificRole, m.JWTMiddleware(), authz.Require(authz.RoleManage))
+	admin.PUT("/:id", roleController.UpdateRole, m.JWTMiddleware(), authz.Require(authz.RoleManage))
+	admin.DELETE("/:id", roleController.DeleteRole, m.JWTMiddleware(), authz.Require(authz.RoleManage))
+	admin.PUT("/user/:id", roleController.UpdateUserRole, m.JWTMiddleware(), authz.Require(authz.RoleManage))
+}
@@ -1,16 +1,35 @@
 package route
 
 import (
+	"tugaskita/app/config"
+	roleRepo "tugaskita/features/role/repository"
+	userRepo "tugaskita/features/user/repository"
+	"tugaskita/utils/authz"
+	"tugaskita/utils/imageproc"
+	m "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
+
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func New(e *echo.Echo, db *gorm.DB) {
+func New(e *echo.Echo, db *gorm.DB, cfg *config.Config, store storage.Storage) {
+	m.SetSigningKey(cfg.JWT.SecretKey)
+	imageproc.SetOptions(cfg.Image.Options())
+	authz.SetChecker(roleRepo.NewRoleRepository(db))
+	m.SetRevoker(userRepo.New(db, store))
+
 	user := e.Group("user")
 	base := e.Group("")
 
-	UserRouter(db, user)
-	TaskRouter(db, base)
-	RewardRouter(db, base)
-	PenaltyRouter(db, base)
+	UserRouter(db, user, cfg, store)
+	TaskRouter(db, base, cfg, store)
+	RewardRouter(db, base, cfg, store)
+	PenaltyRouter(db, base, cfg, store)
+	RoleRouter(db, base, store)
+	SchoolRouter(db, base, store)
+	ParentRouter(db, base, store)
+	CalendarRouter(db, base)
+	JobRouter(db, base)
+	NotificationRouter(db, base)
 }
@@ -0,0 +1,39 @@
+package route
+
+import (
+	"tugaskita/features/school/handler"
+	"tugaskita/features/school/repository"
+	"tugaskita/features/school/service"
+	userRepo "tugaskita/features/user/repository"
+	"tugaskita/utils/authz"
+	m "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func SchoolRouter(db *gorm.DB, e *echo.Group, store storage.Storage) {
+	userRepository := userRepo.New(db, store)
+
+	schoolRepository := repository.NewSchoolRepository(db)
+	schoolUseCase := service.NewSchoolService(schoolRepository, userRepository)
+	schoolController := handler.New(schoolUseCase)
+
+	school := e.Group("/admin-school")
+	school.GET("", schoolController.ReadAllSchool, m.JWTMiddleware(), authz.Require(authz.UserRead))
+	school.POST("", schoolController.AddSchool, m.JWTMiddleware(), authz.Require(authz.SchoolManage))
+	school.GET("/:id", schoolController.ReadSpecificSchool, m.JWTMiddleware(), authz.Require(authz.UserRead))
+	school.PUT("/:id", schoolController.UpdateSchool, m.JWTMiddleware(), authz.Require(authz.SchoolManage))
+	school.DELETE("/:id", schoolController.DeleteSchool, m.JWTMiddleware(), authz.Require(authz.SchoolManage))
+
+	class := e.Group("/admin-class")
+	class.GET("", schoolController.ReadAllClass, m.JWTMiddleware(), authz.Require(authz.UserRead))
+	class.POST("", schoolController.AddClass, m.JWTMiddleware(), authz.Require(authz.SchoolManage))
+	class.GET("/:id", schoolController.ReadSpecificClass, m.JWTMiddleware(), authz.Require(authz.UserRead))
+	class.PUT("/:id", schoolController.UpdateClass, m.JWTMiddleware(), authz.Require(authz.SchoolManage))
+	class.DELETE("/:id", schoolController.DeleteClass, m.JWTMiddleware(), authz.Require(authz.SchoolManage))
+	class.PUT("/:id/student", schoolController.AssignStudent, m.JWTMiddleware(), authz.Require(authz.SchoolManage))
+
+	e.GET("/class-rank", schoolController.ClassRank, m.JWTMiddleware())
+}
@@ -1,72 +1,136 @@
 package route
 
 import (
+	"tugaskita/app/config"
+	calendarRepo "tugaskita/features/calendar/repository"
+	calendarService "tugaskita/features/calendar/service"
+	"tugaskita/features/task/entity"
 	"tugaskita/features/task/handler"
 	"tugaskita/features/task/repository"
 	"tugaskita/features/task/service"
 	userRepo "tugaskita/features/user/repository"
 	userService "tugaskita/features/user/service"
+	"tugaskita/utils/authz"
 	m "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func TaskRouter(db *gorm.DB, e *echo.Group) {
-	userRepository := userRepo.New(db)
-	userUseCase := userService.New(userRepository)
+func TaskRouter(db *gorm.DB, e *echo.Group, cfg *config.Config, store storage.Storage) {
+	userRepository := userRepo.New(db, store)
+	userUseCase := userService.New(userRepository, cfg.Mail.Sender())
 
-	taskRepository := repository.NewTaskRepository(db, userRepository)
-	taskUseCase := service.NewTaskService(taskRepository)
+	calendarUseCase := calendarService.NewCalendarService(calendarRepo.NewCalendarRepository(db))
+
+	taskRepository := repository.NewTaskRepository(db, userRepository, store)
+	taskUseCase := service.NewTaskService(taskRepository, calendarUseCase, cfg.Prayer.Calculator())
 	taskController := handler.New(taskUseCase, userUseCase)
 
 	user := e.Group("/user-task")
 	user.GET("/:id", taskController.ReadSpecificTask, m.JWTMiddleware())
 	user.GET("", taskController.ReadAllTask, m.JWTMiddleware())
-	user.POST("", taskController.UploadTaskUser, m.JWTMiddleware())
+	user.POST("", taskController.UploadTaskUser, m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
 	user.GET("/riwayat/:id", taskController.FindUserTaskById, m.JWTMiddleware())
 	user.GET("/riwayat", taskController.ReadHistoryTaskUser, m.JWTMiddleware())
+	user.PUT("/riwayat/:id", taskController.ResubmitSubmission(entity.KindTask), m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.GET("/riwayat/:id/revisions", taskController.FindSubmissionRevisions(entity.KindTask), m.JWTMiddleware())
+	user.GET("/riwayat/:id/comments", taskController.FindSubmissionComments(entity.KindTask), m.JWTMiddleware())
+	user.POST("/riwayat/:id/comments", taskController.AddSubmissionComment(entity.KindTask), m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.PUT("/riwayat/:id/comments/read", taskController.ReadSubmissionComments(entity.KindTask), m.JWTMiddleware())
 
-	user.POST("/request", taskController.UploadRequestTaskUser, m.JWTMiddleware())
+	user.POST("/request", taskController.UploadRequestTaskUser, m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
 	user.GET("/req-riwayat", taskController.FindAllRequestTaskHistory, m.JWTMiddleware())
 	user.GET("/request/:id", taskController.FindUserTaskReqyId, m.JWTMiddleware())
+	user.PUT("/request/:id", taskController.ResubmitSubmission(entity.KindSubmission), m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.GET("/request/:id/revisions", taskController.FindSubmissionRevisions(entity.KindSubmission), m.JWTMiddleware())
+	user.GET("/request/:id/comments", taskController.FindSubmissionComments(entity.KindSubmission), m.JWTMiddleware())
+	user.POST("/request/:id/comments", taskController.AddSubmissionComment(entity.KindSubmission), m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.PUT("/request/:id/comments/read", taskController.ReadSubmissionComments(entity.KindSubmission), m.JWTMiddleware())
 	
 	user.GET("/religion", taskController.FindAllReligionTaskUser, m.JWTMiddleware())
 	user.GET("/religion/:id", taskController.ReadSpecificReligionTask, m.JWTMiddleware())
 	user.GET("/religion/history", taskController.ReligionTaskHistoryUser, m.JWTMiddleware())
-	user.POST("/religion", taskController.UploadTaskReligionUser, m.JWTMiddleware())
+	user.POST("/religion", taskController.UploadTaskReligionUser, m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.PUT("/religion/history/:id", taskController.ResubmitSubmission(entity.KindReligion), m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.GET("/religion/history/:id/revisions", taskController.FindSubmissionRevisions(entity.KindReligion), m.JWTMiddleware())
+	user.GET("/religion/history/:id/comments", taskController.FindSubmissionComments(entity.KindReligion), m.JWTMiddleware())
+	user.POST("/religion/history/:id/comments", taskController.AddSubmissionComment(entity.KindReligion), m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.PUT("/religion/history/:id/comments/read", taskController.ReadSubmissionComments(entity.KindReligion), m.JWTMiddleware())
 
-	user.POST("/religion-req", taskController.UploadReligionTaskRequest, m.JWTMiddleware())
+	user.POST("/religion-req", taskController.UploadReligionTaskRequest, m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
 	user.GET("/religion-req/history", taskController.FindAllReligionTaskRequestHistory, m.JWTMiddleware()) 
 	user.GET("/religion-req/history/:id", taskController.FindSpesificReligionTaskRequest, m.JWTMiddleware()) 
+	user.PUT("/religion-req/history/:id", taskController.ResubmitSubmission(entity.KindReligionRequest), m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.GET("/religion-req/history/:id/revisions", taskController.FindSubmissionRevisions(entity.KindReligionRequest), m.JWTMiddleware())
+	user.GET("/religion-req/history/:id/comments", taskController.FindSubmissionComments(entity.KindReligionRequest), m.JWTMiddleware())
+	user.POST("/religion-req/history/:id/comments", taskController.AddSubmissionComment(entity.KindReligionRequest), m.JWTMiddleware(), authz.Require(authz.TaskSubmit))
+	user.PUT("/religion-req/history/:id/comments/read", taskController.ReadSubmissionComments(entity.KindReligionRequest), m.JWTMiddleware())
 	
 	user.GET("/sum-clear", taskController.CountUserClearTask, m.JWTMiddleware())
 	
 	admin := e.Group("/admin-task")
-	admin.GET("/:id", taskController.ReadSpecificTask, m.JWTMiddleware())
-	admin.PUT("/:id", taskController.UpdateTask, m.JWTMiddleware())
-	admin.GET("", taskController.ReadAllTask, m.JWTMiddleware())
-	admin.POST("", taskController.AddTask, m.JWTMiddleware())
-	admin.DELETE("/:id", taskController.DeleteTask, m.JWTMiddleware())
+	admin.GET("/:id", taskController.ReadSpecificTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.PUT("/:id", taskController.UpdateTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.GET("", taskController.ReadAllTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.POST("", taskController.AddTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.DELETE("/:id", taskController.DeleteTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+
+	series := e.Group("/admin-task-series")
+	series.GET("", taskController.ReadAllTaskSeries, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	series.POST("", taskController.AddTaskSeries, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	series.GET("/:id", taskController.ReadSpecificTaskSeries, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	series.PUT("/:id", taskController.UpdateTaskSeries, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	series.DELETE("/:id", taskController.CancelTaskSeries, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+
+	religionTemplate := e.Group("/admin-religion-template")
+	religionTemplate.GET("", taskController.ReadAllReligionTemplate, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	religionTemplate.POST("", taskController.AddReligionTemplate, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	religionTemplate.GET("/:id", taskController.ReadSpecificReligionTemplate, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	religionTemplate.PUT("/:id", taskController.UpdateReligionTemplate, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	religionTemplate.DELETE("/:id", taskController.DeleteReligionTemplate, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+
+	admin.GET("/user", taskController.FindAllUserTask, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/user/request", taskController.FindAllUserRequestTask, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.PUT("/user/request/:id", taskController.UpdateTaskReqStatus, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.PUT("/user/:id", taskController.UpdateTaskStatus, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/user/:id", taskController.FindUserTaskById, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/user/request/:id", taskController.FindUserTaskReqyId, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/user/:id/revisions", taskController.FindSubmissionRevisions(entity.KindTask), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/user/:id/comments", taskController.FindSubmissionComments(entity.KindTask), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.POST("/user/:id/comments", taskController.AddSubmissionComment(entity.KindTask), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.PUT("/user/:id/comments/read", taskController.ReadSubmissionComments(entity.KindTask), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/user/request/:id/revisions", taskController.FindSubmissionRevisions(entity.KindSubmission), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/user/request/:id/comments", taskController.FindSubmissionComments(entity.KindSubmission), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.POST("/user/request/:id/comments", taskController.AddSubmissionComment(entity.KindSubmission), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.PUT("/user/request/:id/comments/read", taskController.ReadSubmissionComments(entity.KindSubmission), m.JWTMiddleware(), authz.Require(authz.TaskReview))
 
-	admin.GET("/user", taskController.FindAllUserTask, m.JWTMiddleware())
-	admin.GET("/user/request", taskController.FindAllUserRequestTask, m.JWTMiddleware())
-	admin.PUT("/user/request/:id", taskController.UpdateTaskReqStatus, m.JWTMiddleware())
-	admin.PUT("/user/:id", taskController.UpdateTaskStatus, m.JWTMiddleware())
-	admin.GET("/user/:id", taskController.FindUserTaskById, m.JWTMiddleware())
-	admin.GET("/user/request/:id", taskController.FindUserTaskReqyId, m.JWTMiddleware())
+	admin.GET("/religion/:id", taskController.ReadSpecificReligionTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.PUT("/religion/:id", taskController.UpdateReligionTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.GET("/religion", taskController.ReadAllReligionTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.POST("/religion", taskController.AddReligionTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
+	admin.DELETE("/religion/:id", taskController.DeleteReligionTask, m.JWTMiddleware(), authz.Require(authz.TaskManage))
 
-	admin.GET("/religion/:id", taskController.ReadSpecificReligionTask, m.JWTMiddleware())
-	admin.PUT("/religion/:id", taskController.UpdateReligionTask, m.JWTMiddleware())
-	admin.GET("/religion", taskController.ReadAllReligionTask, m.JWTMiddleware())
-	admin.POST("/religion", taskController.AddReligionTask, m.JWTMiddleware())
-	admin.DELETE("/religion/:id", taskController.DeleteReligionTask, m.JWTMiddleware())
+	admin.GET("/religion/user", taskController.FindAllUserReligionTask, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/religion/user/:id", taskController.FindSpecificUserReligionTask, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.PUT("/religion/user/:id", taskController.UpdateReligionTaskStatus, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/religion/user/:id/revisions", taskController.FindSubmissionRevisions(entity.KindReligion), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/religion/user/:id/comments", taskController.FindSubmissionComments(entity.KindReligion), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.POST("/religion/user/:id/comments", taskController.AddSubmissionComment(entity.KindReligion), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.PUT("/religion/user/:id/comments/read", taskController.ReadSubmissionComments(entity.KindReligion), m.JWTMiddleware(), authz.Require(authz.TaskReview))
 
-	admin.GET("/religion/user", taskController.FindAllUserReligionTask, m.JWTMiddleware())
-	admin.GET("/religion/user/:id", taskController.FindSpecificUserReligionTask, m.JWTMiddleware())
-	admin.PUT("/religion/user/:id", taskController.UpdateReligionTaskStatus, m.JWTMiddleware())
+	admin.GET("/religion/user-req", taskController.GetAllUserReligionTaskRequest, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/religion/user-req/:id", taskController.FindSpesificReligionTaskRequest, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.PUT("/religion/user-req/:id", taskController.UpdateTaskReligionReqStatus, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/religion/user-req/:id/revisions", taskController.FindSubmissionRevisions(entity.KindReligionRequest), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.GET("/religion/user-req/:id/comments", taskController.FindSubmissionComments(entity.KindReligionRequest), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.POST("/religion/user-req/:id/comments", taskController.AddSubmissionComment(entity.KindReligionRequest), m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	admin.PUT("/religion/user-req/:id/comments/read", taskController.ReadSubmissionComments(entity.KindReligionRequest), m.JWTMiddleware(), authz.Require(authz.TaskReview))
 
-	admin.GET("/religion/user-req", taskController.GetAllUserReligionTaskRequest, m.JWTMiddleware())
-	admin.GET("/religion/user-req/:id", taskController.FindSpesificReligionTaskRequest, m.JWTMiddleware())
-	admin.PUT("/religion/user-req/:id", taskController.UpdateTaskReligionReqStatus, m.JWTMiddleware())
+	queue := e.Group("/admin-review")
+	queue.GET("", taskController.ReadReviewQueue, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	queue.POST("/claim", taskController.ClaimReviewQueue, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	queue.POST("/release", taskController.ReleaseReviewClaims, m.JWTMiddleware(), authz.Require(authz.TaskReview))
+	queue.POST("/bulk", taskController.BulkReviewSubmission, m.JWTMiddleware(), authz.Require(authz.TaskReview))
 }
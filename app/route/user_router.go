@@ -1,34 +1,52 @@
 package route
 
 import (
+	"tugaskita/app/config"
 	"tugaskita/features/user/handler"
 	"tugaskita/features/user/repository"
 	"tugaskita/features/user/service"
+	"tugaskita/utils/authz"
 	m "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func UserRouter(db *gorm.DB, e *echo.Group) {
-	userRepository := repository.New(db)
-	userUseCase := service.New(userRepository)
+func UserRouter(db *gorm.DB, e *echo.Group, cfg *config.Config, store storage.Storage) {
+	userRepository := repository.New(db, store)
+	userUseCase := service.New(userRepository, cfg.Mail.Sender())
 	userController := handler.New(userUseCase)
 
 	e.POST("/register", userController.Register)
 	e.POST("/login", userController.Login)
-	e.GET("", userController.ReadAllUser, m.JWTMiddleware())
+	e.POST("/refresh", userController.RefreshToken)
+	e.POST("/forgot-password", userController.ForgotPassword)
+	e.POST("/verify-reset-code", userController.VerifyResetCode)
+	e.POST("/reset-password", userController.ResetPassword)
+	e.POST("/logout", userController.Logout, m.JWTMiddleware())
+	e.GET("", userController.ReadAllUser, m.JWTMiddleware(), authz.Require(authz.UserRead))
+	e.POST("/import", userController.ImportUser, m.JWTMiddleware(), authz.Require(authz.UserManage))
+	e.GET("/export", userController.ExportUser, m.JWTMiddleware(), authz.Require(authz.UserRead))
 	e.GET("/profile", userController.ReadProfileUser, m.JWTMiddleware())
-	e.GET("/:id", userController.ReadSpecificUser, m.JWTMiddleware())
-	e.DELETE("/:id", userController.DeleteUser, m.JWTMiddleware())
+	e.GET("/:id", userController.ReadSpecificUser, m.JWTMiddleware(), authz.Require(authz.UserRead))
+	e.DELETE("/:id", userController.DeleteUser, m.JWTMiddleware(), authz.Require(authz.UserManage))
 	e.GET("/rank", userController.GetRankUser, m.JWTMiddleware())
+	e.GET("/rank/period", userController.GetPeriodRank, m.JWTMiddleware())
+	e.GET("/rank/trajectory", userController.GetRankTrajectory, m.JWTMiddleware())
+	e.GET("/leaderboard-snapshot", userController.ReadAllSnapshot, m.JWTMiddleware())
+	e.POST("/leaderboard-snapshot", userController.CreateSnapshot, m.JWTMiddleware(), authz.Require(authz.PointReset))
+	e.GET("/leaderboard-snapshot/:id", userController.ReadSpecificSnapshot, m.JWTMiddleware())
 	e.PUT("/change-password", userController.ChangePassword, m.JWTMiddleware())
-	e.PUT("/:id", userController.UpdateSiswa, m.JWTMiddleware())
+	e.PUT("/:id", userController.UpdateSiswa, m.JWTMiddleware(), authz.Require(authz.UserManage))
 
-	e.POST("/monthly-reset", userController.MonthlyResetPoint, m.JWTMiddleware())
-	e.POST("/annual-reset", userController.AnnualResetPoint, m.JWTMiddleware())
+	e.POST("/monthly-reset", userController.MonthlyResetPoint, m.JWTMiddleware(), authz.Require(authz.PointReset))
+	e.POST("/annual-reset", userController.AnnualResetPoint, m.JWTMiddleware(), authz.Require(authz.PointReset))
+	e.GET("/point-reset", userController.ReadAllPointReset, m.JWTMiddleware(), authz.Require(authz.PointRead))
 
-	e.GET("/user-point-history", userController.GetAllUserPointHistory, m.JWTMiddleware())
+	e.GET("/user-point-history", userController.GetAllUserPointHistory, m.JWTMiddleware(), authz.Require(authz.PointRead))
 	e.GET("/user-point-history/:id", userController.GetSpecificUserPointHistory, m.JWTMiddleware())
 	e.GET("/point-history",userController.GetUserPointHistory, m.JWTMiddleware())
+	e.POST("/:id/revoke-sessions", userController.RevokeUserSessions, m.JWTMiddleware(), authz.Require(authz.UserManage))
+	e.POST("/:id/point-adjustment", userController.AdjustPoint, m.JWTMiddleware(), authz.Require(authz.PointAdjust))
 }
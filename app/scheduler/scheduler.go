@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"tugaskita/app/config"
+	calendarRepo "tugaskita/features/calendar/repository"
+	calendarService "tugaskita/features/calendar/service"
+	jobRepo "tugaskita/features/job/repository"
+	taskEntity "tugaskita/features/task/entity"
+	taskRepo "tugaskita/features/task/repository"
+	taskService "tugaskita/features/task/service"
+	"tugaskita/features/user/entity"
+	"tugaskita/features/user/repository"
+	"tugaskita/features/user/service"
+	"tugaskita/utils/scheduler"
+	"tugaskita/utils/storage"
+
+	"gorm.io/gorm"
+)
+
+// Start schedules the background jobs of cfg. Times use the server time
+// zone (TZ).
+//
+// Every attempt is stored in job_runs. A failed run is retried, and
+// job_locks keeps instances sharing the database from running the same
+// activation.
+func Start(db *gorm.DB, cfg *config.Config, store storage.Storage) *scheduler.Scheduler {
+	userRepository := repository.New(db, store)
+	userUseCase := service.New(userRepository, cfg.Mail.Sender())
+	calendarUseCase := calendarService.NewCalendarService(calendarRepo.NewCalendarRepository(db))
+	taskUseCase := taskService.NewTaskService(taskRepo.NewTaskRepository(db, userRepository, store), calendarUseCase, cfg.Prayer.Calculator())
+
+	jobRepository := jobRepo.NewJobRepository(db)
+
+	location := time.Local
+	jobs := scheduler.New(location)
+	jobs.SetRecorder(jobRepository)
+	jobs.SetLocker(jobRepository, cfg.Scheduler.LockTTL)
+	jobs.SetRetry(cfg.Scheduler.Retries, cfg.Scheduler.RetryBackoff)
+	add := func(name string, spec string, job scheduler.Job) {
+		if spec == "off" {
+			return
+		}
+
+		if err := jobs.Add(name, spec, cfg.Scheduler.CatchUp, job); err != nil {
+			log.Fatalf("invalid schedule of %s: %v", name, err)
+		}
+	}
+
+	add("monthly point reset", cfg.Scheduler.MonthlyReset, func(at time.Time) (int, error) {
+		return 0, userUseCase.RunScheduledReset(entity.ResetMonthly, at)
+	})
+	add("annual point reset", cfg.Scheduler.AnnualReset, func(at time.Time) (int, error) {
+		return 0, userUseCase.RunScheduledReset(entity.ResetAnnual, at)
+	})
+	add("task series generation", cfg.Scheduler.TaskSeries, func(at time.Time) (int, error) {
+		return taskUseCase.GenerateTaskSeries(at.AddDate(0, 0, taskEntity.TaskSeriesHorizonDays))
+	})
+
+	//generates today's tasks and those of the coming week, e.g. the
+	//sunday service, for every religion with templates
+	add("religion task generation", cfg.Scheduler.ReligionTask, func(at time.Time) (int, error) {
+		from, _ := time.ParseInLocation("2006-01-02", at.Format("2006-01-02"), location)
+		return taskUseCase.GenerateReligionTasks("", from, from.AddDate(0, 0, taskEntity.ReligionTaskHorizonDays))
+	})
+
+	jobs.Start()
+	return jobs
+}
@@ -0,0 +1,11 @@
+package dto
+
+type HolidayRequest struct {
+	Key         string `json:"key" form:"key"`
+	Period      string `json:"period" form:"period"`
+	Name        string `json:"name" form:"name"`
+	Religion    string `json:"religion" form:"religion"`
+	StartDate   string `json:"start_date" form:"start_date"`
+	EndDate     string `json:"end_date" form:"end_date"`
+	Description string `json:"description" form:"description"`
+}
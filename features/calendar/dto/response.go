@@ -0,0 +1,10 @@
+package dto
+
+type HijriDateResponse struct {
+	Date      string `json:"date"`
+	Year      int    `json:"year"`
+	Month     int    `json:"month"`
+	MonthName string `json:"month_name"`
+	Day       int    `json:"day"`
+	Text      string `json:"text"`
+}
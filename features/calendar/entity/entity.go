@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HolidayCore struct {
+	Id          uuid.UUID `json:"id"`
+	Key         string    `json:"key"`
+	Period      string    `json:"period"`
+	Name        string    `json:"name"`
+	Religion    string    `json:"religion"`
+	StartDate   string    `json:"start_date"`
+	EndDate     string    `json:"end_date"`
+	Description string    `json:"description"`
+	Computed    bool      `json:"computed"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// HolidayFilter selects holidays overlapping [From, To], dates use
+// "2006-01-02".
+type HolidayFilter struct {
+	Key      string
+	Religion string
+	From     string
+	To       string
+}
+
+// HijriHoliday is a holiday on a fixed day of the Hijri calendar lasting
+// Days days, 0 lasts until the end of the month.
+type HijriHoliday struct {
+	Key   string
+	Name  string
+	Month int
+	Day   int
+	Days  int
+}
+
+// HijriHolidays are computed for every Hijri year.
+var HijriHolidays = []HijriHoliday{
+	{Key: "tahun-baru-islam", Name: "Tahun Baru Islam", Month: 1, Day: 1, Days: 1},
+	{Key: "asyura", Name: "Asyura", Month: 1, Day: 10, Days: 1},
+	{Key: "maulid-nabi", Name: "Maulid Nabi Muhammad SAW", Month: 3, Day: 12, Days: 1},
+	{Key: "isra-miraj", Name: "Isra Mi'raj", Month: 7, Day: 27, Days: 1},
+	{Key: "ramadhan", Name: "Ramadhan", Month: 9, Day: 1, Days: 0},
+	{Key: "idul-fitri", Name: "Idul Fitri", Month: 10, Day: 1, Days: 1},
+	{Key: "arafah", Name: "Hari Arafah", Month: 12, Day: 9, Days: 1},
+	{Key: "idul-adha", Name: "Idul Adha", Month: 12, Day: 10, Days: 1},
+}
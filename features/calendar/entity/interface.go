@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+	"tugaskita/utils/hijri"
+)
+
+type CalendarDataInterface interface {
+	CreateHoliday(input HolidayCore) (HolidayCore, error)
+	FindAllHoliday(filter HolidayFilter) ([]HolidayCore, error)
+	FindHolidayById(id string) (HolidayCore, error)
+	UpdateHoliday(id string, data HolidayCore) error
+	DeleteHoliday(id string) error
+	CreateMissingHoliday(data []HolidayCore) (int, error)
+}
+
+type CalendarUseCaseInterface interface {
+	CreateHoliday(input HolidayCore) (HolidayCore, error)
+	FindAllHoliday(filter HolidayFilter) ([]HolidayCore, error)
+	FindHolidayById(id string) (HolidayCore, error)
+	UpdateHoliday(id string, data HolidayCore) error
+	DeleteHoliday(id string) error
+
+	GenerateHijriHolidays(from time.Time, to time.Time) (int, error)
+	HijriDate(date time.Time) hijri.Date
+}
@@ -0,0 +1,43 @@
+package entity
+
+import "tugaskita/features/calendar/model"
+
+func HolidayCoreToHolidayModel(data HolidayCore) model.Holiday {
+	return model.Holiday{
+		Id:          data.Id,
+		Key:         data.Key,
+		Period:      data.Period,
+		Name:        data.Name,
+		Religion:    data.Religion,
+		StartDate:   data.StartDate,
+		EndDate:     data.EndDate,
+		Description: data.Description,
+		Computed:    data.Computed,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func HolidayModelToHolidayCore(data model.Holiday) HolidayCore {
+	return HolidayCore{
+		Id:          data.Id,
+		Key:         data.Key,
+		Period:      data.Period,
+		Name:        data.Name,
+		Religion:    data.Religion,
+		StartDate:   data.StartDate,
+		EndDate:     data.EndDate,
+		Description: data.Description,
+		Computed:    data.Computed,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func ListHolidayModelToHolidayCore(data []model.Holiday) []HolidayCore {
+	dataHoliday := []HolidayCore{}
+	for _, v := range data {
+		dataHoliday = append(dataHoliday, HolidayModelToHolidayCore(v))
+	}
+	return dataHoliday
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+	"tugaskita/features/calendar/dto"
+	"tugaskita/features/calendar/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CalendarController struct {
+	calendarUsecase entity.CalendarUseCaseInterface
+}
+
+func New(calendarUC entity.CalendarUseCaseInterface) *CalendarController {
+	return &CalendarController{
+		calendarUsecase: calendarUC,
+	}
+}
+
+func holidayCore(input dto.HolidayRequest) entity.HolidayCore {
+	return entity.HolidayCore{
+		Key:         input.Key,
+		Period:      input.Period,
+		Name:        input.Name,
+		Religion:    input.Religion,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
+		Description: input.Description,
+	}
+}
+
+func (handler *CalendarController) HijriDate(e echo.Context) error {
+	date := time.Now()
+	if value := e.QueryParam("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "date must be in 'yyyy-mm-dd' format",
+			})
+		}
+		date = parsed
+	}
+
+	result := handler.calendarUsecase.HijriDate(date)
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get hijri date",
+		"data": dto.HijriDateResponse{
+			Date:      date.Format("2006-01-02"),
+			Year:      result.Year,
+			Month:     result.Month,
+			MonthName: result.MonthName(),
+			Day:       result.Day,
+			Text:      result.String(),
+		},
+	})
+}
+
+// ReadAllHoliday lists holidays overlapping from and to, the current year
+// when not given.
+func (handler *CalendarController) ReadAllHoliday(e echo.Context) error {
+	year := time.Now().Format("2006")
+	filter := entity.HolidayFilter{
+		Key:      e.QueryParam("key"),
+		Religion: e.QueryParam("religion"),
+		From:     e.QueryParam("from"),
+		To:       e.QueryParam("to"),
+	}
+	if filter.From == "" {
+		filter.From = year + "-01-01"
+	}
+	if filter.To == "" {
+		filter.To = year + "-12-31"
+	}
+
+	data, err := handler.calendarUsecase.FindAllHoliday(filter)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all holiday",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all holiday",
+		"data":    data,
+	})
+}
+
+func (handler *CalendarController) ReadSpecificHoliday(e echo.Context) error {
+	data, err := handler.calendarUsecase.FindHolidayById(e.Param("id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get holiday",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get holiday",
+		"data":    data,
+	})
+}
+
+func (handler *CalendarController) AddHoliday(e echo.Context) error {
+	input := dto.HolidayRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data, err := handler.calendarUsecase.CreateHoliday(holidayCore(input))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error create holiday",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "succes create holiday",
+		"data":    data,
+	})
+}
+
+func (handler *CalendarController) UpdateHoliday(e echo.Context) error {
+	input := dto.HolidayRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	err := handler.calendarUsecase.UpdateHoliday(e.Param("id"), holidayCore(input))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error update holiday",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success update holiday",
+	})
+}
+
+func (handler *CalendarController) DeleteHoliday(e echo.Context) error {
+	err := handler.calendarUsecase.DeleteHoliday(e.Param("id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error delete holiday",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success delete holiday",
+	})
+}
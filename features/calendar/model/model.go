@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Holiday is a religious holiday or observance period such as Ramadhan or
+// Nyepi. Key identifies the holiday across years and Period the year it
+// belongs to, "1447H" for Hijri holidays. Computed ones come from the Hijri
+// calendar and can be corrected by an admin.
+type Holiday struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	Key         string    `gorm:"column:holiday_key;type:varchar(50);not null;uniqueIndex:idx_holiday_key_period"`
+	Period      string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_holiday_key_period"`
+	Name        string    `gorm:"type:varchar(100);not null"`
+	Religion    string    `gorm:"type:varchar(25);not null;index"`
+	StartDate   string    `gorm:"type:varchar(10);not null;index"`
+	EndDate     string    `gorm:"type:varchar(10);not null"`
+	Description string
+	Computed    bool `gorm:"not null;default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
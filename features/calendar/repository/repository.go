@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"errors"
+	"tugaskita/features/calendar/entity"
+	"tugaskita/features/calendar/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) entity.CalendarDataInterface {
+	return &CalendarRepository{
+		db: db,
+	}
+}
+
+// CreateHoliday implements entity.CalendarDataInterface.
+func (calendarRepo *CalendarRepository) CreateHoliday(input entity.HolidayCore) (entity.HolidayCore, error) {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return entity.HolidayCore{}, UUIDerr
+	}
+
+	data := entity.HolidayCoreToHolidayModel(input)
+	data.Id = newUUID
+	tx := calendarRepo.db.Create(&data)
+	if tx.Error != nil {
+		return entity.HolidayCore{}, tx.Error
+	}
+	return entity.HolidayModelToHolidayCore(data), nil
+}
+
+// FindAllHoliday implements entity.CalendarDataInterface.
+func (calendarRepo *CalendarRepository) FindAllHoliday(filter entity.HolidayFilter) ([]entity.HolidayCore, error) {
+	var holidays []model.Holiday
+
+	query := calendarRepo.db.Order("start_date")
+	if filter.Key != "" {
+		query = query.Where("holiday_key = ?", filter.Key)
+	}
+	if filter.Religion != "" {
+		query = query.Where("religion = ?", filter.Religion)
+	}
+	if filter.From != "" {
+		query = query.Where("end_date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		query = query.Where("start_date <= ?", filter.To)
+	}
+
+	errData := query.Find(&holidays).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	return entity.ListHolidayModelToHolidayCore(holidays), nil
+}
+
+// FindHolidayById implements entity.CalendarDataInterface.
+func (calendarRepo *CalendarRepository) FindHolidayById(id string) (entity.HolidayCore, error) {
+	holiday := model.Holiday{}
+
+	tx := calendarRepo.db.Where("id = ?", id).First(&holiday)
+	if tx.Error != nil {
+		return entity.HolidayCore{}, tx.Error
+	}
+
+	return entity.HolidayModelToHolidayCore(holiday), nil
+}
+
+// UpdateHoliday implements entity.CalendarDataInterface.
+func (calendarRepo *CalendarRepository) UpdateHoliday(id string, data entity.HolidayCore) error {
+	tx := calendarRepo.db.Model(&model.Holiday{}).Where("id = ?", id).Updates(map[string]any{
+		"name":        data.Name,
+		"religion":    data.Religion,
+		"start_date":  data.StartDate,
+		"end_date":    data.EndDate,
+		"description": data.Description,
+		"computed":    data.Computed,
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.New("holiday not found")
+	}
+
+	return nil
+}
+
+// DeleteHoliday implements entity.CalendarDataInterface.
+func (calendarRepo *CalendarRepository) DeleteHoliday(id string) error {
+	tx := calendarRepo.db.Where("id = ?", id).Delete(&model.Holiday{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.New("holiday not found")
+	}
+
+	return nil
+}
+
+// CreateMissingHoliday implements entity.CalendarDataInterface. Holidays
+// whose key and period already exist are left as they are so corrections
+// made by an admin are kept.
+func (calendarRepo *CalendarRepository) CreateMissingHoliday(data []entity.HolidayCore) (int, error) {
+	created := 0
+
+	errTx := calendarRepo.db.Transaction(func(tx *gorm.DB) error {
+		for _, v := range data {
+			holiday := entity.HolidayCoreToHolidayModel(v)
+			holiday.Id = uuid.New()
+
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&holiday)
+			if result.Error != nil {
+				return result.Error
+			}
+			created += int(result.RowsAffected)
+		}
+		return nil
+	})
+	if errTx != nil {
+		return 0, errTx
+	}
+
+	return created, nil
+}
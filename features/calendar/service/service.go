@@ -0,0 +1,197 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+	"tugaskita/features/calendar/entity"
+	"tugaskita/utils/hijri"
+)
+
+var holidayKeyPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type CalendarService struct {
+	CalendarRepo entity.CalendarDataInterface
+}
+
+func NewCalendarService(calendarRepo entity.CalendarDataInterface) entity.CalendarUseCaseInterface {
+	return &CalendarService{
+		CalendarRepo: calendarRepo,
+	}
+}
+
+func validateHoliday(input entity.HolidayCore) error {
+	if input.Name == "" || input.Religion == "" {
+		return errors.New("name and religion can't be empty")
+	}
+
+	if len(input.Name) > 100 {
+		return errors.New("holiday name max 100 character")
+	}
+
+	if len(input.Religion) > 25 {
+		return errors.New("religion max 25 character")
+	}
+
+	start, errStart := time.Parse("2006-01-02", input.StartDate)
+	if errStart != nil {
+		return errors.New("start date must be in 'yyyy-mm-dd' format")
+	}
+
+	end, errEnd := time.Parse("2006-01-02", input.EndDate)
+	if errEnd != nil {
+		return errors.New("end date must be in 'yyyy-mm-dd' format")
+	}
+
+	if end.Before(start) {
+		return errors.New("end date can't be before start date")
+	}
+
+	return nil
+}
+
+// CreateHoliday implements entity.CalendarUseCaseInterface. The period
+// defaults to the year of the start date.
+func (calendarUC *CalendarService) CreateHoliday(input entity.HolidayCore) (entity.HolidayCore, error) {
+	if !holidayKeyPattern.MatchString(input.Key) || len(input.Key) > 50 {
+		return entity.HolidayCore{}, errors.New("key must be lowercase words separated by '-', e.g. 'nyepi'")
+	}
+
+	if input.EndDate == "" {
+		input.EndDate = input.StartDate
+	}
+
+	errValidate := validateHoliday(input)
+	if errValidate != nil {
+		return entity.HolidayCore{}, errValidate
+	}
+
+	if input.Period == "" {
+		input.Period = input.StartDate[:4]
+	}
+	input.Computed = false
+
+	existing, err := calendarUC.CalendarRepo.FindAllHoliday(entity.HolidayFilter{Key: input.Key})
+	if err != nil {
+		return entity.HolidayCore{}, err
+	}
+	for _, v := range existing {
+		if v.Period == input.Period {
+			return entity.HolidayCore{}, errors.New("holiday " + input.Key + " already exists for " + input.Period)
+		}
+	}
+
+	return calendarUC.CalendarRepo.CreateHoliday(input)
+}
+
+// FindAllHoliday implements entity.CalendarUseCaseInterface. Hijri holidays
+// of the requested range are computed first when a range is given.
+func (calendarUC *CalendarService) FindAllHoliday(filter entity.HolidayFilter) ([]entity.HolidayCore, error) {
+	if filter.From != "" && filter.To != "" {
+		from, errFrom := time.Parse("2006-01-02", filter.From)
+		to, errTo := time.Parse("2006-01-02", filter.To)
+		if errFrom != nil || errTo != nil {
+			return nil, errors.New("from and to must be in 'yyyy-mm-dd' format")
+		}
+
+		if _, err := calendarUC.GenerateHijriHolidays(from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := calendarUC.CalendarRepo.FindAllHoliday(filter)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindHolidayById implements entity.CalendarUseCaseInterface.
+func (calendarUC *CalendarService) FindHolidayById(id string) (entity.HolidayCore, error) {
+	data, err := calendarUC.CalendarRepo.FindHolidayById(id)
+	if err != nil {
+		return entity.HolidayCore{}, errors.New("holiday not found")
+	}
+
+	return data, nil
+}
+
+// UpdateHoliday implements entity.CalendarUseCaseInterface. Correcting a
+// computed holiday keeps it from being recomputed.
+func (calendarUC *CalendarService) UpdateHoliday(id string, data entity.HolidayCore) error {
+	current, err := calendarUC.CalendarRepo.FindHolidayById(id)
+	if err != nil {
+		return errors.New("holiday not found")
+	}
+
+	if data.Name == "" {
+		data.Name = current.Name
+	}
+	if data.Religion == "" {
+		data.Religion = current.Religion
+	}
+	if data.EndDate == "" {
+		data.EndDate = data.StartDate
+	}
+
+	errValidate := validateHoliday(data)
+	if errValidate != nil {
+		return errValidate
+	}
+	data.Computed = false
+
+	return calendarUC.CalendarRepo.UpdateHoliday(id, data)
+}
+
+// DeleteHoliday implements entity.CalendarUseCaseInterface.
+func (calendarUC *CalendarService) DeleteHoliday(id string) error {
+	current, err := calendarUC.CalendarRepo.FindHolidayById(id)
+	if err != nil {
+		return errors.New("holiday not found")
+	}
+
+	for _, v := range entity.HijriHolidays {
+		if v.Key == current.Key {
+			return errors.New("hijri holidays can't be deleted, change their date instead")
+		}
+	}
+
+	return calendarUC.CalendarRepo.DeleteHoliday(id)
+}
+
+// GenerateHijriHolidays implements entity.CalendarUseCaseInterface. It
+// stores the Hijri holidays of every Hijri year overlapping [from, to] that
+// aren't stored yet and returns how many were created.
+func (calendarUC *CalendarService) GenerateHijriHolidays(from time.Time, to time.Time) (int, error) {
+	first, last := hijri.FromGregorian(from).Year, hijri.FromGregorian(to).Year
+
+	data := []entity.HolidayCore{}
+	for year := first; year <= last; year++ {
+		for _, v := range entity.HijriHolidays {
+			days := v.Days
+			if days == 0 {
+				days = hijri.MonthLength(year, v.Month) - v.Day + 1
+			}
+
+			start := hijri.ToGregorian(year, v.Month, v.Day)
+			data = append(data, entity.HolidayCore{
+				Key:       v.Key,
+				Period:    strconv.Itoa(year) + "H",
+				Name:      v.Name,
+				Religion:  "Islam",
+				StartDate: start.Format("2006-01-02"),
+				EndDate:   start.AddDate(0, 0, days-1).Format("2006-01-02"),
+				Computed:  true,
+			})
+		}
+	}
+
+	return calendarUC.CalendarRepo.CreateMissingHoliday(data)
+}
+
+// HijriDate implements entity.CalendarUseCaseInterface.
+func (calendarUC *CalendarService) HijriDate(date time.Time) hijri.Date {
+	return hijri.FromGregorian(date)
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	JobRunSuccess = "success"
+	JobRunFailed  = "failed"
+)
+
+type JobRunCore struct {
+	Id          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Attempt     int       `json:"attempt"`
+	Status      string    `json:"status"`
+	Created     int       `json:"created"`
+	Error       string    `json:"error"`
+	Holder      string    `json:"holder"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+type JobLockCore struct {
+	Name        string    `json:"name"`
+	Holder      string    `json:"holder"`
+	Activation  string    `json:"activation"`
+	Done        bool      `json:"done"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+type JobRunFilter struct {
+	Name   string
+	Status string
+	Limit  int
+}
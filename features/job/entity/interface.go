@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+	"tugaskita/utils/scheduler"
+)
+
+// JobDataInterface stores the run history and locks of the scheduler, it
+// implements scheduler.Recorder and scheduler.Locker.
+type JobDataInterface interface {
+	Record(run scheduler.Run) error
+	Acquire(name string, at time.Time, ttl time.Duration) (bool, error)
+	Release(name string, at time.Time, done bool) error
+
+	FindAllJobRun(filter JobRunFilter) ([]JobRunCore, error)
+	FindAllJobLock() ([]JobLockCore, error)
+}
+
+type JobUseCaseInterface interface {
+	FindAllJobRun(filter JobRunFilter) ([]JobRunCore, error)
+	FindAllJobLock() ([]JobLockCore, error)
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "tugaskita/features/job/model"
+
+func JobRunModelToJobRunCore(data model.JobRun) JobRunCore {
+	return JobRunCore{
+		Id:          data.Id,
+		Name:        data.Name,
+		ScheduledAt: data.ScheduledAt,
+		Attempt:     data.Attempt,
+		Status:      data.Status,
+		Created:     data.Created,
+		Error:       data.Error,
+		Holder:      data.Holder,
+		StartedAt:   data.StartedAt,
+		FinishedAt:  data.FinishedAt,
+	}
+}
+
+func JobLockModelToJobLockCore(data model.JobLock) JobLockCore {
+	return JobLockCore{
+		Name:        data.Name,
+		Holder:      data.Holder,
+		Activation:  data.Activation,
+		Done:        data.Done,
+		LockedUntil: data.LockedUntil,
+	}
+}
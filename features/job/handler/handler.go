@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"tugaskita/features/job/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+type JobController struct {
+	jobUsecase entity.JobUseCaseInterface
+}
+
+func New(jobUC entity.JobUseCaseInterface) *JobController {
+	return &JobController{
+		jobUsecase: jobUC,
+	}
+}
+
+func (handler *JobController) ReadAllJobRun(e echo.Context) error {
+	limit, _ := strconv.Atoi(e.QueryParam("limit"))
+
+	data, err := handler.jobUsecase.FindAllJobRun(entity.JobRunFilter{
+		Name:   e.QueryParam("name"),
+		Status: e.QueryParam("status"),
+		Limit:  limit,
+	})
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get job runs",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get job runs",
+		"data":    data,
+	})
+}
+
+func (handler *JobController) ReadAllJobLock(e echo.Context) error {
+	data, err := handler.jobUsecase.FindAllJobLock()
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get job locks",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get job locks",
+		"data":    data,
+	})
+}
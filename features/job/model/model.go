@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobRun is one attempt of a background job activation.
+type JobRun struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null;index"`
+	ScheduledAt time.Time `gorm:"not null;index"`
+	Attempt     int       `gorm:"not null"`
+	Status      string    `gorm:"type:varchar(20);not null;index"`
+	Created     int       `gorm:"not null;default:0"`
+	Error       string    `gorm:"type:text"`
+	Holder      string    `gorm:"type:varchar(100)"`
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
+
+// JobLock is held by the instance running a job. Activation is the run it
+// was taken for and Done whether that run succeeded.
+type JobLock struct {
+	Name        string `gorm:"type:varchar(100);primaryKey;not null"`
+	Holder      string `gorm:"type:varchar(100);not null"`
+	Activation  string `gorm:"type:varchar(35);not null"`
+	Done        bool   `gorm:"not null;default:false"`
+	LockedUntil time.Time
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"tugaskita/features/job/entity"
+	"tugaskita/features/job/model"
+	"tugaskita/utils/scheduler"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository struct {
+	db     *gorm.DB
+	holder string
+}
+
+func NewJobRepository(db *gorm.DB) entity.JobDataInterface {
+	host, _ := os.Hostname()
+
+	return &JobRepository{
+		db:     db,
+		holder: fmt.Sprintf("%s:%d", host, os.Getpid()),
+	}
+}
+
+// Record implements entity.JobDataInterface.
+func (jobRepo *JobRepository) Record(run scheduler.Run) error {
+	data := model.JobRun{
+		Id:          uuid.New(),
+		Name:        run.Name,
+		ScheduledAt: run.At,
+		Attempt:     run.Attempt,
+		Status:      entity.JobRunSuccess,
+		Created:     run.Created,
+		Holder:      jobRepo.holder,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+	}
+	if run.Err != nil {
+		data.Status = entity.JobRunFailed
+		data.Error = run.Err.Error()
+	}
+
+	return jobRepo.db.Create(&data).Error
+}
+
+// Acquire implements entity.JobDataInterface. The lock is taken when it is
+// new, or expired and its activation didn't already succeed.
+func (jobRepo *JobRepository) Acquire(name string, at time.Time, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	activation := at.Format(time.RFC3339)
+
+	lock := model.JobLock{
+		Name:        name,
+		Holder:      jobRepo.holder,
+		Activation:  activation,
+		LockedUntil: now.Add(ttl),
+	}
+	tx := jobRepo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	if tx.RowsAffected == 1 {
+		return true, nil
+	}
+
+	tx = jobRepo.db.Model(&model.JobLock{}).
+		Where("name = ? AND locked_until < ? AND (activation <> ? OR done = ?)", name, now, activation, false).
+		Updates(map[string]any{
+			"holder":       jobRepo.holder,
+			"activation":   activation,
+			"done":         false,
+			"locked_until": now.Add(ttl),
+		})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+// Release implements entity.JobDataInterface.
+func (jobRepo *JobRepository) Release(name string, at time.Time, done bool) error {
+	return jobRepo.db.Model(&model.JobLock{}).
+		Where("name = ? AND holder = ? AND activation = ?", name, jobRepo.holder, at.Format(time.RFC3339)).
+		Updates(map[string]any{
+			"done":         done,
+			"locked_until": time.Now(),
+		}).Error
+}
+
+// FindAllJobRun implements entity.JobDataInterface.
+func (jobRepo *JobRepository) FindAllJobRun(filter entity.JobRunFilter) ([]entity.JobRunCore, error) {
+	var runs []model.JobRun
+
+	query := jobRepo.db.Order("started_at desc").Limit(filter.Limit)
+	if filter.Name != "" {
+		query = query.Where("name = ?", filter.Name)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	errData := query.Find(&runs).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataRun := []entity.JobRunCore{}
+	for _, v := range runs {
+		dataRun = append(dataRun, entity.JobRunModelToJobRunCore(v))
+	}
+	return dataRun, nil
+}
+
+// FindAllJobLock implements entity.JobDataInterface.
+func (jobRepo *JobRepository) FindAllJobLock() ([]entity.JobLockCore, error) {
+	var locks []model.JobLock
+
+	errData := jobRepo.db.Order("name").Find(&locks).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataLock := []entity.JobLockCore{}
+	for _, v := range locks {
+		dataLock = append(dataLock, entity.JobLockModelToJobLockCore(v))
+	}
+	return dataLock, nil
+}
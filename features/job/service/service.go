@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"tugaskita/features/job/entity"
+)
+
+type JobService struct {
+	JobRepo entity.JobDataInterface
+}
+
+func NewJobService(jobRepo entity.JobDataInterface) entity.JobUseCaseInterface {
+	return &JobService{
+		JobRepo: jobRepo,
+	}
+}
+
+// FindAllJobRun implements entity.JobUseCaseInterface. At most 500 runs
+// are returned, 50 by default.
+func (jobUC *JobService) FindAllJobRun(filter entity.JobRunFilter) ([]entity.JobRunCore, error) {
+	if filter.Status != "" && filter.Status != entity.JobRunSuccess && filter.Status != entity.JobRunFailed {
+		return nil, errors.New("status must be success or failed")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 500 {
+		filter.Limit = 500
+	}
+
+	data, err := jobUC.JobRepo.FindAllJobRun(filter)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindAllJobLock implements entity.JobUseCaseInterface.
+func (jobUC *JobService) FindAllJobLock() ([]entity.JobLockCore, error) {
+	data, err := jobUC.JobRepo.FindAllJobLock()
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefReward is the RefType of the notifications about a reward exchange,
+// the ones about a submission use its kind.
+const RefReward = "Reward"
+
+type NotificationCore struct {
+	Id        uuid.UUID  `json:"id"`
+	UserId    string     `json:"user_id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	RefType   string     `json:"ref_type"`
+	RefId     string     `json:"ref_id"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type NotificationFilter struct {
+	UserId string
+	Unread bool
+	Limit  int
+}
+
+// StreamViewer is who a stream of events is for, Reviewer tells whether
+// they may review submissions.
+type StreamViewer struct {
+	UserId   string
+	Religion string
+	Reviewer bool
+}
+
+// StreamBuffer is how many events a stream can fall behind before it is
+// closed.
+const StreamBuffer = 32
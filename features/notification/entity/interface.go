@@ -0,0 +1,23 @@
+package entity
+
+import "tugaskita/utils/event"
+
+type NotificationDataInterface interface {
+	CreateNotification(data []NotificationCore) error
+	FindStudentIds(religion string) ([]string, error)
+
+	FindAllNotification(filter NotificationFilter) ([]NotificationCore, error)
+	CountUnreadNotification(userId string) (int, error)
+	MarkNotificationRead(userId string, id string) (bool, error)
+	MarkAllNotificationRead(userId string) (int, error)
+}
+
+type NotificationUseCaseInterface interface {
+	Notify(e event.Event) error
+	Subscribe(viewer StreamViewer) (<-chan event.Event, func())
+
+	FindAllNotification(filter NotificationFilter) ([]NotificationCore, error)
+	CountUnreadNotification(userId string) (int, error)
+	MarkNotificationRead(userId string, id string) error
+	MarkAllNotificationRead(userId string) (int, error)
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "tugaskita/features/notification/model"
+
+func NotificationCoreToNotificationModel(data NotificationCore) model.Notification {
+	return model.Notification{
+		Id:      data.Id,
+		UserId:  data.UserId,
+		Type:    data.Type,
+		Title:   data.Title,
+		Message: data.Message,
+		RefType: data.RefType,
+		RefId:   data.RefId,
+	}
+}
+
+func NotificationModelToNotificationCore(data model.Notification) NotificationCore {
+	return NotificationCore{
+		Id:        data.Id,
+		UserId:    data.UserId,
+		Type:      data.Type,
+		Title:     data.Title,
+		Message:   data.Message,
+		RefType:   data.RefType,
+		RefId:     data.RefId,
+		ReadAt:    data.ReadAt,
+		CreatedAt: data.CreatedAt,
+	}
+}
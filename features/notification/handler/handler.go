@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"tugaskita/features/notification/entity"
+	middleware "tugaskita/utils/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationController struct {
+	notificationUsecase entity.NotificationUseCaseInterface
+}
+
+func New(notificationUC entity.NotificationUseCaseInterface) *NotificationController {
+	return &NotificationController{
+		notificationUsecase: notificationUC,
+	}
+}
+
+func (handler *NotificationController) ReadAllNotification(e echo.Context) error {
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	limit, _ := strconv.Atoi(e.QueryParam("limit"))
+	unread, _ := strconv.ParseBool(e.QueryParam("unread"))
+
+	data, err := handler.notificationUsecase.FindAllNotification(entity.NotificationFilter{
+		UserId: userId,
+		Unread: unread,
+		Limit:  limit,
+	})
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get notifications",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get notifications",
+		"data":    data,
+	})
+}
+
+func (handler *NotificationController) CountUnreadNotification(e echo.Context) error {
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	count, err := handler.notificationUsecase.CountUnreadNotification(userId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get unread notifications",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get unread notifications",
+		"data":    map[string]any{"unread": count},
+	})
+}
+
+func (handler *NotificationController) MarkNotificationRead(e echo.Context) error {
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	err := handler.notificationUsecase.MarkNotificationRead(userId, e.Param("id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error read notification",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success read notification",
+	})
+}
+
+func (handler *NotificationController) MarkAllNotificationRead(e echo.Context) error {
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	count, err := handler.notificationUsecase.MarkAllNotificationRead(userId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error read notifications",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success read notifications",
+		"data":    map[string]any{"read": count},
+	})
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"tugaskita/features/notification/entity"
+	"tugaskita/utils/authz"
+	middleware "tugaskita/utils/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// streamPing is how often a comment is sent on an idle stream, it keeps
+// proxies from closing the connection.
+const streamPing = 25 * time.Second
+
+// StreamEvent sends the events of the user as Server-Sent Events until the
+// client disconnects or the token expires. The stream ends with a
+// stream.closed event when the client fell behind or the token expired,
+// the client should reconnect, with a fresh token then, and reload.
+func (handler *NotificationController) StreamEvent(e echo.Context) error {
+	userId, _, religion, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	_, _, expiredAt, errToken := middleware.ExtractTokenId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	events, unsubscribe := handler.notificationUsecase.Subscribe(entity.StreamViewer{
+		UserId:   userId,
+		Religion: religion,
+		Reviewer: authz.Can(e, authz.TaskReview),
+	})
+	defer unsubscribe()
+
+	res := e.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// nginx buffers responses unless told not to
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	fmt.Fprint(res, ": connected\n\n")
+	res.Flush()
+
+	ping := time.NewTicker(streamPing)
+	defer ping.Stop()
+
+	var expired <-chan time.Time
+	if !expiredAt.IsZero() {
+		timer := time.NewTimer(time.Until(expiredAt))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	closeStream := func(reason string) error {
+		fmt.Fprintf(res, "event: stream.closed\ndata: {\"reason\":%q}\n\n", reason)
+		res.Flush()
+		return nil
+	}
+
+	for {
+		select {
+		case <-e.Request().Context().Done():
+			return nil
+		case <-expired:
+			return closeStream("token expired")
+		case <-ping.C:
+			fmt.Fprint(res, ": ping\n\n")
+			res.Flush()
+		case v, ok := <-events:
+			if !ok {
+				return closeStream("too many events")
+			}
+
+			data, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(res, "event: %s\ndata: %s\n\n", v.Type, data)
+			res.Flush()
+		}
+	}
+}
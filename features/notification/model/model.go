@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is a message shown to a user in the app. RefType and RefId
+// point to what it is about, e.g. a task upload, and ReadAt is nil until
+// the user read it.
+type Notification struct {
+	Id        uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId    string    `gorm:"type:varchar(50);not null;index"`
+	Type      string    `gorm:"type:varchar(50);not null"`
+	Title     string    `gorm:"type:varchar(100);not null"`
+	Message   string    `gorm:"type:text"`
+	RefType   string    `gorm:"type:varchar(20)"`
+	RefId     string    `gorm:"type:varchar(50)"`
+	ReadAt    *time.Time
+	CreatedAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"time"
+	"tugaskita/features/notification/entity"
+	"tugaskita/features/notification/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) entity.NotificationDataInterface {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+// CreateNotification implements entity.NotificationDataInterface.
+func (notificationRepo *NotificationRepository) CreateNotification(data []entity.NotificationCore) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	dataNotification := []model.Notification{}
+	for _, v := range data {
+		notification := entity.NotificationCoreToNotificationModel(v)
+		notification.Id = uuid.New()
+		dataNotification = append(dataNotification, notification)
+	}
+
+	return notificationRepo.db.CreateInBatches(&dataNotification, 500).Error
+}
+
+// FindStudentIds implements entity.NotificationDataInterface. Every
+// student is returned when religion is empty.
+func (notificationRepo *NotificationRepository) FindStudentIds(religion string) ([]string, error) {
+	var ids []string
+
+	query := notificationRepo.db.Table("users").Where("role = ?", "user")
+	if religion != "" {
+		query = query.Where("religion = ?", religion)
+	}
+
+	errData := query.Pluck("id", &ids).Error
+	if errData != nil {
+		return nil, errData
+	}
+	return ids, nil
+}
+
+// FindAllNotification implements entity.NotificationDataInterface.
+func (notificationRepo *NotificationRepository) FindAllNotification(filter entity.NotificationFilter) ([]entity.NotificationCore, error) {
+	var notifications []model.Notification
+
+	query := notificationRepo.db.Where("user_id = ?", filter.UserId).Order("created_at desc").Limit(filter.Limit)
+	if filter.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	errData := query.Find(&notifications).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataNotification := []entity.NotificationCore{}
+	for _, v := range notifications {
+		dataNotification = append(dataNotification, entity.NotificationModelToNotificationCore(v))
+	}
+	return dataNotification, nil
+}
+
+// CountUnreadNotification implements entity.NotificationDataInterface.
+func (notificationRepo *NotificationRepository) CountUnreadNotification(userId string) (int, error) {
+	var count int64
+
+	errData := notificationRepo.db.Model(&model.Notification{}).Where("user_id = ? AND read_at IS NULL", userId).Count(&count).Error
+	if errData != nil {
+		return 0, errData
+	}
+	return int(count), nil
+}
+
+// MarkNotificationRead implements entity.NotificationDataInterface. It
+// reports whether the user has the notification, reading it again keeps
+// the first read time.
+func (notificationRepo *NotificationRepository) MarkNotificationRead(userId string, id string) (bool, error) {
+	var count int64
+
+	errData := notificationRepo.db.Model(&model.Notification{}).Where("id = ? AND user_id = ?", id, userId).Count(&count).Error
+	if errData != nil || count == 0 {
+		return false, errData
+	}
+
+	errUpdate := notificationRepo.db.Model(&model.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userId).
+		Update("read_at", time.Now()).Error
+	if errUpdate != nil {
+		return false, errUpdate
+	}
+	return true, nil
+}
+
+// MarkAllNotificationRead implements entity.NotificationDataInterface and
+// returns how many notifications were unread.
+func (notificationRepo *NotificationRepository) MarkAllNotificationRead(userId string) (int, error) {
+	tx := notificationRepo.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Update("read_at", time.Now())
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	return int(tx.RowsAffected), nil
+}
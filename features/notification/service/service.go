@@ -0,0 +1,236 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"tugaskita/features/notification/entity"
+	"tugaskita/utils/event"
+	"tugaskita/utils/review"
+)
+
+// Types are the events stored as notifications.
+var Types = []string{
+	event.SubmissionReviewed,
+	event.RewardReviewed,
+	event.PenaltyCreated,
+	event.ReligionTasksGenerated,
+	event.PointsReset,
+}
+
+type NotificationService struct {
+	NotificationRepo entity.NotificationDataInterface
+}
+
+func NewNotificationService(notificationRepo entity.NotificationDataInterface) entity.NotificationUseCaseInterface {
+	return &NotificationService{
+		NotificationRepo: notificationRepo,
+	}
+}
+
+// compose returns the notification of an event, without its user. It is
+// false for the events users aren't notified of.
+func compose(e event.Event) (entity.NotificationCore, bool) {
+	notification := entity.NotificationCore{Type: e.Type}
+
+	switch data := e.Data.(type) {
+	case event.Submission:
+		notification.RefType, notification.RefId = data.Kind, data.Id
+		switch data.Status {
+		case review.Approved:
+			notification.Title = "Submission approved"
+			notification.Message = fmt.Sprintf("%q was approved, you earned %d points", data.Title, data.Point)
+		case review.NeedsRevision:
+			notification.Title = "Submission needs a revision"
+			notification.Message = fmt.Sprintf("%q needs a revision: %s", data.Title, data.Message)
+		case review.Rejected:
+			notification.Title = "Submission rejected"
+			notification.Message = fmt.Sprintf("%q was rejected: %s", data.Title, data.Message)
+		default:
+			return entity.NotificationCore{}, false
+		}
+	case event.Reward:
+		notification.RefType, notification.RefId = entity.RefReward, data.Id
+		if data.Status == "Diterima" {
+			notification.Title = "Reward exchange accepted"
+			notification.Message = fmt.Sprintf("Your exchange of %d %s was accepted", data.Amount, data.RewardName)
+		} else {
+			notification.Title = "Reward exchange rejected"
+			notification.Message = fmt.Sprintf("Your exchange of %d %s was rejected, the points were refunded", data.Amount, data.RewardName)
+		}
+	case event.Penalty:
+		notification.Title = "Penalty received"
+		notification.Message = fmt.Sprintf("%s, %d points were deducted", data.Description, data.Point)
+	case event.ReligionTasks:
+		notification.Title = "New religion tasks"
+		notification.Message = fmt.Sprintf("%d new religion tasks are available", data.Count)
+	case event.PointReset:
+		notification.Title = "Points reset"
+		notification.Message = fmt.Sprintf("The %s points of %s were reset, a new period starts", data.Kind, data.Period)
+	default:
+		return entity.NotificationCore{}, false
+	}
+
+	return notification, true
+}
+
+// Notify implements entity.NotificationUseCaseInterface. An event about
+// many students is stored once for each of them, a religion task event
+// for the students of that religion.
+func (notificationUC *NotificationService) Notify(e event.Event) error {
+	notification, ok := compose(e)
+	if !ok {
+		return nil
+	}
+
+	userIds := []string{e.UserId}
+	if e.UserId == "" {
+		religion := ""
+		if data, isReligion := e.Data.(event.ReligionTasks); isReligion {
+			religion = data.Religion
+		}
+
+		ids, err := notificationUC.NotificationRepo.FindStudentIds(religion)
+		if err != nil {
+			return err
+		}
+		userIds = ids
+	}
+
+	data := []entity.NotificationCore{}
+	for _, v := range userIds {
+		notification.UserId = v
+		data = append(data, notification)
+	}
+
+	return notificationUC.NotificationRepo.CreateNotification(data)
+}
+
+// reviewQueue are the events changing the review queue, streamed to every
+// reviewer.
+var reviewQueue = map[string]bool{
+	event.SubmissionCreated:     true,
+	event.SubmissionResubmitted: true,
+	event.SubmissionReviewed:    true,
+}
+
+// streamed returns the events a viewer is sent for e. Students get the
+// events about them and the ones about every student, reviewers the events
+// of the review queue as well. A change of points is followed by
+// LeaderboardChanged for everyone.
+func streamed(e event.Event, viewer entity.StreamViewer) []event.Event {
+	events := []event.Event{}
+
+	switch {
+	case e.UserId != "" && e.UserId == viewer.UserId:
+		events = append(events, e)
+	case viewer.Reviewer && reviewQueue[e.Type]:
+		events = append(events, e)
+	case e.Type == event.PointsReset:
+		events = append(events, e)
+	case e.Type == event.ReligionTasksGenerated && !viewer.Reviewer:
+		if data, ok := e.Data.(event.ReligionTasks); ok && data.Religion == viewer.Religion {
+			events = append(events, e)
+		}
+	}
+
+	if e.Type == event.PointsChanged || e.Type == event.PointsReset {
+		events = append(events, event.Event{Type: event.LeaderboardChanged, At: e.At})
+	}
+	return events
+}
+
+// Subscribe implements entity.NotificationUseCaseInterface. The channel is
+// closed by the returned function, or when the viewer falls more than
+// entity.StreamBuffer events behind, they should reload what they show
+// then.
+func (notificationUC *NotificationService) Subscribe(viewer entity.StreamViewer) (<-chan event.Event, func()) {
+	events := make(chan event.Event, entity.StreamBuffer)
+
+	var mu sync.Mutex
+	closed := false
+	closeEvents := func() {
+		if !closed {
+			closed = true
+			close(events)
+		}
+	}
+
+	// the handler can still run after unsubscribe returned, closed keeps
+	// it from sending on the closed channel
+	unsubscribe := event.Subscribe(func(e event.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+
+		for _, v := range streamed(e, viewer) {
+			select {
+			case events <- v:
+			default:
+				closeEvents()
+				return
+			}
+		}
+	})
+
+	return events, func() {
+		unsubscribe()
+		mu.Lock()
+		closeEvents()
+		mu.Unlock()
+	}
+}
+
+// FindAllNotification implements entity.NotificationUseCaseInterface. The
+// newest come first, at most 200 and 50 by default.
+func (notificationUC *NotificationService) FindAllNotification(filter entity.NotificationFilter) ([]entity.NotificationCore, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+
+	data, err := notificationUC.NotificationRepo.FindAllNotification(filter)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// CountUnreadNotification implements entity.NotificationUseCaseInterface.
+func (notificationUC *NotificationService) CountUnreadNotification(userId string) (int, error) {
+	count, err := notificationUC.NotificationRepo.CountUnreadNotification(userId)
+	if err != nil {
+		return 0, errors.New("error get data")
+	}
+
+	return count, nil
+}
+
+// MarkNotificationRead implements entity.NotificationUseCaseInterface.
+func (notificationUC *NotificationService) MarkNotificationRead(userId string, id string) error {
+	found, err := notificationUC.NotificationRepo.MarkNotificationRead(userId, id)
+	if err != nil {
+		return errors.New("failed mark notification as read")
+	}
+
+	if !found {
+		return errors.New("notification not found")
+	}
+
+	return nil
+}
+
+// MarkAllNotificationRead implements entity.NotificationUseCaseInterface.
+func (notificationUC *NotificationService) MarkAllNotificationRead(userId string) (int, error) {
+	count, err := notificationUC.NotificationRepo.MarkAllNotificationRead(userId)
+	if err != nil {
+		return 0, errors.New("failed mark notifications as read")
+	}
+
+	return count, nil
+}
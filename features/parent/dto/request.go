@@ -0,0 +1,12 @@
+package dto
+
+type ParentRequest struct {
+	Name     string `json:"name" form:"name"`
+	Email    string `json:"email" form:"email"`
+	Password string `json:"password" form:"password"`
+	Address  string `json:"address" form:"address"`
+}
+
+type LinkStudentRequest struct {
+	StudentId string `json:"student_id" form:"student_id"`
+}
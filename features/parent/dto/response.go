@@ -0,0 +1,13 @@
+package dto
+
+type ChildResponse struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Image      string `json:"image"`
+	Thumbnail  string `json:"thumbnail"`
+	School     string `json:"school"`
+	Class      string `json:"class"`
+	Religion   string `json:"religion"`
+	Point      int    `json:"point"`
+	TotalPoint int    `json:"total_point"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+	task "tugaskita/features/task/entity"
+
+	"github.com/google/uuid"
+)
+
+type ParentStudentCore struct {
+	Id        uuid.UUID `json:"id"`
+	ParentId  string    `json:"parent_id"`
+	StudentId string    `json:"student_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ChildTaskHistoryCore groups every kind of task a child has submitted.
+type ChildTaskHistoryCore struct {
+	Tasks            []task.UserTaskUploadCore         `json:"tasks"`
+	TaskRequests     []task.UserTaskSubmissionCore     `json:"task_requests"`
+	ReligionTasks    []task.UserReligionTaskUploadCore `json:"religion_tasks"`
+	ReligionRequests []task.UserReligionReqTaskCore    `json:"religion_requests"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	penalty "tugaskita/features/penalty/entity"
+	reward "tugaskita/features/reward/entity"
+	user "tugaskita/features/user/entity"
+)
+
+type ParentDataInterface interface {
+	CreateParent(data user.UserCore) error
+	LinkStudent(parentId string, studentId string) error
+	UnlinkStudent(parentId string, studentId string) error
+	FindChildren(parentId string) ([]user.UserCore, error)
+	IsLinked(parentId string, studentId string) (bool, error)
+}
+
+type ParentUseCaseInterface interface {
+	CreateParent(data user.UserCore) error
+	LinkStudent(parentId string, studentId string) error
+	UnlinkStudent(parentId string, studentId string) error
+	FindChildren(parentId string) ([]user.UserCore, error)
+	CanMonitor(parentId string, studentId string) (bool, error)
+
+	FindChildPointHistory(studentId string) ([]user.UserPointCore, error)
+	FindChildPenaltyHistory(studentId string) ([]penalty.PenaltyCore, error)
+	FindChildTaskHistory(studentId string) (ChildTaskHistoryCore, error)
+	FindChildRewardHistory(studentId string) ([]reward.UserRewardRequestCore, error)
+}
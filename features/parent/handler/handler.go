@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"net/http"
+	"tugaskita/features/parent/dto"
+	"tugaskita/features/parent/entity"
+	penalty "tugaskita/features/penalty/entity"
+	reward "tugaskita/features/reward/entity"
+	task "tugaskita/features/task/entity"
+	user "tugaskita/features/user/entity"
+	middleware "tugaskita/utils/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ParentController struct {
+	parentUsecase entity.ParentUseCaseInterface
+}
+
+func New(parentUC entity.ParentUseCaseInterface) *ParentController {
+	return &ParentController{
+		parentUsecase: parentUC,
+	}
+}
+
+// childId returns the :id child of the logged in parent, answering with
+// 403 when the child is not linked to them.
+func (handler *ParentController) childId(e echo.Context) (string, error) {
+	parentId, _, _, err := middleware.ExtractTokenUserId(e)
+	if err != nil {
+		return "", e.JSON(http.StatusBadRequest, map[string]any{
+			"message": err.Error(),
+		})
+	}
+
+	studentId := e.Param("id")
+	linked, err := handler.parentUsecase.CanMonitor(parentId, studentId)
+	if err != nil {
+		return "", e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error check child",
+		})
+	}
+
+	if !linked {
+		return "", e.JSON(http.StatusForbidden, map[string]any{
+			"message": "access denied",
+		})
+	}
+
+	return studentId, nil
+}
+
+func (handler *ParentController) AddParent(e echo.Context) error {
+	input := dto.ParentRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := user.UserCore{
+		Name:     input.Name,
+		Email:    input.Email,
+		Password: input.Password,
+		Address:  input.Address,
+	}
+
+	errParent := handler.parentUsecase.CreateParent(data)
+	if errParent != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error create parent",
+			"error":   errParent.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "succes create parent",
+	})
+}
+
+func (handler *ParentController) LinkStudent(e echo.Context) error {
+	idParams := e.Param("id")
+
+	input := dto.LinkStudentRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	err := handler.parentUsecase.LinkStudent(idParams, input.StudentId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error link student",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success link student",
+	})
+}
+
+func (handler *ParentController) UnlinkStudent(e echo.Context) error {
+	err := handler.parentUsecase.UnlinkStudent(e.Param("id"), e.Param("student_id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error unlink student",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success unlink student",
+	})
+}
+
+func (handler *ParentController) readChildren(e echo.Context, parentId string) error {
+	data, err := handler.parentUsecase.FindChildren(parentId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all children",
+		})
+	}
+
+	dataList := []dto.ChildResponse{}
+	for _, v := range data {
+		dataList = append(dataList, dto.ChildResponse{
+			Id:         v.ID,
+			Name:       v.Name,
+			Image:      v.Image,
+			Thumbnail:  v.Thumbnail,
+			School:     v.School,
+			Class:      v.Class,
+			Religion:   v.Religion,
+			Point:      v.Point,
+			TotalPoint: v.TotalPoint,
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all children",
+		"data":    dataList,
+	})
+}
+
+func (handler *ParentController) ReadParentChildren(e echo.Context) error {
+	return handler.readChildren(e, e.Param("id"))
+}
+
+func (handler *ParentController) ReadChildren(e echo.Context) error {
+	parentId, _, _, err := middleware.ExtractTokenUserId(e)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": err.Error(),
+		})
+	}
+
+	return handler.readChildren(e, parentId)
+}
+
+func (handler *ParentController) ReadChildPointHistory(e echo.Context) error {
+	studentId, errChild := handler.childId(e)
+	if studentId == "" {
+		return errChild
+	}
+
+	data, err := handler.parentUsecase.FindChildPointHistory(studentId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get history point",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all point history",
+		"data":    append([]user.UserPointCore{}, data...),
+	})
+}
+
+func (handler *ParentController) ReadChildPenaltyHistory(e echo.Context) error {
+	studentId, errChild := handler.childId(e)
+	if studentId == "" {
+		return errChild
+	}
+
+	data, err := handler.parentUsecase.FindChildPenaltyHistory(studentId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all penalty history",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all penalty history",
+		"data":    append([]penalty.PenaltyCore{}, data...),
+	})
+}
+
+func (handler *ParentController) ReadChildTaskHistory(e echo.Context) error {
+	studentId, errChild := handler.childId(e)
+	if studentId == "" {
+		return errChild
+	}
+
+	data, err := handler.parentUsecase.FindChildTaskHistory(studentId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get history task",
+		})
+	}
+
+	data.Tasks = append([]task.UserTaskUploadCore{}, data.Tasks...)
+	data.TaskRequests = append([]task.UserTaskSubmissionCore{}, data.TaskRequests...)
+	data.ReligionTasks = append([]task.UserReligionTaskUploadCore{}, data.ReligionTasks...)
+	data.ReligionRequests = append([]task.UserReligionReqTaskCore{}, data.ReligionRequests...)
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all task history",
+		"data":    data,
+	})
+}
+
+func (handler *ParentController) ReadChildRewardHistory(e echo.Context) error {
+	studentId, errChild := handler.childId(e)
+	if studentId == "" {
+		return errChild
+	}
+
+	data, err := handler.parentUsecase.FindChildRewardHistory(studentId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get history reward",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all reward history",
+		"data":    append([]reward.UserRewardRequestCore{}, data...),
+	})
+}
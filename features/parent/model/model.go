@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+	user "tugaskita/features/user/model"
+
+	"github.com/google/uuid"
+)
+
+// ParentStudent links a parent account to one of their children.
+type ParentStudent struct {
+	Id        uuid.UUID   `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	ParentId  string      `gorm:"type:varchar(50);not null;uniqueIndex:idx_parent_student"`
+	Parent    *user.Users `gorm:"foreignKey:ParentId;constraint:OnDelete:CASCADE"`
+	StudentId string      `gorm:"type:varchar(50);not null;uniqueIndex:idx_parent_student;index"`
+	Student   *user.Users `gorm:"foreignKey:StudentId;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"tugaskita/features/parent/entity"
+	"tugaskita/features/parent/model"
+	user "tugaskita/features/user/entity"
+	userModel "tugaskita/features/user/model"
+	bcrypt "tugaskita/utils/bcrypt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const defaultUserImage = "public/images/user/person.png"
+
+type ParentRepository struct {
+	db *gorm.DB
+}
+
+func NewParentRepository(db *gorm.DB) entity.ParentDataInterface {
+	return &ParentRepository{
+		db: db,
+	}
+}
+
+// CreateParent implements entity.ParentDataInterface.
+func (parentRepo *ParentRepository) CreateParent(data user.UserCore) error {
+	hashPassword, err := bcrypt.HashPassword(data.Password)
+	if err != nil {
+		return err
+	}
+
+	input := userModel.Users{
+		ID:        uuid.NewString(),
+		Name:      data.Name,
+		Image:     defaultUserImage,
+		Thumbnail: defaultUserImage,
+		Address:   data.Address,
+		Email:     data.Email,
+		Password:  hashPassword,
+		Role:      "parent",
+	}
+
+	tx := parentRepo.db.Create(&input)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// LinkStudent implements entity.ParentDataInterface.
+func (parentRepo *ParentRepository) LinkStudent(parentId string, studentId string) error {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return UUIDerr
+	}
+
+	data := model.ParentStudent{
+		Id:        newUUID,
+		ParentId:  parentId,
+		StudentId: studentId,
+	}
+
+	tx := parentRepo.db.Create(&data)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// UnlinkStudent implements entity.ParentDataInterface.
+func (parentRepo *ParentRepository) UnlinkStudent(parentId string, studentId string) error {
+	tx := parentRepo.db.Where("parent_id = ? AND student_id = ?", parentId, studentId).Delete(&model.ParentStudent{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.New("student is not linked to this parent")
+	}
+
+	return nil
+}
+
+// FindChildren implements entity.ParentDataInterface.
+func (parentRepo *ParentRepository) FindChildren(parentId string) ([]user.UserCore, error) {
+	var dataUser []userModel.Users
+
+	errData := parentRepo.db.
+		Joins("JOIN parent_students ON parent_students.student_id = users.id").
+		Where("parent_students.parent_id = ?", parentId).
+		Order("users.name").
+		Find(&dataUser).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataResponse := []user.UserCore{}
+	for _, v := range dataUser {
+		data := user.UserModelToUserCore(v)
+		data.Image = v.Image
+		data.Thumbnail = v.Thumbnail
+		data.Religion = v.Religion
+		dataResponse = append(dataResponse, data)
+	}
+	return dataResponse, nil
+}
+
+// IsLinked implements entity.ParentDataInterface.
+func (parentRepo *ParentRepository) IsLinked(parentId string, studentId string) (bool, error) {
+	var count int64
+
+	err := parentRepo.db.Model(&model.ParentStudent{}).
+		Where("parent_id = ? AND student_id = ?", parentId, studentId).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
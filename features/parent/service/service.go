@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"tugaskita/features/parent/entity"
+	penalty "tugaskita/features/penalty/entity"
+	reward "tugaskita/features/reward/entity"
+	task "tugaskita/features/task/entity"
+	user "tugaskita/features/user/entity"
+	"tugaskita/utils/authz"
+)
+
+var emailPattern = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+type ParentService struct {
+	ParentRepo  entity.ParentDataInterface
+	UserRepo    user.UserDataInterface
+	PenaltyRepo penalty.PenaltyDataInterface
+	TaskRepo    task.TaskDataInterface
+	RewardRepo  reward.RewardDataInterface
+}
+
+func NewParentService(parentRepo entity.ParentDataInterface, userRepo user.UserDataInterface, penaltyRepo penalty.PenaltyDataInterface, taskRepo task.TaskDataInterface, rewardRepo reward.RewardDataInterface) entity.ParentUseCaseInterface {
+	return &ParentService{
+		ParentRepo:  parentRepo,
+		UserRepo:    userRepo,
+		PenaltyRepo: penaltyRepo,
+		TaskRepo:    taskRepo,
+		RewardRepo:  rewardRepo,
+	}
+}
+
+// CreateParent implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) CreateParent(data user.UserCore) error {
+	data.Name = strings.TrimSpace(data.Name)
+	data.Email = strings.TrimSpace(data.Email)
+	if data.Name == "" {
+		return errors.New("name can't be empty")
+	}
+
+	if data.Email == "" || data.Password == "" {
+		return errors.New("error, email or password can't be empty")
+	}
+
+	if !emailPattern.MatchString(data.Email) {
+		return errors.New("error. email format not valid")
+	}
+
+	_, errEmail := parentUC.UserRepo.FindUserByEmail(data.Email)
+	if errEmail == nil {
+		return errors.New("email already registered")
+	}
+
+	return parentUC.ParentRepo.CreateParent(data)
+}
+
+// LinkStudent implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) LinkStudent(parentId string, studentId string) error {
+	parent, errParent := parentUC.UserRepo.ReadSpecificUser(parentId)
+	if errParent != nil {
+		return errors.New("parent not found")
+	}
+
+	if parent.Role != authz.RoleParent {
+		return errors.New("user is not a parent")
+	}
+
+	student, errStudent := parentUC.UserRepo.ReadSpecificUser(studentId)
+	if errStudent != nil {
+		return errors.New("student not found")
+	}
+
+	if student.Role != authz.RoleUser {
+		return errors.New("user is not a student")
+	}
+
+	linked, errLinked := parentUC.ParentRepo.IsLinked(parentId, studentId)
+	if errLinked != nil {
+		return errLinked
+	}
+
+	if linked {
+		return errors.New("student already linked to this parent")
+	}
+
+	return parentUC.ParentRepo.LinkStudent(parentId, studentId)
+}
+
+// UnlinkStudent implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) UnlinkStudent(parentId string, studentId string) error {
+	return parentUC.ParentRepo.UnlinkStudent(parentId, studentId)
+}
+
+// FindChildren implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) FindChildren(parentId string) ([]user.UserCore, error) {
+	data, err := parentUC.ParentRepo.FindChildren(parentId)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// CanMonitor implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) CanMonitor(parentId string, studentId string) (bool, error) {
+	if parentId == "" || studentId == "" {
+		return false, nil
+	}
+
+	return parentUC.ParentRepo.IsLinked(parentId, studentId)
+}
+
+// FindChildPointHistory implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) FindChildPointHistory(studentId string) ([]user.UserPointCore, error) {
+	data, err := parentUC.UserRepo.GetUserPointHistory(studentId)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindChildPenaltyHistory implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) FindChildPenaltyHistory(studentId string) ([]penalty.PenaltyCore, error) {
+	data, err := parentUC.PenaltyRepo.FindAllPenaltyHistory(studentId)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindChildTaskHistory implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) FindChildTaskHistory(studentId string) (entity.ChildTaskHistoryCore, error) {
+	tasks, err := parentUC.TaskRepo.FindAllClaimedTask(studentId)
+	if err != nil {
+		return entity.ChildTaskHistoryCore{}, errors.New("error get data")
+	}
+
+	for i, v := range tasks {
+		taskData, _ := parentUC.TaskRepo.FindById(v.TaskId)
+		tasks[i].TaskName = taskData.Title
+		tasks[i].Type = taskData.Type
+	}
+
+	requests, err := parentUC.TaskRepo.FindAllRequestTaskHistory(studentId)
+	if err != nil {
+		return entity.ChildTaskHistoryCore{}, errors.New("error get data")
+	}
+
+	religionTasks, err := parentUC.TaskRepo.FindAllReligionTaskHistory(studentId)
+	if err != nil {
+		return entity.ChildTaskHistoryCore{}, errors.New("error get data")
+	}
+
+	for i, v := range religionTasks {
+		taskData, _ := parentUC.TaskRepo.FindByIdReligionTask(v.TaskId)
+		religionTasks[i].TaskName = taskData.Title
+	}
+
+	religionRequests, err := parentUC.TaskRepo.FindAllReligionTaskRequestHistory(studentId)
+	if err != nil {
+		return entity.ChildTaskHistoryCore{}, errors.New("error get data")
+	}
+
+	return entity.ChildTaskHistoryCore{
+		Tasks:            tasks,
+		TaskRequests:     requests,
+		ReligionTasks:    religionTasks,
+		ReligionRequests: religionRequests,
+	}, nil
+}
+
+// FindChildRewardHistory implements entity.ParentUseCaseInterface.
+func (parentUC *ParentService) FindChildRewardHistory(studentId string) ([]reward.UserRewardRequestCore, error) {
+	data, err := parentUC.RewardRepo.FindAllRewardHistory(studentId)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	for i, v := range data {
+		rewardData, _ := parentUC.RewardRepo.FindById(v.RewardId)
+		data[i].RewardName = rewardData.Name
+		data[i].Price = rewardData.Price
+	}
+
+	return data, nil
+}
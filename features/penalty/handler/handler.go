@@ -5,6 +5,7 @@ import (
 	"tugaskita/features/penalty/dto"
 	"tugaskita/features/penalty/entity"
 	user "tugaskita/features/user/entity"
+	"tugaskita/utils/authz"
 	middleware "tugaskita/utils/jwt"
 
 	"github.com/google/uuid"
@@ -24,19 +25,6 @@ func New(penaltyUC entity.PenaltyUseCaseInterface, userUC user.UserUseCaseInterf
 }
 
 func (handler *PenaltyController) CreatePenalty(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	input := dto.PenaltyRequest{}
 	errBind := e.Bind(&input)
 	if errBind != nil {
@@ -66,19 +54,6 @@ func (handler *PenaltyController) CreatePenalty(e echo.Context) error {
 }
 
 func (handler *PenaltyController) DeletePenalty(e echo.Context) error {
-	_, role, _, errRole := middleware.ExtractTokenUserId(e)
-	if errRole != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": errRole.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 	err := handler.penaltyUsecase.DeletePenalty(idParams)
 	if err != nil {
@@ -93,19 +68,6 @@ func (handler *PenaltyController) DeletePenalty(e echo.Context) error {
 }
 
 func (handler *PenaltyController) FindAllPenalty(e echo.Context) error {
-	_, role, _, errRole := middleware.ExtractTokenUserId(e)
-	if errRole != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": errRole.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	data, err := handler.penaltyUsecase.FindAllPenalty()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
@@ -154,6 +116,19 @@ func (handler *PenaltyController) FindSpecificPenalty(e echo.Context) error {
 		})
 	}
 
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	if data.UserId != userId && !authz.Can(e, authz.PenaltyRead) {
+		return e.JSON(http.StatusForbidden, map[string]any{
+			"message": "access denied",
+		})
+	}
+
 	userData, errData := handler.userUsecase.ReadSpecificUser(data.UserId)
 	if errData != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
@@ -179,19 +154,6 @@ func (handler *PenaltyController) FindSpecificPenalty(e echo.Context) error {
 }
 
 func (handler *PenaltyController) UpdatePenalty(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := new(dto.PenaltyRequest)
@@ -4,18 +4,21 @@ import (
 	"errors"
 	"tugaskita/features/penalty/entity"
 	"tugaskita/features/penalty/model"
+	user "tugaskita/features/user/entity"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type PenaltyRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	userRepository user.UserDataInterface
 }
 
-func NewPenaltyRepository(db *gorm.DB) entity.PenaltyDataInterface {
+func NewPenaltyRepository(db *gorm.DB, userRepository user.UserDataInterface) entity.PenaltyDataInterface {
 	return &PenaltyRepository{
-		db: db,
+		db:             db,
+		userRepository: userRepository,
 	}
 }
 
@@ -28,27 +31,64 @@ func (penaltyRepo *PenaltyRepository) CreatePenalty(input entity.PenaltyCore) er
 
 	data := entity.PenaltyCoreToPenaltyModel(input)
 	data.Id = newUUID
-	tx := penaltyRepo.db.Create(&data)
-	if tx.Error != nil {
-		return tx.Error
-	}
-	return nil
+
+	return penaltyRepo.db.Transaction(func(tx *gorm.DB) error {
+		errCreate := tx.Create(&data).Error
+		if errCreate != nil {
+			return errCreate
+		}
+
+		//reduce user point
+		historyData := user.UserPointCore{
+			UserId:          input.UserId,
+			Type:            user.PointTypePenalty,
+			SourceId:        newUUID.String(),
+			Point:           input.Point,
+			PointDelta:      -input.Point,
+			TotalPointDelta: -input.Point,
+			TaskName:        input.Description,
+		}
+		_, errPoint := penaltyRepo.userRepository.PostPointEntry(tx, historyData)
+		if errPoint != nil {
+			return errPoint
+		}
+
+		return nil
+	})
 }
 
 // DeletePenalty implements entity.PenaltyDataInterface.
 func (penaltyRepo *PenaltyRepository) DeletePenalty(id string) error {
-	dataPenalty := model.Penalty{}
+	return penaltyRepo.db.Transaction(func(tx *gorm.DB) error {
+		dataPenalty := model.Penalty{}
 
-	tx := penaltyRepo.db.Where("id = ? ", id).Delete(&dataPenalty)
-	if tx.Error != nil {
-		return tx.Error
-	}
+		errData := tx.Where("id = ? ", id).First(&dataPenalty).Error
+		if errData != nil {
+			return errors.New("penalty not found")
+		}
 
-	if tx.RowsAffected == 0 {
-		return errors.New("penalty not found")
-	}
+		errDelete := tx.Where("id = ? ", id).Delete(&model.Penalty{}).Error
+		if errDelete != nil {
+			return errDelete
+		}
 
-	return nil
+		//give back the reduced point
+		historyData := user.UserPointCore{
+			UserId:          dataPenalty.UserId,
+			Type:            user.PointTypePenalty,
+			SourceId:        id,
+			Point:           dataPenalty.Point,
+			PointDelta:      dataPenalty.Point,
+			TotalPointDelta: dataPenalty.Point,
+			TaskName:        "Cancel penalty: " + dataPenalty.Description,
+		}
+		_, errPoint := penaltyRepo.userRepository.PostPointEntry(tx, historyData)
+		if errPoint != nil {
+			return errPoint
+		}
+
+		return nil
+	})
 }
 
 // FindAllPenalty implements entity.PenaltyDataInterface.
@@ -96,19 +136,71 @@ func (penaltyRepo *PenaltyRepository) FindSpecificPenalty(id string) (entity.Pen
 func (penaltyRepo *PenaltyRepository) UpdatePenalty(id string, data entity.PenaltyCore) error {
 	dataPenalty := entity.PenaltyCoreToPenaltyModel(data)
 
-	tx := penaltyRepo.db.Where("id = ?", id).Updates(&dataPenalty)
-	if tx.Error != nil {
-		if tx.Error != nil {
-			return tx.Error
+	return penaltyRepo.db.Transaction(func(tx *gorm.DB) error {
+		oldPenalty := model.Penalty{}
+		errData := tx.Where("id = ?", id).First(&oldPenalty).Error
+		if errData != nil {
+			return errors.New("penalty not found")
 		}
-		return tx.Error
-	}
 
-	if tx.RowsAffected == 0 {
-		return errors.New("penalty not found")
-	}
+		update := tx.Where("id = ?", id).Updates(&dataPenalty)
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if update.RowsAffected == 0 {
+			return errors.New("penalty not found")
+		}
+
+		//fields left empty aren't updated, the stored penalty has the
+		//point and user the correction is made for
+		newPenalty := model.Penalty{}
+		errData = tx.Where("id = ?", id).First(&newPenalty).Error
+		if errData != nil {
+			return errData
+		}
+
+		//correct user point with the difference of the old and new penalty
+		var corrections []user.UserPointCore
+		if newPenalty.UserId == oldPenalty.UserId {
+			if delta := oldPenalty.Point - newPenalty.Point; delta != 0 {
+				corrections = append(corrections, user.UserPointCore{
+					UserId:          newPenalty.UserId,
+					PointDelta:      delta,
+					TotalPointDelta: delta,
+					TaskName:        "Penalty correction: " + oldPenalty.Description,
+				})
+			}
+		} else {
+			corrections = append(corrections, user.UserPointCore{
+				UserId:          oldPenalty.UserId,
+				PointDelta:      oldPenalty.Point,
+				TotalPointDelta: oldPenalty.Point,
+				TaskName:        "Cancel penalty: " + oldPenalty.Description,
+			}, user.UserPointCore{
+				UserId:          newPenalty.UserId,
+				PointDelta:      -newPenalty.Point,
+				TotalPointDelta: -newPenalty.Point,
+				TaskName:        newPenalty.Description,
+			})
+		}
+
+		for _, historyData := range corrections {
+			historyData.Type = user.PointTypePenalty
+			historyData.SourceId = id
+			historyData.Point = historyData.PointDelta
+			if historyData.Point < 0 {
+				historyData.Point = -historyData.Point
+			}
+
+			_, errPoint := penaltyRepo.userRepository.PostPointEntry(tx, historyData)
+			if errPoint != nil {
+				return errPoint
+			}
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // FindAllPenaltyHistory implements entity.PenaltyDataInterface.
@@ -2,10 +2,10 @@ package service
 
 import (
 	"errors"
-	"strconv"
 	"time"
 	"tugaskita/features/penalty/entity"
 	user "tugaskita/features/user/entity"
+	"tugaskita/utils/event"
 )
 
 type PenaltyService struct {
@@ -20,6 +20,24 @@ func NewPenaltyService(penaltyRepo entity.PenaltyDataInterface, userRepo user.Us
 	}
 }
 
+// publishPoints publishes the change of the points of the given students,
+// empty and repeated ids are skipped.
+func publishPoints(userIds ...string) {
+	published := map[string]bool{}
+	for _, v := range userIds {
+		if v == "" || published[v] {
+			continue
+		}
+		published[v] = true
+
+		event.Publish(event.Event{
+			Type:   event.PointsChanged,
+			UserId: v,
+			Data:   event.Points{Source: user.PointTypePenalty},
+		})
+	}
+}
+
 // CreatePenalty implements entity.PenaltyUseCaseInterface.
 func (penaltyUC *PenaltyService) CreatePenalty(input entity.PenaltyCore) error {
 	if input.Description == "" || input.UserId == "" {
@@ -42,48 +60,23 @@ func (penaltyUC *PenaltyService) CreatePenalty(input entity.PenaltyCore) error {
 		return errors.New("user not found")
 	}
 
-	userData, errUser := penaltyUC.UserRepo.ReadSpecificUser(input.UserId)
-	if errUser != nil {
-		return errors.New("failed get user")
-	}
-
-	//reduce total point
-	userTotalPoint, _ := strconv.Atoi(userData.TotalPoint)
-
-	countTotal := userTotalPoint - input.Point
-
-	userData.TotalPoint = strconv.Itoa(countTotal)
-
-	//reduce point
-	userPoint, _ := strconv.Atoi(userData.Point)
-
-	count := userPoint - input.Point
-
-	userData.Point = strconv.Itoa(count)
-	//update user
-	errUserUpdate := penaltyUC.UserRepo.UpdatePoint(input.UserId, userData)
-	if errUserUpdate != nil {
-		return errors.New("failed update user point")
-	}
-
-	//update history
-	historyData := user.UserPointCore{
-		UserId:   input.UserId,
-		Type:     "Penalty",
-		Point:    input.Point,
-		TaskName: input.Description,
-	}
-	errUserHistory := penaltyUC.UserRepo.PostUserPointHistory(historyData)
-	if errUserHistory != nil {
-		return errors.New("failed add user history point")
-	}
-
-	//create penalty
+	//create penalty and reduce user point
 	err := penaltyUC.PenaltyRepo.CreatePenalty(input)
 	if err != nil {
 		return err
 	}
 
+	event.Publish(event.Event{
+		Type:   event.PenaltyCreated,
+		UserId: input.UserId,
+		Data: event.Penalty{
+			Description: input.Description,
+			Point:       input.Point,
+			Date:        input.Date,
+		},
+	})
+	publishPoints(input.UserId)
+
 	return nil
 }
 
@@ -93,7 +86,7 @@ func (penaltyUC *PenaltyService) DeletePenalty(id string) error {
 		return errors.New("insert penalty id")
 	}
 
-	_, err := penaltyUC.PenaltyRepo.FindSpecificPenalty(id)
+	penalty, err := penaltyUC.PenaltyRepo.FindSpecificPenalty(id)
 	if err != nil {
 		return errors.New("penalty not found")
 	}
@@ -102,6 +95,7 @@ func (penaltyUC *PenaltyService) DeletePenalty(id string) error {
 	if errDelete != nil {
 		return errors.New("can't delete penalty")
 	}
+	publishPoints(penalty.UserId)
 
 	return nil
 }
@@ -138,31 +132,29 @@ func (penaltyUC *PenaltyService) UpdatePenalty(id string, data entity.PenaltyCor
 		return errors.New("date must be in 'yyyy-mm-dd'")
 	}
 
-	//get user
-	userData, errUser := penaltyUC.UserRepo.ReadSpecificUser(data.UserId)
-	if errUser != nil {
-		return errors.New("failed get user")
+	if data.Point < 0 {
+		return errors.New("point can't less then 0")
 	}
 
-	//get penalty
-	penaltyData, _ := penaltyUC.PenaltyRepo.FindSpecificPenalty(id)
-
-	userPoint, _ := strconv.Atoi(userData.Point)
-
-	count := (userPoint + penaltyData.Point) - data.Point
-
-	userData.Point = strconv.Itoa(count)
+	//get user
+	if data.UserId != "" {
+		_, errUser := penaltyUC.UserRepo.ReadSpecificUser(data.UserId)
+		if errUser != nil {
+			return errors.New("failed get user")
+		}
+	}
 
-	//update user
-	errUserUpdate := penaltyUC.UserRepo.UpdatePoint(data.UserId, userData)
-	if errUserUpdate != nil {
-		return errors.New("failed update user point")
+	penalty, errPenalty := penaltyUC.PenaltyRepo.FindSpecificPenalty(id)
+	if errPenalty != nil {
+		return errors.New("penalty not found")
 	}
 
+	//update penalty and correct user point
 	err := penaltyUC.PenaltyRepo.UpdatePenalty(id, data)
 	if err != nil {
 		return err
 	}
+	publishPoints(penalty.UserId, data.UserId)
 
 	return nil
 }
@@ -8,6 +8,7 @@ type RewardResponse struct {
 	Stock     int
 	Price     int
 	Image     string
+	Thumbnail string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -12,6 +12,7 @@ type RewardCore struct {
 	Stock     int       `json:"stock"`
 	Price     int       `json:"price"`
 	Image     string    `json:"image"`
+	Thumbnail string    `json:"thumbnail"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
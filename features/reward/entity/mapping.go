@@ -11,6 +11,7 @@ func RewardCoreToRewardModel(data RewardCore) model.Reward {
 		Stock:     data.Stock,
 		Price:     data.Price,
 		Image:     data.Image,
+		Thumbnail: data.Thumbnail,
 		CreatedAt: data.CreatedAt,
 		UpdatedAt: data.UpdatedAt,
 	}
@@ -23,6 +24,7 @@ func RewardModelToRewardCore(data model.Reward) RewardCore {
 		Stock:     data.Stock,
 		Price:     data.Price,
 		Image:     data.Image,
+		Thumbnail: data.Thumbnail,
 		CreatedAt: data.CreatedAt,
 		UpdatedAt: data.UpdatedAt,
 	}
@@ -25,19 +25,6 @@ func New(rewardUC entity.RewardUseCaseInterface, userUC user.UserUseCaseInterfac
 }
 
 func (handler *RewardController) AddReward(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	input := dto.RewardRequest{}
 	errBind := e.Bind(&input)
 	if errBind != nil {
@@ -94,6 +81,7 @@ func (handler *RewardController) ReadAllReward(e echo.Context) error {
 			Stock:     v.Stock,
 			Price:     v.Price,
 			Image:     v.Image,
+			Thumbnail: v.Thumbnail,
 			CreatedAt: v.CreatedAt,
 			UpdatedAt: v.UpdatedAt,
 		}
@@ -130,6 +118,7 @@ func (handler *RewardController) ReadSpecificReward(e echo.Context) error {
 		Stock:     data.Stock,
 		Price:     data.Price,
 		Image:     data.Image,
+		Thumbnail: data.Thumbnail,
 		CreatedAt: data.CreatedAt,
 		UpdatedAt: data.UpdatedAt,
 	}
@@ -141,19 +130,6 @@ func (handler *RewardController) ReadSpecificReward(e echo.Context) error {
 }
 
 func (handler *RewardController) DeleteReward(e echo.Context) error {
-	_, role, _, errRole := middleware.ExtractTokenUserId(e)
-	if errRole != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": errRole.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 	err := handler.rewardUsecase.DeleteReward(idParams)
 	if err != nil {
@@ -168,19 +144,6 @@ func (handler *RewardController) DeleteReward(e echo.Context) error {
 }
 
 func (handler *RewardController) UpdateReward(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := new(dto.RewardRequest)
@@ -192,7 +155,7 @@ func (handler *RewardController) UpdateReward(e echo.Context) error {
 
 	// Menginisialisasi variabel untuk file gambar
 	var image *multipart.FileHeader
-	image, err = e.FormFile("image")
+	image, err := e.FormFile("image")
 	if err != nil && err != http.ErrMissingFile {
 		return e.JSON(http.StatusBadRequest, map[string]interface{}{
 			"message": "Error uploading file",
@@ -260,19 +223,6 @@ func (handler *RewardController) FindAllRewardHistory(e echo.Context) error {
 }
 
 func (handler *RewardController) FindAllUploadReward(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	data, err := handler.rewardUsecase.FindAllUploadReward()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
@@ -343,19 +293,6 @@ func (handler *RewardController) UploadRewardRequest(e echo.Context) error {
 }
 
 func (handler *RewardController) FindUserRewardById(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data, err := handler.rewardUsecase.FindUserRewardById(idParams)
@@ -389,19 +326,6 @@ func (handler *RewardController) FindUserRewardById(e echo.Context) error {
 }
 
 func (handler *RewardController) UpdateReqRewardStatus(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := dto.RewardReqUpdateRequest{}
@@ -12,6 +12,7 @@ type Reward struct {
 	Stock     int
 	Price     int
 	Image     string
+	Thumbnail string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -1,15 +1,14 @@
 package repository
 
 import (
+	"context"
 	"errors"
-	"io"
 	"mime/multipart"
-	"os"
-	"path/filepath"
-	"strings"
+	"strconv"
 	"tugaskita/features/reward/entity"
 	"tugaskita/features/reward/model"
 	user "tugaskita/features/user/entity"
+	"tugaskita/utils/storage"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -18,12 +17,14 @@ import (
 type RewardRepository struct {
 	db             *gorm.DB
 	userRepository user.UserDataInterface
+	storage        storage.Storage
 }
 
-func NewRewardRepository(db *gorm.DB, userRepository user.UserDataInterface) entity.RewardDataInterface {
+func NewRewardRepository(db *gorm.DB, userRepository user.UserDataInterface, store storage.Storage) entity.RewardDataInterface {
 	return &RewardRepository{
 		db:             db,
 		userRepository: userRepository,
+		storage:        store,
 	}
 }
 
@@ -34,40 +35,19 @@ func (rewardRepo *RewardRepository) CreateReward(input entity.RewardCore, image
 		return UUIDerr
 	}
 
-	file, err := image.Open()
+	stored, err := storage.SaveImage(context.Background(), rewardRepo.storage, "images/reward", image)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	// Define the directory where you want to save the image
-	saveDir := "public/images/reward"
-	os.MkdirAll(saveDir, os.ModePerm)
-
-	// Define the file path
-	filePath := filepath.Join(saveDir, image.Filename)
-
-	// Replace backslashes with forward slashes for consistency
-	filePath = strings.ReplaceAll(filePath, "\\", "/")
-
-	// Create the destination file
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer dst.Close()
-
-	// Copy the uploaded file data to the destination file
-	if _, err = io.Copy(dst, file); err != nil {
-		return err
-	}
-
-	input.Image = filePath
+	input.Image = stored.URL
+	input.Thumbnail = stored.ThumbnailURL
 
 	data := entity.RewardCoreToRewardModel(input)
 	data.ID = newUUID
 	tx := rewardRepo.db.Create(&data)
 	if tx.Error != nil {
+		storage.DeleteImage(context.Background(), rewardRepo.storage, stored)
 		return tx.Error
 	}
 	return nil
@@ -119,47 +99,26 @@ func (rewardRepo *RewardRepository) FindById(rewardId string) (entity.RewardCore
 func (rewardRepo *RewardRepository) UpdateReward(rewardId string, data entity.RewardCore, image *multipart.FileHeader) error {
 	dataReward := entity.RewardCoreToRewardModel(data)
 
+	var stored storage.StoredImage
 	if image != nil {
-		file, err := image.Open()
+		var err error
+		stored, err = storage.SaveImage(context.Background(), rewardRepo.storage, "images/reward", image)
 		if err != nil {
 			return err
 		}
-		defer file.Close()
-
-		// Define the directory where you want to save the image
-		saveDir := "public/images/reward"
-		os.MkdirAll(saveDir, os.ModePerm)
-
-		// Define the file path
-		filePath := filepath.Join(saveDir, image.Filename)
-
-		// Replace backslashes with forward slashes for consistency
-		filePath = strings.ReplaceAll(filePath, "\\", "/")
-
-		// Create the destination file
-		dst, err := os.Create(filePath)
-		if err != nil {
-			return err
-		}
-		defer dst.Close()
-
-		// Copy the uploaded file data to the destination file
-		if _, err = io.Copy(dst, file); err != nil {
-			return err
-		}
 
-		dataReward.Image = filePath
+		dataReward.Image = stored.URL
+		dataReward.Thumbnail = stored.ThumbnailURL
 	}
 
 	tx := rewardRepo.db.Where("id = ?", rewardId).Updates(&dataReward)
 	if tx.Error != nil {
-		if tx.Error != nil {
-			return tx.Error
-		}
+		storage.DeleteImage(context.Background(), rewardRepo.storage, stored)
 		return tx.Error
 	}
 
 	if tx.RowsAffected == 0 {
+		storage.DeleteImage(context.Background(), rewardRepo.storage, stored)
 		return errors.New("reward not found")
 	}
 
@@ -209,12 +168,28 @@ func (rewardRepo *RewardRepository) UploadRewardRequest(input entity.UserRewardR
 		Price:      input.Price,
 	}
 
-	errUpload := rewardRepo.db.Save(&inputData)
-	if errUpload != nil {
-		return errUpload.Error
-	}
+	return rewardRepo.db.Transaction(func(tx *gorm.DB) error {
+		errUpload := tx.Create(&inputData).Error
+		if errUpload != nil {
+			return errUpload
+		}
 
-	return nil
+		//spend user point
+		historyData := user.UserPointCore{
+			UserId:          input.UserId,
+			Type:            user.PointTypeReward,
+			SourceId:        newUUID.String(),
+			Point:           input.TotalPrice,
+			TotalPointDelta: -input.TotalPrice,
+			TaskName:        "Change " + strconv.Itoa(input.Amount) + " " + input.RewardName,
+		}
+		_, errPoint := rewardRepo.userRepository.PostPointEntry(tx, historyData)
+		if errPoint != nil {
+			return errPoint
+		}
+
+		return nil
+	})
 }
 
 // FindAllRewardRequestUser implements entity.RewardDataInterface.
@@ -259,11 +234,42 @@ func (rewardRepo *RewardRepository) FindUserRewardById(id string) (entity.UserRe
 func (rewardRepo *RewardRepository) UpdateReqRewardStatus(rewardId string, data entity.UserRewardRequestCore) error {
 	rewardData := entity.RewardUserCoreToRewardUserModel(data)
 
-	//update status
-	tx := rewardRepo.db.Where("id=?", rewardId).Updates(rewardData)
-	if tx.Error != nil {
-		return tx.Error
-	}
+	return rewardRepo.db.Transaction(func(tx *gorm.DB) error {
+		var request model.UserRewardRequest
+		errData := tx.Where("id=?", rewardId).First(&request).Error
+		if errData != nil {
+			return errData
+		}
 
-	return nil
+		//update status, only a request that is still waiting for review
+		update := tx.Where("id=? AND status = ?", rewardId, "Perlu Review").Updates(rewardData)
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if update.RowsAffected == 0 {
+			return errors.New("reward request already reviewed")
+		}
+
+		if rewardData.Status == "Ditolak" {
+			var reward model.Reward
+			tx.Where("id=?", request.RewardId).First(&reward)
+
+			//refund user point
+			historyData := user.UserPointCore{
+				UserId:          request.UserId,
+				Type:            user.PointTypeRewardRefund,
+				SourceId:        rewardId,
+				Point:           request.TotalPrice,
+				TotalPointDelta: request.TotalPrice,
+				TaskName:        "Refund " + strconv.Itoa(request.Amount) + " " + reward.Name,
+			}
+			_, errPoint := rewardRepo.userRepository.PostPointEntry(tx, historyData)
+			if errPoint != nil {
+				return errPoint
+			}
+		}
+
+		return nil
+	})
 }
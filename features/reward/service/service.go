@@ -3,9 +3,10 @@ package service
 import (
 	"errors"
 	"mime/multipart"
-	"strconv"
 	"tugaskita/features/reward/entity"
 	user "tugaskita/features/user/entity"
+	"tugaskita/utils/event"
+	"tugaskita/utils/imageproc"
 )
 
 type RewardService struct {
@@ -31,8 +32,8 @@ func (rewardUC *RewardService) CreateReward(input entity.RewardCore, image *mult
 		return errors.New("price and stock can't less then 0")
 	}
 
-	if image != nil && image.Size > 10*1024*1024 {
-		return errors.New("image file size should be less than 10 MB")
+	if err := imageproc.Check(image); err != nil {
+		return err
 	}
 
 	err := rewardUC.RewardRepo.CreateReward(input, image)
@@ -96,11 +97,9 @@ func (rewardUC *RewardService) UpdateReward(rewardId string, data entity.RewardC
 		return errors.New("price and stock can't less then 0")
 	}
 
-	// Validasi ukuran file gambar jika gambar diunggah
-	if image != nil {
-		if image.Size > 10*1024*1024 {
-			return errors.New("image file size should be less than 10 MB")
-		}
+	// Validasi file gambar jika gambar diunggah
+	if err := imageproc.Check(image); err != nil {
+		return err
 	}
 
 	err := rewardUC.RewardRepo.UpdateReward(rewardId, data, image)
@@ -132,13 +131,15 @@ func (rewardUC *RewardService) FindAllUploadReward() ([]entity.UserRewardRequest
 
 // UploadRewardRequest implements entity.RewardUseCaseInterface.
 func (rewardUC *RewardService) UploadRewardRequest(input entity.UserRewardRequestCore) error {
+	if input.Amount < 1 {
+		return errors.New("amount must be more than 0")
+	}
+
 	userData, errUser := rewardUC.UserRepo.ReadSpecificUser(input.UserId)
 	if errUser != nil {
 		return errors.New("failed get user")
 	}
 
-	userPoint, _ := strconv.Atoi(userData.TotalPoint)
-
 	rewardData, errReward := rewardUC.RewardRepo.FindById(input.RewardId)
 	if errReward != nil {
 		return errors.New("failed get reward")
@@ -150,27 +151,26 @@ func (rewardUC *RewardService) UploadRewardRequest(input entity.UserRewardReques
 
 	totalPrice := rewardData.Price * input.Amount
 
-	count := userPoint - totalPrice
-
-	userData.TotalPoint = strconv.Itoa(count)
 	input.TotalPrice = totalPrice
 	input.Price = rewardData.Price
+	input.RewardName = rewardData.Name
 
-	if userPoint < totalPrice {
+	if userData.TotalPoint < totalPrice {
 		return errors.New("not enough point")
 	}
 
-	//update user
-	errUserUpdate := rewardUC.UserRepo.UpdatePoint(input.UserId, userData)
-	if errUserUpdate != nil {
-		return errors.New("failed update user point")
-	}
-
+	// the point is spent in the same transaction as the request
 	err := rewardUC.RewardRepo.UploadRewardRequest(input)
 	if err != nil {
-		return errors.New("failed request reward")
+		return errors.New("failed request reward: " + err.Error())
 	}
 
+	event.Publish(event.Event{
+		Type:   event.PointsChanged,
+		UserId: input.UserId,
+		Data:   event.Points{Source: user.PointTypeReward},
+	})
+
 	return nil
 }
 
@@ -186,8 +186,12 @@ func (rewardUC *RewardService) FindUserRewardById(id string) (entity.UserRewardR
 
 // UpdateReqRewardStatus implements entity.RewardUseCaseInterface.
 func (rewardUC *RewardService) UpdateReqRewardStatus(rewardId string, data entity.UserRewardRequestCore) error {
+	if data.Status != "Diterima" && data.Status != "Ditolak" {
+		return errors.New("status must be Diterima or Ditolak")
+	}
+
 	// user data
-	userData, errUser := rewardUC.UserRepo.ReadSpecificUser(data.UserId)
+	_, errUser := rewardUC.UserRepo.ReadSpecificUser(data.UserId)
 	if errUser != nil {
 		return errors.New("failed get user")
 	}
@@ -212,43 +216,34 @@ func (rewardUC *RewardService) UpdateReqRewardStatus(rewardId string, data entit
 		return errors.New("you already reject this request")
 	}
 
-	if rewardData.Stock < 1 {
+	if data.Status == "Diterima" && rewardData.Stock < 1 {
 		return errors.New("not enough stock")
 	}
 
-	if data.Status == "Ditolak" {
-		userPoint, _ := strconv.Atoi(userData.TotalPoint)
-		count := userPoint + rewardReqData.TotalPrice
-		userData.TotalPoint = strconv.Itoa(count)
-	}
-
-	//update user
-	errUserUpdate := rewardUC.UserRepo.UpdatePoint(data.UserId, userData)
-	if errUserUpdate != nil {
-		return errors.New("failed update user point")
-	}
-
-	//save history
-	if data.Status == "Diterima" {
-		//update history
-		amount := strconv.Itoa(rewardReqData.Amount)
-		historyData := user.UserPointCore{
-			UserId:   data.UserId,
-			Type:     "Reward",
-			Point:    rewardReqData.TotalPrice,
-			TaskName: "Change " + amount + " " + rewardReqData.RewardName,
-		}
-		errUserHistory := rewardUC.UserRepo.PostUserPointHistory(historyData)
-		if errUserHistory != nil {
-			return errors.New("failed add user history point")
-		}
-	}
-
-	//update status
+	//update status, a rejected request refunds the spent point
 	err := rewardUC.RewardRepo.UpdateReqRewardStatus(rewardId, data)
 	if err != nil {
 		return err
 	}
 
+	event.Publish(event.Event{
+		Type:   event.RewardReviewed,
+		UserId: rewardReqData.UserId,
+		Data: event.Reward{
+			Id:         rewardId,
+			RewardName: rewardData.Name,
+			Amount:     rewardReqData.Amount,
+			Status:     data.Status,
+		},
+	})
+
+	if data.Status == "Ditolak" {
+		event.Publish(event.Event{
+			Type:   event.PointsChanged,
+			UserId: rewardReqData.UserId,
+			Data:   event.Points{Source: user.PointTypeRewardRefund},
+		})
+	}
+
 	return nil
 }
@@ -0,0 +1,11 @@
+package dto
+
+type RoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type UserRoleRequest struct {
+	Role string `json:"role"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+type RoleDataInterface interface {
+	CreateRole(input RoleCore) error
+	FindAllRole() ([]RoleCore, error)
+	FindRoleById(id string) (RoleCore, error)
+	FindRoleByName(name string) (RoleCore, error)
+	UpdateRole(id string, data RoleCore) error
+	DeleteRole(id string) error
+	CountRoleUser(name string) (int, error)
+
+	FindAllPermission() ([]PermissionCore, error)
+	RolePermissions(role string) ([]string, error)
+
+	UpdateUserRole(userId string, role string) error
+}
+
+type RoleUseCaseInterface interface {
+	CreateRole(input RoleCore) error
+	FindAllRole() ([]RoleCore, error)
+	FindRoleById(id string) (RoleCore, error)
+	UpdateRole(id string, data RoleCore) error
+	DeleteRole(id string) error
+
+	FindAllPermission() ([]PermissionCore, error)
+
+	UpdateUserRole(userId string, role string) error
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "tugaskita/features/role/model"
+
+func RoleCoreToRoleModel(data RoleCore) model.Role {
+	return model.Role{
+		Id:          data.Id,
+		Name:        data.Name,
+		Description: data.Description,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func RoleModelToRoleCore(data model.Role) RoleCore {
+	return RoleCore{
+		Id:          data.Id,
+		Name:        data.Name,
+		Description: data.Description,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func PermissionModelToPermissionCore(data model.Permission) PermissionCore {
+	return PermissionCore{
+		Name:        data.Name,
+		Description: data.Description,
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"net/http"
+	"tugaskita/features/role/dto"
+	"tugaskita/features/role/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoleController struct {
+	roleUsecase entity.RoleUseCaseInterface
+}
+
+func New(roleUC entity.RoleUseCaseInterface) *RoleController {
+	return &RoleController{
+		roleUsecase: roleUC,
+	}
+}
+
+func roleResponse(data entity.RoleCore) dto.RoleResponse {
+	permissions := data.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return dto.RoleResponse{
+		Id:          data.Id.String(),
+		Name:        data.Name,
+		Description: data.Description,
+		Permissions: permissions,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func (handler *RoleController) AddRole(e echo.Context) error {
+	input := dto.RoleRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.RoleCore{
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: input.Permissions,
+	}
+
+	errRole := handler.roleUsecase.CreateRole(data)
+	if errRole != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error create role",
+			"error":   errRole.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "succes create role",
+	})
+}
+
+func (handler *RoleController) ReadAllRole(e echo.Context) error {
+	data, err := handler.roleUsecase.FindAllRole()
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all role",
+		})
+	}
+
+	dataList := []dto.RoleResponse{}
+	for _, v := range data {
+		dataList = append(dataList, roleResponse(v))
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all role",
+		"data":    dataList,
+	})
+}
+
+func (handler *RoleController) ReadSpecificRole(e echo.Context) error {
+	idParams := e.Param("id")
+
+	data, err := handler.roleUsecase.FindRoleById(idParams)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get specific role",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get role",
+		"data":    roleResponse(data),
+	})
+}
+
+func (handler *RoleController) UpdateRole(e echo.Context) error {
+	idParams := e.Param("id")
+
+	input := dto.RoleRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.RoleCore{
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: input.Permissions,
+	}
+
+	err := handler.roleUsecase.UpdateRole(idParams, data)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error update role",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success update role",
+	})
+}
+
+func (handler *RoleController) DeleteRole(e echo.Context) error {
+	idParams := e.Param("id")
+
+	err := handler.roleUsecase.DeleteRole(idParams)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error delete role",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success delete role",
+	})
+}
+
+func (handler *RoleController) ReadAllPermission(e echo.Context) error {
+	data, err := handler.roleUsecase.FindAllPermission()
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all permission",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all permission",
+		"data":    data,
+	})
+}
+
+func (handler *RoleController) UpdateUserRole(e echo.Context) error {
+	idParams := e.Param("id")
+
+	input := dto.UserRoleRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	err := handler.roleUsecase.UpdateUserRole(idParams, input.Role)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error update user role",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success update user role",
+	})
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Role struct {
+	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	Name        string    `gorm:"type:varchar(25);uniqueIndex;not null"`
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Permission struct {
+	Name        string `gorm:"type:varchar(50);primaryKey;not null"`
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type RolePermission struct {
+	RoleId         uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	PermissionName string    `gorm:"type:varchar(50);primaryKey;not null"`
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"errors"
+	"tugaskita/features/role/entity"
+	"tugaskita/features/role/model"
+	user "tugaskita/features/user/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) entity.RoleDataInterface {
+	return &RoleRepository{
+		db: db,
+	}
+}
+
+func rolePermissionModels(roleId uuid.UUID, permissions []string) []model.RolePermission {
+	data := []model.RolePermission{}
+	for _, v := range permissions {
+		data = append(data, model.RolePermission{
+			RoleId:         roleId,
+			PermissionName: v,
+		})
+	}
+	return data
+}
+
+func (roleRepo *RoleRepository) findPermissions(roleId uuid.UUID) ([]string, error) {
+	var permissions []string
+	err := roleRepo.db.Model(&model.RolePermission{}).
+		Where("role_id = ?", roleId).
+		Order("permission_name").
+		Pluck("permission_name", &permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// CreateRole implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) CreateRole(input entity.RoleCore) error {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return UUIDerr
+	}
+
+	data := entity.RoleCoreToRoleModel(input)
+	data.Id = newUUID
+
+	return roleRepo.db.Transaction(func(tx *gorm.DB) error {
+		errCreate := tx.Create(&data).Error
+		if errCreate != nil {
+			return errCreate
+		}
+
+		if len(input.Permissions) > 0 {
+			errPermission := tx.Create(rolePermissionModels(newUUID, input.Permissions)).Error
+			if errPermission != nil {
+				return errPermission
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindAllRole implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) FindAllRole() ([]entity.RoleCore, error) {
+	var dataRole []model.Role
+
+	errData := roleRepo.db.Order("name").Find(&dataRole).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataResponse := []entity.RoleCore{}
+	for _, v := range dataRole {
+		result := entity.RoleModelToRoleCore(v)
+		result.Permissions, errData = roleRepo.findPermissions(v.Id)
+		if errData != nil {
+			return nil, errData
+		}
+		dataResponse = append(dataResponse, result)
+	}
+	return dataResponse, nil
+}
+
+// FindRoleById implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) FindRoleById(id string) (entity.RoleCore, error) {
+	dataRole := model.Role{}
+
+	tx := roleRepo.db.Where("id = ?", id).First(&dataRole)
+	if tx.Error != nil {
+		return entity.RoleCore{}, tx.Error
+	}
+
+	dataResponse := entity.RoleModelToRoleCore(dataRole)
+	permissions, err := roleRepo.findPermissions(dataRole.Id)
+	if err != nil {
+		return entity.RoleCore{}, err
+	}
+	dataResponse.Permissions = permissions
+
+	return dataResponse, nil
+}
+
+// FindRoleByName implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) FindRoleByName(name string) (entity.RoleCore, error) {
+	dataRole := model.Role{}
+
+	tx := roleRepo.db.Where("name = ?", name).First(&dataRole)
+	if tx.Error != nil {
+		return entity.RoleCore{}, tx.Error
+	}
+
+	return entity.RoleModelToRoleCore(dataRole), nil
+}
+
+// UpdateRole implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) UpdateRole(id string, data entity.RoleCore) error {
+	dataRole := model.Role{}
+
+	return roleRepo.db.Transaction(func(tx *gorm.DB) error {
+		errData := tx.Where("id = ?", id).First(&dataRole).Error
+		if errData != nil {
+			return errors.New("role not found")
+		}
+
+		oldName := dataRole.Name
+
+		errUpdate := tx.Model(&dataRole).Updates(map[string]any{
+			"name":        data.Name,
+			"description": data.Description,
+		}).Error
+		if errUpdate != nil {
+			return errUpdate
+		}
+
+		//users keep the role by name
+		if oldName != data.Name {
+			errUser := tx.Model(&user.Users{}).Where("role = ?", oldName).Update("role", data.Name).Error
+			if errUser != nil {
+				return errUser
+			}
+		}
+
+		//nil permissions keep the current ones
+		if data.Permissions == nil {
+			return nil
+		}
+
+		errDelete := tx.Where("role_id = ?", dataRole.Id).Delete(&model.RolePermission{}).Error
+		if errDelete != nil {
+			return errDelete
+		}
+
+		if len(data.Permissions) > 0 {
+			errPermission := tx.Create(rolePermissionModels(dataRole.Id, data.Permissions)).Error
+			if errPermission != nil {
+				return errPermission
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteRole implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) DeleteRole(id string) error {
+	return roleRepo.db.Transaction(func(tx *gorm.DB) error {
+		errPermission := tx.Where("role_id = ?", id).Delete(&model.RolePermission{}).Error
+		if errPermission != nil {
+			return errPermission
+		}
+
+		delete := tx.Where("id = ?", id).Delete(&model.Role{})
+		if delete.Error != nil {
+			return delete.Error
+		}
+
+		if delete.RowsAffected == 0 {
+			return errors.New("role not found")
+		}
+
+		return nil
+	})
+}
+
+// CountRoleUser implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) CountRoleUser(name string) (int, error) {
+	var count int64
+
+	err := roleRepo.db.Model(&user.Users{}).Where("role = ?", name).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// FindAllPermission implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) FindAllPermission() ([]entity.PermissionCore, error) {
+	var dataPermission []model.Permission
+
+	errData := roleRepo.db.Order("name").Find(&dataPermission).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataResponse := []entity.PermissionCore{}
+	for _, v := range dataPermission {
+		dataResponse = append(dataResponse, entity.PermissionModelToPermissionCore(v))
+	}
+	return dataResponse, nil
+}
+
+// RolePermissions implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) RolePermissions(role string) ([]string, error) {
+	var permissions []string
+
+	err := roleRepo.db.Model(&model.RolePermission{}).
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = ?", role).
+		Pluck("role_permissions.permission_name", &permissions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// UpdateUserRole implements entity.RoleDataInterface.
+func (roleRepo *RoleRepository) UpdateUserRole(userId string, role string) error {
+	tx := roleRepo.db.Model(&user.Users{}).Where("id = ?", userId).Update("role", role)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"tugaskita/features/role/entity"
+	user "tugaskita/features/user/entity"
+	"tugaskita/utils/authz"
+)
+
+type RoleService struct {
+	RoleRepo entity.RoleDataInterface
+	UserRepo user.UserDataInterface
+}
+
+func NewRoleService(roleRepo entity.RoleDataInterface, userRepo user.UserDataInterface) entity.RoleUseCaseInterface {
+	return &RoleService{
+		RoleRepo: roleRepo,
+		UserRepo: userRepo,
+	}
+}
+
+func (roleUC *RoleService) validatePermissions(permissions []string) error {
+	known := map[string]bool{}
+	for _, v := range authz.Permissions() {
+		known[v.Name] = true
+	}
+
+	seen := map[string]bool{}
+	for _, v := range permissions {
+		if !known[v] {
+			return errors.New("unknown permission " + v)
+		}
+		if seen[v] {
+			return errors.New("duplicate permission " + v)
+		}
+		seen[v] = true
+	}
+
+	return nil
+}
+
+// CreateRole implements entity.RoleUseCaseInterface.
+func (roleUC *RoleService) CreateRole(input entity.RoleCore) error {
+	input.Name = strings.ToLower(strings.TrimSpace(input.Name))
+	if input.Name == "" {
+		return errors.New("role name can't be empty")
+	}
+
+	_, errRole := roleUC.RoleRepo.FindRoleByName(input.Name)
+	if errRole == nil {
+		return errors.New("role already exist")
+	}
+
+	errPermission := roleUC.validatePermissions(input.Permissions)
+	if errPermission != nil {
+		return errPermission
+	}
+
+	err := roleUC.RoleRepo.CreateRole(input)
+	if err != nil {
+		return err
+	}
+
+	authz.Invalidate()
+	return nil
+}
+
+// FindAllRole implements entity.RoleUseCaseInterface.
+func (roleUC *RoleService) FindAllRole() ([]entity.RoleCore, error) {
+	data, err := roleUC.RoleRepo.FindAllRole()
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindRoleById implements entity.RoleUseCaseInterface.
+func (roleUC *RoleService) FindRoleById(id string) (entity.RoleCore, error) {
+	if id == "" {
+		return entity.RoleCore{}, errors.New("role ID is required")
+	}
+
+	data, err := roleUC.RoleRepo.FindRoleById(id)
+	if err != nil {
+		return entity.RoleCore{}, errors.New("role not found")
+	}
+
+	return data, nil
+}
+
+// UpdateRole implements entity.RoleUseCaseInterface.
+func (roleUC *RoleService) UpdateRole(id string, data entity.RoleCore) error {
+	roleData, err := roleUC.RoleRepo.FindRoleById(id)
+	if err != nil {
+		return errors.New("role not found")
+	}
+
+	data.Name = strings.ToLower(strings.TrimSpace(data.Name))
+	if data.Name == "" {
+		data.Name = roleData.Name
+	}
+
+	if roleData.Name == authz.RoleAdmin || roleData.Name == authz.RoleUser {
+		if data.Name != roleData.Name {
+			return errors.New("can't rename built in role")
+		}
+	}
+
+	if roleData.Name == authz.RoleAdmin && data.Permissions != nil {
+		return errors.New("admin always has every permission")
+	}
+
+	if data.Name != roleData.Name {
+		_, errRole := roleUC.RoleRepo.FindRoleByName(data.Name)
+		if errRole == nil {
+			return errors.New("role already exist")
+		}
+	}
+
+	errPermission := roleUC.validatePermissions(data.Permissions)
+	if errPermission != nil {
+		return errPermission
+	}
+
+	errUpdate := roleUC.RoleRepo.UpdateRole(id, data)
+	if errUpdate != nil {
+		return errUpdate
+	}
+
+	authz.Invalidate()
+	return nil
+}
+
+// DeleteRole implements entity.RoleUseCaseInterface.
+func (roleUC *RoleService) DeleteRole(id string) error {
+	roleData, err := roleUC.RoleRepo.FindRoleById(id)
+	if err != nil {
+		return errors.New("role not found")
+	}
+
+	if roleData.Name == authz.RoleAdmin || roleData.Name == authz.RoleUser {
+		return errors.New("can't delete built in role")
+	}
+
+	count, errCount := roleUC.RoleRepo.CountRoleUser(roleData.Name)
+	if errCount != nil {
+		return errCount
+	}
+
+	if count > 0 {
+		return errors.New("role is still used by users")
+	}
+
+	errDelete := roleUC.RoleRepo.DeleteRole(id)
+	if errDelete != nil {
+		return errors.New("can't delete role")
+	}
+
+	authz.Invalidate()
+	return nil
+}
+
+// FindAllPermission implements entity.RoleUseCaseInterface.
+func (roleUC *RoleService) FindAllPermission() ([]entity.PermissionCore, error) {
+	data, err := roleUC.RoleRepo.FindAllPermission()
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// UpdateUserRole implements entity.RoleUseCaseInterface. Permissions are
+// read from the role in the token, so a changed role logs out every
+// session of the user.
+func (roleUC *RoleService) UpdateUserRole(userId string, role string) error {
+	user, errUser := roleUC.UserRepo.ReadSpecificUser(userId)
+	if errUser != nil {
+		return errors.New("user not found")
+	}
+
+	_, errRole := roleUC.RoleRepo.FindRoleByName(role)
+	if errRole != nil {
+		return errors.New("role not found")
+	}
+
+	errUpdate := roleUC.RoleRepo.UpdateUserRole(userId, role)
+	if errUpdate != nil {
+		return errUpdate
+	}
+
+	if user.Role == role {
+		return nil
+	}
+
+	errRevoke := roleUC.UserRepo.RevokeUserSessions(userId)
+	if errRevoke != nil {
+		return errors.New("failed revoke user sessions")
+	}
+
+	return nil
+}
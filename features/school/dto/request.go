@@ -0,0 +1,18 @@
+package dto
+
+type SchoolRequest struct {
+	Name    string `json:"name" form:"name"`
+	Address string `json:"address" form:"address"`
+}
+
+type ClassRequest struct {
+	SchoolId          string `json:"school_id" form:"school_id"`
+	Name              string `json:"name" form:"name"`
+	Grade             int    `json:"grade" form:"grade"`
+	AcademicYear      string `json:"academic_year" form:"academic_year"`
+	HomeroomTeacherId string `json:"homeroom_teacher_id" form:"homeroom_teacher_id"`
+}
+
+type AssignStudentRequest struct {
+	UserIds []string `json:"user_ids"`
+}
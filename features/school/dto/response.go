@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+type SchoolResponse struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ClassResponse struct {
+	Id                  string    `json:"id"`
+	SchoolId            string    `json:"school_id"`
+	SchoolName          string    `json:"school_name"`
+	Name                string    `json:"name"`
+	Grade               int       `json:"grade"`
+	AcademicYear        string    `json:"academic_year"`
+	HomeroomTeacherId   string    `json:"homeroom_teacher_id"`
+	HomeroomTeacherName string    `json:"homeroom_teacher_name"`
+	StudentCount        int       `json:"student_count"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type ClassRankResponse struct {
+	Rank         int     `json:"rank"`
+	ClassId      string  `json:"class_id"`
+	ClassName    string  `json:"class_name"`
+	Grade        int     `json:"grade"`
+	AcademicYear string  `json:"academic_year"`
+	StudentCount int     `json:"student_count"`
+	TotalPoint   int     `json:"total_point"`
+	AveragePoint float64 `json:"average_point"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SchoolCore struct {
+	Id        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ClassCore struct {
+	Id                  uuid.UUID `json:"id"`
+	SchoolId            string    `json:"school_id"`
+	SchoolName          string    `json:"school_name"`
+	Name                string    `json:"name"`
+	Grade               int       `json:"grade"`
+	AcademicYear        string    `json:"academic_year"`
+	HomeroomTeacherId   string    `json:"homeroom_teacher_id"`
+	HomeroomTeacherName string    `json:"homeroom_teacher_name"`
+	StudentCount        int       `json:"student_count"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type ClassFilter struct {
+	SchoolId     string
+	Grade        int
+	AcademicYear string
+}
+
+// ClassRankCore is the aggregate standing of a class against other classes.
+type ClassRankCore struct {
+	Rank         int     `json:"rank"`
+	ClassId      string  `json:"class_id"`
+	ClassName    string  `json:"class_name"`
+	Grade        int     `json:"grade"`
+	AcademicYear string  `json:"academic_year"`
+	StudentCount int     `json:"student_count"`
+	TotalPoint   int     `json:"total_point"`
+	AveragePoint float64 `json:"average_point"`
+}
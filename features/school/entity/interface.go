@@ -0,0 +1,39 @@
+package entity
+
+type SchoolDataInterface interface {
+	CreateSchool(input SchoolCore) error
+	FindAllSchool() ([]SchoolCore, error)
+	FindSchoolById(id string) (SchoolCore, error)
+	FindSchoolByName(name string) (SchoolCore, error)
+	UpdateSchool(id string, data SchoolCore) error
+	DeleteSchool(id string) error
+
+	CreateClass(input ClassCore) error
+	FindAllClass(filter ClassFilter) ([]ClassCore, error)
+	FindClassById(id string) (ClassCore, error)
+	FindClass(schoolId string, name string, academicYear string) (ClassCore, error)
+	UpdateClass(id string, data ClassCore) error
+	DeleteClass(id string) error
+	CountClassStudent(id string) (int, error)
+	CountSchoolClass(id string) (int, error)
+	AssignStudent(classId string, userIds []string) error
+
+	ClassRank(filter ClassFilter, orderBy string) ([]ClassRankCore, error)
+}
+
+type SchoolUseCaseInterface interface {
+	CreateSchool(input SchoolCore) error
+	FindAllSchool() ([]SchoolCore, error)
+	FindSchoolById(id string) (SchoolCore, error)
+	UpdateSchool(id string, data SchoolCore) error
+	DeleteSchool(id string) error
+
+	CreateClass(input ClassCore) error
+	FindAllClass(filter ClassFilter) ([]ClassCore, error)
+	FindClassById(id string) (ClassCore, error)
+	UpdateClass(id string, data ClassCore) error
+	DeleteClass(id string) error
+	AssignStudent(classId string, userIds []string) error
+
+	ClassRank(filter ClassFilter, orderBy string) ([]ClassRankCore, error)
+}
@@ -0,0 +1,49 @@
+package entity
+
+import "tugaskita/features/school/model"
+
+func SchoolCoreToSchoolModel(data SchoolCore) model.School {
+	return model.School{
+		Id:        data.Id,
+		Name:      data.Name,
+		Address:   data.Address,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+}
+
+func SchoolModelToSchoolCore(data model.School) SchoolCore {
+	return SchoolCore{
+		Id:        data.Id,
+		Name:      data.Name,
+		Address:   data.Address,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+}
+
+func ClassCoreToClassModel(data ClassCore) model.Class {
+	return model.Class{
+		Id:                data.Id,
+		SchoolId:          data.SchoolId,
+		Name:              data.Name,
+		Grade:             data.Grade,
+		AcademicYear:      data.AcademicYear,
+		HomeroomTeacherId: data.HomeroomTeacherId,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
+	}
+}
+
+func ClassModelToClassCore(data model.Class) ClassCore {
+	return ClassCore{
+		Id:                data.Id,
+		SchoolId:          data.SchoolId,
+		Name:              data.Name,
+		Grade:             data.Grade,
+		AcademicYear:      data.AcademicYear,
+		HomeroomTeacherId: data.HomeroomTeacherId,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
+	}
+}
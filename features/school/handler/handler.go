@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"tugaskita/features/school/dto"
+	"tugaskita/features/school/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SchoolController struct {
+	schoolUsecase entity.SchoolUseCaseInterface
+}
+
+func New(schoolUC entity.SchoolUseCaseInterface) *SchoolController {
+	return &SchoolController{
+		schoolUsecase: schoolUC,
+	}
+}
+
+func schoolResponse(data entity.SchoolCore) dto.SchoolResponse {
+	return dto.SchoolResponse{
+		Id:        data.Id.String(),
+		Name:      data.Name,
+		Address:   data.Address,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+}
+
+func classResponse(data entity.ClassCore) dto.ClassResponse {
+	return dto.ClassResponse{
+		Id:                  data.Id.String(),
+		SchoolId:            data.SchoolId,
+		SchoolName:          data.SchoolName,
+		Name:                data.Name,
+		Grade:               data.Grade,
+		AcademicYear:        data.AcademicYear,
+		HomeroomTeacherId:   data.HomeroomTeacherId,
+		HomeroomTeacherName: data.HomeroomTeacherName,
+		StudentCount:        data.StudentCount,
+		CreatedAt:           data.CreatedAt,
+		UpdatedAt:           data.UpdatedAt,
+	}
+}
+
+func classFilter(e echo.Context) entity.ClassFilter {
+	grade, _ := strconv.Atoi(e.QueryParam("grade"))
+
+	return entity.ClassFilter{
+		SchoolId:     e.QueryParam("school_id"),
+		Grade:        grade,
+		AcademicYear: e.QueryParam("academic_year"),
+	}
+}
+
+func (handler *SchoolController) AddSchool(e echo.Context) error {
+	input := dto.SchoolRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.SchoolCore{
+		Name:    input.Name,
+		Address: input.Address,
+	}
+
+	errSchool := handler.schoolUsecase.CreateSchool(data)
+	if errSchool != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error create school",
+			"error":   errSchool.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "succes create school",
+	})
+}
+
+func (handler *SchoolController) ReadAllSchool(e echo.Context) error {
+	data, err := handler.schoolUsecase.FindAllSchool()
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all school",
+		})
+	}
+
+	dataList := []dto.SchoolResponse{}
+	for _, v := range data {
+		dataList = append(dataList, schoolResponse(v))
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all school",
+		"data":    dataList,
+	})
+}
+
+func (handler *SchoolController) ReadSpecificSchool(e echo.Context) error {
+	idParams := e.Param("id")
+
+	data, err := handler.schoolUsecase.FindSchoolById(idParams)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get specific school",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get school",
+		"data":    schoolResponse(data),
+	})
+}
+
+func (handler *SchoolController) UpdateSchool(e echo.Context) error {
+	idParams := e.Param("id")
+
+	input := dto.SchoolRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.SchoolCore{
+		Name:    input.Name,
+		Address: input.Address,
+	}
+
+	err := handler.schoolUsecase.UpdateSchool(idParams, data)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error update school",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success update school",
+	})
+}
+
+func (handler *SchoolController) DeleteSchool(e echo.Context) error {
+	idParams := e.Param("id")
+
+	err := handler.schoolUsecase.DeleteSchool(idParams)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error delete school",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success delete school",
+	})
+}
+
+func (handler *SchoolController) AddClass(e echo.Context) error {
+	input := dto.ClassRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.ClassCore{
+		SchoolId:          input.SchoolId,
+		Name:              input.Name,
+		Grade:             input.Grade,
+		AcademicYear:      input.AcademicYear,
+		HomeroomTeacherId: input.HomeroomTeacherId,
+	}
+
+	errClass := handler.schoolUsecase.CreateClass(data)
+	if errClass != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error create class",
+			"error":   errClass.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "succes create class",
+	})
+}
+
+func (handler *SchoolController) ReadAllClass(e echo.Context) error {
+	data, err := handler.schoolUsecase.FindAllClass(classFilter(e))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all class",
+		})
+	}
+
+	dataList := []dto.ClassResponse{}
+	for _, v := range data {
+		dataList = append(dataList, classResponse(v))
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all class",
+		"data":    dataList,
+	})
+}
+
+func (handler *SchoolController) ReadSpecificClass(e echo.Context) error {
+	idParams := e.Param("id")
+
+	data, err := handler.schoolUsecase.FindClassById(idParams)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get specific class",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get class",
+		"data":    classResponse(data),
+	})
+}
+
+func (handler *SchoolController) UpdateClass(e echo.Context) error {
+	idParams := e.Param("id")
+
+	input := dto.ClassRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.ClassCore{
+		Name:              input.Name,
+		Grade:             input.Grade,
+		AcademicYear:      input.AcademicYear,
+		HomeroomTeacherId: input.HomeroomTeacherId,
+	}
+
+	err := handler.schoolUsecase.UpdateClass(idParams, data)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error update class",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success update class",
+	})
+}
+
+func (handler *SchoolController) DeleteClass(e echo.Context) error {
+	idParams := e.Param("id")
+
+	err := handler.schoolUsecase.DeleteClass(idParams)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error delete class",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success delete class",
+	})
+}
+
+func (handler *SchoolController) AssignStudent(e echo.Context) error {
+	idParams := e.Param("id")
+
+	input := dto.AssignStudentRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	err := handler.schoolUsecase.AssignStudent(idParams, input.UserIds)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error assign student",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success assign student",
+	})
+}
+
+func (handler *SchoolController) ClassRank(e echo.Context) error {
+	data, err := handler.schoolUsecase.ClassRank(classFilter(e), e.QueryParam("order"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get class rank",
+			"error":   err.Error(),
+		})
+	}
+
+	dataList := []dto.ClassRankResponse{}
+	for _, v := range data {
+		dataList = append(dataList, dto.ClassRankResponse{
+			Rank:         v.Rank,
+			ClassId:      v.ClassId,
+			ClassName:    v.ClassName,
+			Grade:        v.Grade,
+			AcademicYear: v.AcademicYear,
+			StudentCount: v.StudentCount,
+			TotalPoint:   v.TotalPoint,
+			AveragePoint: math.Round(v.AveragePoint*100) / 100,
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get class rank",
+		"data":    dataList,
+	})
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type School struct {
+	Id        uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	Name      string    `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Address   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Class is a class of a school in one academic year, e.g. "XI IPA 1" in
+// grade 11 of "2024/2025".
+type Class struct {
+	Id                uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	SchoolId          string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_class_school_name_year"`
+	School            *School   `gorm:"foreignKey:SchoolId;constraint:OnDelete:RESTRICT"`
+	Name              string    `gorm:"type:varchar(25);not null;uniqueIndex:idx_class_school_name_year"`
+	Grade             int       `gorm:"not null;index"`
+	AcademicYear      string    `gorm:"type:varchar(9);not null;uniqueIndex:idx_class_school_name_year"`
+	HomeroomTeacherId string    `gorm:"type:varchar(50);index"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
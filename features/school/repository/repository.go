@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"errors"
+	"tugaskita/features/school/entity"
+	"tugaskita/features/school/model"
+	user "tugaskita/features/user/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SchoolRepository struct {
+	db *gorm.DB
+}
+
+func NewSchoolRepository(db *gorm.DB) entity.SchoolDataInterface {
+	return &SchoolRepository{
+		db: db,
+	}
+}
+
+type classRow struct {
+	model.Class
+	SchoolName          string
+	HomeroomTeacherName string
+	StudentCount        int
+}
+
+func (schoolRepo *SchoolRepository) classQuery() *gorm.DB {
+	return schoolRepo.db.Table("classes").
+		Select("classes.*, schools.name AS school_name, teacher.name AS homeroom_teacher_name, " +
+			"(SELECT COUNT(*) FROM users WHERE users.class_id = classes.id) AS student_count").
+		Joins("LEFT JOIN schools ON schools.id = classes.school_id").
+		Joins("LEFT JOIN users teacher ON teacher.id = classes.homeroom_teacher_id")
+}
+
+func classRowToClassCore(data classRow) entity.ClassCore {
+	result := entity.ClassModelToClassCore(data.Class)
+	result.SchoolName = data.SchoolName
+	result.HomeroomTeacherName = data.HomeroomTeacherName
+	result.StudentCount = data.StudentCount
+	return result
+}
+
+// CreateSchool implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) CreateSchool(input entity.SchoolCore) error {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return UUIDerr
+	}
+
+	data := entity.SchoolCoreToSchoolModel(input)
+	data.Id = newUUID
+	tx := schoolRepo.db.Create(&data)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// FindAllSchool implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) FindAllSchool() ([]entity.SchoolCore, error) {
+	var dataSchool []model.School
+
+	errData := schoolRepo.db.Order("name").Find(&dataSchool).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataResponse := []entity.SchoolCore{}
+	for _, v := range dataSchool {
+		dataResponse = append(dataResponse, entity.SchoolModelToSchoolCore(v))
+	}
+	return dataResponse, nil
+}
+
+// FindSchoolById implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) FindSchoolById(id string) (entity.SchoolCore, error) {
+	dataSchool := model.School{}
+
+	tx := schoolRepo.db.Where("id = ?", id).First(&dataSchool)
+	if tx.Error != nil {
+		return entity.SchoolCore{}, tx.Error
+	}
+
+	return entity.SchoolModelToSchoolCore(dataSchool), nil
+}
+
+// FindSchoolByName implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) FindSchoolByName(name string) (entity.SchoolCore, error) {
+	dataSchool := model.School{}
+
+	tx := schoolRepo.db.Where("name = ?", name).First(&dataSchool)
+	if tx.Error != nil {
+		return entity.SchoolCore{}, tx.Error
+	}
+
+	return entity.SchoolModelToSchoolCore(dataSchool), nil
+}
+
+// UpdateSchool implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) UpdateSchool(id string, data entity.SchoolCore) error {
+	return schoolRepo.db.Transaction(func(tx *gorm.DB) error {
+		update := tx.Model(&model.School{}).Where("id = ?", id).Updates(map[string]any{
+			"name":    data.Name,
+			"address": data.Address,
+		})
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if update.RowsAffected == 0 {
+			return errors.New("school not found")
+		}
+
+		//keep the legacy text column in sync
+		return tx.Model(&user.Users{}).Where("school_id = ?", id).Update("school", data.Name).Error
+	})
+}
+
+// DeleteSchool implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) DeleteSchool(id string) error {
+	tx := schoolRepo.db.Where("id = ?", id).Delete(&model.School{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.New("school not found")
+	}
+
+	return nil
+}
+
+// CreateClass implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) CreateClass(input entity.ClassCore) error {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return UUIDerr
+	}
+
+	data := entity.ClassCoreToClassModel(input)
+	data.Id = newUUID
+	tx := schoolRepo.db.Create(&data)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// FindAllClass implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) FindAllClass(filter entity.ClassFilter) ([]entity.ClassCore, error) {
+	var dataClass []classRow
+
+	query := schoolRepo.classQuery()
+	if filter.SchoolId != "" {
+		query = query.Where("classes.school_id = ?", filter.SchoolId)
+	}
+	if filter.Grade != 0 {
+		query = query.Where("classes.grade = ?", filter.Grade)
+	}
+	if filter.AcademicYear != "" {
+		query = query.Where("classes.academic_year = ?", filter.AcademicYear)
+	}
+
+	errData := query.Order("classes.academic_year desc, classes.grade, classes.name").Scan(&dataClass).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataResponse := []entity.ClassCore{}
+	for _, v := range dataClass {
+		dataResponse = append(dataResponse, classRowToClassCore(v))
+	}
+	return dataResponse, nil
+}
+
+// FindClassById implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) FindClassById(id string) (entity.ClassCore, error) {
+	var dataClass classRow
+
+	tx := schoolRepo.classQuery().Where("classes.id = ?", id).Take(&dataClass)
+	if tx.Error != nil {
+		return entity.ClassCore{}, tx.Error
+	}
+
+	return classRowToClassCore(dataClass), nil
+}
+
+// FindClass implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) FindClass(schoolId string, name string, academicYear string) (entity.ClassCore, error) {
+	dataClass := model.Class{}
+
+	tx := schoolRepo.db.Where("school_id = ? AND name = ? AND academic_year = ?", schoolId, name, academicYear).First(&dataClass)
+	if tx.Error != nil {
+		return entity.ClassCore{}, tx.Error
+	}
+
+	return entity.ClassModelToClassCore(dataClass), nil
+}
+
+// UpdateClass implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) UpdateClass(id string, data entity.ClassCore) error {
+	return schoolRepo.db.Transaction(func(tx *gorm.DB) error {
+		update := tx.Model(&model.Class{}).Where("id = ?", id).Updates(map[string]any{
+			"name":                data.Name,
+			"grade":               data.Grade,
+			"academic_year":       data.AcademicYear,
+			"homeroom_teacher_id": data.HomeroomTeacherId,
+		})
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if update.RowsAffected == 0 {
+			return errors.New("class not found")
+		}
+
+		//keep the legacy text column in sync
+		return tx.Model(&user.Users{}).Where("class_id = ?", id).Update("class", data.Name).Error
+	})
+}
+
+// DeleteClass implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) DeleteClass(id string) error {
+	tx := schoolRepo.db.Where("id = ?", id).Delete(&model.Class{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.New("class not found")
+	}
+
+	return nil
+}
+
+// CountClassStudent implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) CountClassStudent(id string) (int, error) {
+	var count int64
+
+	err := schoolRepo.db.Model(&user.Users{}).Where("class_id = ?", id).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// CountSchoolClass implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) CountSchoolClass(id string) (int, error) {
+	var count int64
+
+	err := schoolRepo.db.Model(&model.Class{}).Where("school_id = ?", id).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// AssignStudent implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) AssignStudent(classId string, userIds []string) error {
+	return schoolRepo.db.Transaction(func(tx *gorm.DB) error {
+		var class model.Class
+		errClass := tx.Preload("School").Where("id = ?", classId).First(&class).Error
+		if errClass != nil {
+			return errors.New("class not found")
+		}
+
+		schoolName := ""
+		if class.School != nil {
+			schoolName = class.School.Name
+		}
+
+		update := tx.Model(&user.Users{}).
+			Where("id IN ? AND role = ?", userIds, "user").
+			Updates(map[string]any{
+				"class_id":  class.Id.String(),
+				"school_id": class.SchoolId,
+				"class":     class.Name,
+				"school":    schoolName,
+			})
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if int(update.RowsAffected) != len(userIds) {
+			return errors.New("some users are not found or not a student")
+		}
+
+		return nil
+	})
+}
+
+// ClassRank implements entity.SchoolDataInterface.
+func (schoolRepo *SchoolRepository) ClassRank(filter entity.ClassFilter, orderBy string) ([]entity.ClassRankCore, error) {
+	var dataRank []entity.ClassRankCore
+
+	query := schoolRepo.db.Table("classes").
+		Select("classes.id AS class_id, classes.name AS class_name, classes.grade, classes.academic_year, "+
+			"COUNT(users.id) AS student_count, COALESCE(SUM(users.point), 0) AS total_point, "+
+			"COALESCE(AVG(users.point), 0) AS average_point").
+		Joins("LEFT JOIN users ON users.class_id = classes.id AND users.role = ?", "user").
+		Where("classes.school_id = ?", filter.SchoolId).
+		Group("classes.id, classes.name, classes.grade, classes.academic_year")
+
+	if filter.Grade != 0 {
+		query = query.Where("classes.grade = ?", filter.Grade)
+	}
+	if filter.AcademicYear != "" {
+		query = query.Where("classes.academic_year = ?", filter.AcademicYear)
+	}
+
+	if orderBy == "average" {
+		query = query.Order("average_point desc")
+	} else {
+		query = query.Order("total_point desc")
+	}
+
+	errData := query.Order("classes.name").Scan(&dataRank).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	return dataRank, nil
+}
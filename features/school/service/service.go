@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"tugaskita/features/school/entity"
+	user "tugaskita/features/user/entity"
+)
+
+var academicYearPattern = regexp.MustCompile(`^(\d{4})/(\d{4})$`)
+
+type SchoolService struct {
+	SchoolRepo entity.SchoolDataInterface
+	UserRepo   user.UserDataInterface
+}
+
+func NewSchoolService(schoolRepo entity.SchoolDataInterface, userRepo user.UserDataInterface) entity.SchoolUseCaseInterface {
+	return &SchoolService{
+		SchoolRepo: schoolRepo,
+		UserRepo:   userRepo,
+	}
+}
+
+func validateAcademicYear(year string) error {
+	match := academicYearPattern.FindStringSubmatch(year)
+	if match == nil {
+		return errors.New("academic year must use YYYY/YYYY format")
+	}
+
+	start, _ := strconv.Atoi(match[1])
+	end, _ := strconv.Atoi(match[2])
+	if end != start+1 {
+		return errors.New("academic year must span two consecutive years")
+	}
+
+	return nil
+}
+
+func (schoolUC *SchoolService) validateSchool(input entity.SchoolCore) error {
+	if input.Name == "" {
+		return errors.New("school name can't be empty")
+	}
+
+	if len(input.Name) > 50 {
+		return errors.New("school name max 50 character")
+	}
+
+	return nil
+}
+
+func (schoolUC *SchoolService) validateClass(input entity.ClassCore) error {
+	if input.Name == "" {
+		return errors.New("class name can't be empty")
+	}
+
+	if len(input.Name) > 25 {
+		return errors.New("class name max 25 character")
+	}
+
+	if input.Grade < 1 || input.Grade > 12 {
+		return errors.New("grade must be between 1 and 12")
+	}
+
+	errYear := validateAcademicYear(input.AcademicYear)
+	if errYear != nil {
+		return errYear
+	}
+
+	if input.HomeroomTeacherId != "" {
+		teacher, errTeacher := schoolUC.UserRepo.ReadSpecificUser(input.HomeroomTeacherId)
+		if errTeacher != nil {
+			return errors.New("homeroom teacher not found")
+		}
+
+		if teacher.Role == "user" {
+			return errors.New("homeroom teacher can't be a student")
+		}
+	}
+
+	return nil
+}
+
+// CreateSchool implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) CreateSchool(input entity.SchoolCore) error {
+	input.Name = strings.TrimSpace(input.Name)
+	errValidate := schoolUC.validateSchool(input)
+	if errValidate != nil {
+		return errValidate
+	}
+
+	_, errSchool := schoolUC.SchoolRepo.FindSchoolByName(input.Name)
+	if errSchool == nil {
+		return errors.New("school already exist")
+	}
+
+	return schoolUC.SchoolRepo.CreateSchool(input)
+}
+
+// FindAllSchool implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) FindAllSchool() ([]entity.SchoolCore, error) {
+	data, err := schoolUC.SchoolRepo.FindAllSchool()
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindSchoolById implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) FindSchoolById(id string) (entity.SchoolCore, error) {
+	if id == "" {
+		return entity.SchoolCore{}, errors.New("school id can't be empty")
+	}
+
+	data, err := schoolUC.SchoolRepo.FindSchoolById(id)
+	if err != nil {
+		return entity.SchoolCore{}, errors.New("school not found")
+	}
+
+	return data, nil
+}
+
+// UpdateSchool implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) UpdateSchool(id string, data entity.SchoolCore) error {
+	data.Name = strings.TrimSpace(data.Name)
+	errValidate := schoolUC.validateSchool(data)
+	if errValidate != nil {
+		return errValidate
+	}
+
+	existing, errSchool := schoolUC.SchoolRepo.FindSchoolByName(data.Name)
+	if errSchool == nil && existing.Id.String() != id {
+		return errors.New("school already exist")
+	}
+
+	return schoolUC.SchoolRepo.UpdateSchool(id, data)
+}
+
+// DeleteSchool implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) DeleteSchool(id string) error {
+	count, errCount := schoolUC.SchoolRepo.CountSchoolClass(id)
+	if errCount != nil {
+		return errCount
+	}
+
+	if count > 0 {
+		return errors.New("school still has classes")
+	}
+
+	return schoolUC.SchoolRepo.DeleteSchool(id)
+}
+
+// CreateClass implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) CreateClass(input entity.ClassCore) error {
+	input.Name = strings.TrimSpace(input.Name)
+	input.AcademicYear = strings.TrimSpace(input.AcademicYear)
+
+	_, errSchool := schoolUC.SchoolRepo.FindSchoolById(input.SchoolId)
+	if errSchool != nil {
+		return errors.New("school not found")
+	}
+
+	errValidate := schoolUC.validateClass(input)
+	if errValidate != nil {
+		return errValidate
+	}
+
+	_, errClass := schoolUC.SchoolRepo.FindClass(input.SchoolId, input.Name, input.AcademicYear)
+	if errClass == nil {
+		return errors.New("class already exist in this academic year")
+	}
+
+	return schoolUC.SchoolRepo.CreateClass(input)
+}
+
+// FindAllClass implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) FindAllClass(filter entity.ClassFilter) ([]entity.ClassCore, error) {
+	data, err := schoolUC.SchoolRepo.FindAllClass(filter)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindClassById implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) FindClassById(id string) (entity.ClassCore, error) {
+	if id == "" {
+		return entity.ClassCore{}, errors.New("class id can't be empty")
+	}
+
+	data, err := schoolUC.SchoolRepo.FindClassById(id)
+	if err != nil {
+		return entity.ClassCore{}, errors.New("class not found")
+	}
+
+	return data, nil
+}
+
+// UpdateClass implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) UpdateClass(id string, data entity.ClassCore) error {
+	current, errCurrent := schoolUC.SchoolRepo.FindClassById(id)
+	if errCurrent != nil {
+		return errors.New("class not found")
+	}
+
+	data.Name = strings.TrimSpace(data.Name)
+	data.AcademicYear = strings.TrimSpace(data.AcademicYear)
+	errValidate := schoolUC.validateClass(data)
+	if errValidate != nil {
+		return errValidate
+	}
+
+	existing, errClass := schoolUC.SchoolRepo.FindClass(current.SchoolId, data.Name, data.AcademicYear)
+	if errClass == nil && existing.Id.String() != id {
+		return errors.New("class already exist in this academic year")
+	}
+
+	return schoolUC.SchoolRepo.UpdateClass(id, data)
+}
+
+// DeleteClass implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) DeleteClass(id string) error {
+	count, errCount := schoolUC.SchoolRepo.CountClassStudent(id)
+	if errCount != nil {
+		return errCount
+	}
+
+	if count > 0 {
+		return errors.New("class still has students")
+	}
+
+	return schoolUC.SchoolRepo.DeleteClass(id)
+}
+
+// AssignStudent implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) AssignStudent(classId string, userIds []string) error {
+	if len(userIds) == 0 {
+		return errors.New("user ids can't be empty")
+	}
+
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, v := range userIds {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		ids = append(ids, v)
+	}
+
+	return schoolUC.SchoolRepo.AssignStudent(classId, ids)
+}
+
+// ClassRank implements entity.SchoolUseCaseInterface.
+func (schoolUC *SchoolService) ClassRank(filter entity.ClassFilter, orderBy string) ([]entity.ClassRankCore, error) {
+	if filter.SchoolId == "" {
+		return nil, errors.New("school id can't be empty")
+	}
+
+	if orderBy == "" {
+		orderBy = "total"
+	}
+
+	if orderBy != "total" && orderBy != "average" {
+		return nil, errors.New("order must be total or average")
+	}
+
+	data, err := schoolUC.SchoolRepo.ClassRank(filter, orderBy)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	//classes with equal score share a rank
+	for i := range data {
+		data[i].Rank = i + 1
+		if i == 0 {
+			continue
+		}
+
+		prev := data[i-1]
+		tie := prev.TotalPoint == data[i].TotalPoint
+		if orderBy == "average" {
+			tie = prev.AveragePoint == data[i].AveragePoint
+		}
+		if tie {
+			data[i].Rank = prev.Rank
+		}
+	}
+
+	return data, nil
+}
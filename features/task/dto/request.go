@@ -54,4 +54,53 @@ type UserReqReligionTaskRequest struct {
 	Point       int    `json:"point" form:"point"`
 	Status      string `json:"status" form:"status"`
 	Message     string `json:"message"`
-}
\ No newline at end of file
+}
+type TaskSeriesRequest struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Point        int    `json:"point"`
+	Rule         string `json:"rule"`
+	StartDate    string `json:"start_date"`
+	DurationDays int    `json:"duration_days"`
+}
+
+type ReligionTemplateRequest struct {
+	Religion     string `json:"religion"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Point        int    `json:"point"`
+	Rule         string `json:"rule"`
+	StartDate    string `json:"start_date"`
+	DurationDays int    `json:"duration_days"`
+	PrayerWindow string `json:"prayer_window"`
+	Holiday      string `json:"holiday"`
+	Active       *bool  `json:"active"`
+}
+
+type ResubmitRequest struct {
+	Description string `json:"description" form:"description"`
+}
+
+type CommentRequest struct {
+	Body string `json:"body" form:"body"`
+}
+
+type ReviewQueueRequest struct {
+	Type  string `json:"type"`
+	Limit int    `json:"limit"`
+}
+
+type SubmissionRefRequest struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+type ReviewReleaseRequest struct {
+	Items []SubmissionRefRequest `json:"items"`
+}
+
+type ReviewBulkRequest struct {
+	Status  string                 `json:"status"`
+	Message string                 `json:"message"`
+	Items   []SubmissionRefRequest `json:"items"`
+}
@@ -33,6 +33,7 @@ type UserTaskUploadResponse struct {
 	UserId      string `json:"user_id"`
 	UserName    string `json:"user_name"`
 	Image       string `json:"image"`
+	Thumbnail   string `json:"thumbnail"`
 	Description string `json:"description"`
 	Status      string `json:"status"`
 	Message     string `json:"message"`
@@ -44,6 +45,7 @@ type UserReqTaksResponse struct {
 	UserId      string `json:"user_id"`
 	UserName    string `json:"user_name"`
 	Image       string `json:"image"`
+	Thumbnail   string `json:"thumbnail"`
 	Description string `json:"description"`
 	Point       int    `json:"point"`
 	Status      string `json:"status"`
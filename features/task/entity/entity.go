@@ -7,18 +7,41 @@ import (
 )
 
 type TaskCore struct {
-	ID          uuid.UUID `json:"id"`
-	AdminId     string    `json:"admin_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Point       int       `json:"point"`
-	Message     string    `json:"message"`
-	Status      string    `json:"status"`
-	Type        string    `json:"type"`
-	Start_date  string    `json:"start_date"`
-	End_date    string    `json:"end_date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             uuid.UUID `json:"id"`
+	AdminId        string    `json:"admin_id"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Point          int       `json:"point"`
+	Message        string    `json:"message"`
+	Status         string    `json:"status"`
+	Type           string    `json:"type"`
+	Start_date     string    `json:"start_date"`
+	End_date       string    `json:"end_date"`
+	SeriesId       string    `json:"series_id"`
+	OccurrenceDate string    `json:"occurrence_date"`
+	Detached       bool      `json:"detached"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TaskSeriesHorizonDays is how far ahead occurrences are materialized.
+const TaskSeriesHorizonDays = 14
+
+// TaskSeriesCore is a recurring task, Rule is an RRULE such as
+// "FREQ=WEEKLY;BYDAY=MO". Each occurrence stays open for DurationDays.
+type TaskSeriesCore struct {
+	Id             uuid.UUID `json:"id"`
+	AdminId        string    `json:"admin_id"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Point          int       `json:"point"`
+	Rule           string    `json:"rule"`
+	StartDate      string    `json:"start_date"`
+	DurationDays   int       `json:"duration_days"`
+	GeneratedUntil string    `json:"generated_until"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type UserTaskUploadCore struct {
@@ -28,12 +51,28 @@ type UserTaskUploadCore struct {
 	UserId      string    `json:"user_id"`
 	UserName    string    `json:"user_name"`
 	Image       string    `json:"image"`
+	Thumbnail   string    `json:"thumbnail"`
 	Description string    `json:"description"`
 	Status      string    `json:"status"`
 	Type        string    `json:"type"`
 	Message     string    `json:"message"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// the earlier upload with the same image, see imageproc.Match
+	Duplicate *ImageMatch `json:"duplicate,omitempty"`
+	// the comment thread, only set by the detail endpoints
+	Thread *CommentThreadCore `json:"thread,omitempty"`
+}
+
+// ImageMatch is an earlier task or religion upload whose image is the
+// same or nearly the same as the image of an upload. Distance is 0 for
+// the same file or a copy that looks the same, Link is the admin
+// endpoint of the earlier upload.
+type ImageMatch struct {
+	Id       string `json:"id"`
+	Type     string `json:"type"`
+	Distance int    `json:"distance"`
+	Link     string `json:"link"`
 }
 
 type UserTaskSubmissionCore struct {
@@ -42,6 +81,7 @@ type UserTaskSubmissionCore struct {
 	UserId      string    `json:"user_id"`
 	UserName    string    `json:"user_name"`
 	Image       string    `json:"image"`
+	Thumbnail   string    `json:"thumbnail"`
 	Type        string    `json:"type"`
 	Description string    `json:"description"`
 	Point       int       `json:"point"`
@@ -49,19 +89,52 @@ type UserTaskSubmissionCore struct {
 	Message     string    `json:"message"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// the comment thread, only set by the detail endpoints
+	Thread *CommentThreadCore `json:"thread,omitempty"`
 }
 
 type ReligionTaskCore struct {
-	Id          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Religion    string    `json:"religion"`
-	Point       int       `json:"point"`
-	Type        string    `json:"type"`
-	Start_date  string    `json:"start_date"`
-	End_date    string    `json:"end_date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id             uuid.UUID  `json:"id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Religion       string     `json:"religion"`
+	Point          int        `json:"point"`
+	Type           string     `json:"type"`
+	Start_date     string     `json:"start_date"`
+	End_date       string     `json:"end_date"`
+	TemplateId     string     `json:"template_id"`
+	OccurrenceDate string     `json:"occurrence_date"`
+	StartAt        *time.Time `json:"start_at"`
+	EndAt          *time.Time `json:"end_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ReligionTaskHorizonDays is how far ahead religion tasks are generated
+// from their templates.
+const ReligionTaskHorizonDays = 7
+
+// ReligionTemplateCore is an observance generated for every student of a
+// religion, Rule is an RRULE such as "FREQ=DAILY", "FREQ=WEEKLY;BYDAY=SU"
+// or "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25". Generated tasks close
+// DurationDays after their day, 0 closes them the same day. PrayerWindow
+// is one of the prayertime prayers and limits uploads to the time of that
+// prayer instead. With Holiday, the key of a calendar holiday, only days
+// of that holiday are generated.
+type ReligionTemplateCore struct {
+	Id           uuid.UUID `json:"id"`
+	Religion     string    `json:"religion"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Point        int       `json:"point"`
+	Rule         string    `json:"rule"`
+	StartDate    string    `json:"start_date"`
+	DurationDays int       `json:"duration_days"`
+	PrayerWindow string    `json:"prayer_window"`
+	Holiday      string    `json:"holiday"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 type UserReligionTaskUploadCore struct {
@@ -71,12 +144,17 @@ type UserReligionTaskUploadCore struct {
 	UserId      string    `json:"user_id"`
 	UserName    string    `json:"username"`
 	Image       string    `json:"image"`
+	Thumbnail   string    `json:"thumbnail"`
 	Type        string    `json:"type"`
 	Description string    `json:"description"`
 	Status      string    `json:"status"`
 	Message     string    `json:"message"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// the earlier upload with the same image, see imageproc.Match
+	Duplicate *ImageMatch `json:"duplicate,omitempty"`
+	// the comment thread, only set by the detail endpoints
+	Thread *CommentThreadCore `json:"thread,omitempty"`
 }
 
 type UserReligionReqTaskCore struct {
@@ -85,6 +163,7 @@ type UserReligionReqTaskCore struct {
 	UserId      string    `json:"user_id"`
 	UserName    string    `json:"user_name"`
 	Image       string    `json:"image"`
+	Thumbnail   string    `json:"thumbnail"`
 	Type        string    `json:"type"`
 	Description string    `json:"description"`
 	Point       int       `json:"point"`
@@ -92,4 +171,136 @@ type UserReligionReqTaskCore struct {
 	Message     string    `json:"message"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
-}
\ No newline at end of file
+	// the comment thread, only set by the detail endpoints
+	Thread *CommentThreadCore `json:"thread,omitempty"`
+}
+
+// Kinds of submission reviewed with the workflow of package review, they
+// are the Type of the submissions and of their revisions.
+const (
+	KindTask            = "Task"
+	KindSubmission      = "Submission"
+	KindReligion        = "Religion"
+	KindReligionRequest = "Religion Request"
+)
+
+// SubmissionKinds lists every kind of submission.
+var SubmissionKinds = []string{KindTask, KindSubmission, KindReligion, KindReligionRequest}
+
+// SubmissionCore is what the review workflow needs of any kind of
+// submission. Title and Point are those of the task for uploads.
+type SubmissionCore struct {
+	Id          string `json:"id"`
+	Type        string `json:"type"`
+	UserId      string `json:"user_id"`
+	TaskId      string `json:"task_id"`
+	Title       string `json:"title"`
+	Point       int    `json:"point"`
+	Image       string `json:"image"`
+	Thumbnail   string `json:"thumbnail"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+}
+
+// ReviewCore is the decision of a reviewer on a submission. Point, when
+// set, replaces the point asked for by a task submission or religion
+// request.
+type ReviewCore struct {
+	ReviewerId string
+	Status     string
+	Message    string
+	Point      int
+}
+
+// RevisionCore is an entry of the review history of a submission. UserId
+// is the student who sent it or the reviewer who reviewed it.
+type RevisionCore struct {
+	Id           uuid.UUID `json:"id"`
+	SubmissionId string    `json:"submission_id"`
+	Type         string    `json:"type"`
+	UserId       string    `json:"user_id"`
+	FromStatus   string    `json:"from_status"`
+	Status       string    `json:"status"`
+	Message      string    `json:"message"`
+	Image        string    `json:"image"`
+	Thumbnail    string    `json:"thumbnail"`
+	Description  string    `json:"description"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ReviewClaimLease is how long a claimed submission is hidden from the
+// review queue of other reviewers.
+const ReviewClaimLease = 15 * time.Minute
+
+// SubmissionRef identifies a submission of any kind.
+type SubmissionRef struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+// ReviewQueueFilter selects the submissions of the review queue, Type is
+// empty for every kind.
+type ReviewQueueFilter struct {
+	Type       string
+	ReviewerId string
+	Limit      int
+}
+
+// ReviewQueueCore is a submission waiting for review, ClaimedBy is the
+// reviewer it is handed to until ClaimExpiredAt.
+type ReviewQueueCore struct {
+	SubmissionCore
+	UserName       string      `json:"user_name"`
+	Message        string      `json:"message"`
+	Duplicate      *ImageMatch `json:"duplicate,omitempty"`
+	ClaimedBy      string      `json:"claimed_by"`
+	ClaimExpiredAt *time.Time  `json:"claim_expired_at"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// ReviewClaimCore is the reviewer a submission is handed to.
+type ReviewClaimCore struct {
+	SubmissionId string    `json:"submission_id"`
+	Type         string    `json:"type"`
+	ReviewerId   string    `json:"reviewer_id"`
+	ExpiredAt    time.Time `json:"expired_at"`
+}
+
+// ReviewResultCore is the outcome of the review of one submission of a
+// bulk review, Error is empty when it succeeded.
+type ReviewResultCore struct {
+	Type  string `json:"type"`
+	Id    string `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+// MaxCommentLength is the longest comment body in characters.
+const MaxCommentLength = 2000
+
+// CommentCore is a comment of the thread of a submission, posted by its
+// student or by a reviewer.
+type CommentCore struct {
+	Id           uuid.UUID `json:"id"`
+	SubmissionId string    `json:"submission_id"`
+	Type         string    `json:"type"`
+	UserId       string    `json:"user_id"`
+	UserName     string    `json:"user_name"`
+	Body         string    `json:"body"`
+	Image        string    `json:"image"`
+	Thumbnail    string    `json:"thumbnail"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CommentReadCore is when a user last read a thread.
+type CommentReadCore struct {
+	UserId string    `json:"user_id"`
+	ReadAt time.Time `json:"read_at"`
+}
+
+// CommentThreadCore is the thread of a submission, oldest comment first.
+// Unread counts the comments of others the user reading it hasn't read.
+type CommentThreadCore struct {
+	Comments []CommentCore     `json:"comments"`
+	Reads    []CommentReadCore `json:"reads"`
+	Unread   int               `json:"unread"`
+}
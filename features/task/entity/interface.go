@@ -2,6 +2,7 @@ package entity
 
 import (
 	"mime/multipart"
+	"time"
 )
 
 type TaskDataInterface interface {
@@ -10,15 +11,23 @@ type TaskDataInterface interface {
 	FindById(taskId string) (TaskCore, error)
 	UpdateTask(taskId string, data TaskCore) error
 	DeleteTask(taskId string) error
+	CancelTask(taskId string) error
+
+	CreateTaskSeries(input TaskSeriesCore) (TaskSeriesCore, error)
+	FindAllTaskSeries() ([]TaskSeriesCore, error)
+	FindActiveTaskSeries() ([]TaskSeriesCore, error)
+	FindTaskSeriesById(id string) (TaskSeriesCore, error)
+	FindTaskSeriesOccurrences(id string) ([]TaskCore, error)
+	UpdateTaskSeries(id string, data TaskSeriesCore, from string) error
+	CancelTaskSeries(id string, from string) error
+	GenerateTaskSeries(id string, dates []string, until string) (int, error)
 
-	UpdateTaskStatus(taskId string, data UserTaskUploadCore) error
-	UpdateTaskReqStatus(id string, data UserTaskSubmissionCore) error
 	FindUserTaskById(id string) (UserTaskUploadCore, error)
 	FindUserTaskReqById(id string) (UserTaskSubmissionCore, error)
 	FindAllUserTask() ([]UserTaskUploadCore, error)
 
-	UploadTask(input UserTaskUploadCore, image *multipart.FileHeader) error
-	UploadTaskRequest(input UserTaskSubmissionCore, image *multipart.FileHeader) error
+	UploadTask(input UserTaskUploadCore, image *multipart.FileHeader) (string, error)
+	UploadTaskRequest(input UserTaskSubmissionCore, image *multipart.FileHeader) (string, error)
 	FindAllRequestTask() ([]UserTaskSubmissionCore, error)
 	FindAllClaimedTask(userId string) ([]UserTaskUploadCore, error)
 	FindAllRequestTaskHistory(userId string) ([]UserTaskSubmissionCore, error)
@@ -31,23 +40,40 @@ type TaskDataInterface interface {
 	FindByIdReligionTask(taskId string) (ReligionTaskCore, error)
 	UpdateTaskReligion(taskId string, data ReligionTaskCore) error
 	DeleteTaskReligion(taskId string) error
-	FindTaskByDateAndReligion(date string, religion string) ([]ReligionTaskCore, error)
-	FindTaskByDateAndReligionNon(date string, religion string) ([]ReligionTaskCore, error)
 
-	UploadTaskReligion(input UserReligionTaskUploadCore, image *multipart.FileHeader) error
+	CreateReligionTemplate(input ReligionTemplateCore) (ReligionTemplateCore, error)
+	FindAllReligionTemplate(religion string) ([]ReligionTemplateCore, error)
+	FindActiveReligionTemplate(religion string) ([]ReligionTemplateCore, error)
+	FindReligionTemplateById(id string) (ReligionTemplateCore, error)
+	UpdateReligionTemplate(id string, data ReligionTemplateCore) error
+	DeleteReligionTemplate(id string) error
+	GenerateReligionTasks(templateId string, tasks []ReligionTaskCore) (int, error)
+
+	UploadTaskReligion(input UserReligionTaskUploadCore, image *multipart.FileHeader) (string, error)
 	FindAllReligionTaskUser(religion string, userId string) ([]ReligionTaskCore, error)
 	FindAllReligionTaskHistory(userId string) ([]UserReligionTaskUploadCore, error)
 
 	FindAllUserReligionTaskUpload() ([]UserReligionTaskUploadCore, error)
 	FindSpecificUserReligionTaskUpload(userId string) (UserReligionTaskUploadCore, error)
-	UpdateReligionTaskStatus(id string, data UserReligionTaskUploadCore) error
 
-	UploadReligionTaskRequest(input UserReligionReqTaskCore, image *multipart.FileHeader) error
+	UploadReligionTaskRequest(input UserReligionReqTaskCore, image *multipart.FileHeader) (string, error)
 	FindAllReligionTaskRequestHistory(userId string) ([]UserReligionReqTaskCore, error)
 	FindSpesificReligionTaskRequest(id string) (UserReligionReqTaskCore, error)
 
 	GetAllUserReligionTaskRequest()([]UserReligionReqTaskCore, error)
-	UpdateTaskReligionReqStatus(id string, data UserReligionReqTaskCore) error
+
+	FindSubmission(kind string, id string) (SubmissionCore, error)
+	ReviewSubmission(submission SubmissionCore, data ReviewCore) error
+	ResubmitSubmission(submission SubmissionCore, description string, image *multipart.FileHeader) error
+	FindRevisions(kind string, id string) ([]RevisionCore, error)
+
+	FindReviewQueue(filter ReviewQueueFilter) ([]ReviewQueueCore, error)
+	ClaimSubmission(kind string, id string, reviewerId string, until time.Time) (bool, error)
+	ReleaseReviewClaims(reviewerId string, items []SubmissionRef) error
+
+	FindComments(kind string, id string) ([]CommentCore, []CommentReadCore, error)
+	CreateComment(input CommentCore, image *multipart.FileHeader) (CommentCore, error)
+	MarkCommentsRead(kind string, id string, userId string, at time.Time) error
 }
 
 type TaskUseCaseInterface interface {
@@ -57,8 +83,13 @@ type TaskUseCaseInterface interface {
 	UpdateTask(taskId string, data TaskCore) error
 	DeleteTask(taskId string) error
 
-	UpdateTaskStatus(taskId string, data UserTaskUploadCore) error
-	UpdateTaskReqStatus(id string, data UserTaskSubmissionCore) error
+	CreateTaskSeries(input TaskSeriesCore) (TaskSeriesCore, error)
+	FindAllTaskSeries() ([]TaskSeriesCore, error)
+	FindTaskSeriesById(id string) (TaskSeriesCore, []TaskCore, error)
+	UpdateTaskSeries(id string, data TaskSeriesCore) error
+	CancelTaskSeries(id string) error
+	GenerateTaskSeries(until time.Time) (int, error)
+
 	FindUserTaskById(id string) (UserTaskUploadCore, error)
 	FindUserTaskReqById(id string) (UserTaskSubmissionCore, error)
 	FindAllUserTask() ([]UserTaskUploadCore, error)
@@ -78,18 +109,36 @@ type TaskUseCaseInterface interface {
 	UpdateTaskReligion(taskId string, data ReligionTaskCore) error
 	DeleteTaskReligion(taskId string) error
 
+	CreateReligionTemplate(input ReligionTemplateCore) (ReligionTemplateCore, error)
+	FindAllReligionTemplate(religion string) ([]ReligionTemplateCore, error)
+	FindReligionTemplateById(id string) (ReligionTemplateCore, error)
+	UpdateReligionTemplate(id string, data ReligionTemplateCore) error
+	DeleteReligionTemplate(id string) error
+	GenerateReligionTasks(religion string, from time.Time, until time.Time) (int, error)
+
 	UploadTaskReligion(input UserReligionTaskUploadCore, image *multipart.FileHeader) error
 	FindAllReligionTaskUser(religion string, userId string) ([]ReligionTaskCore, error)
 	FindAllReligionTaskHistory(userId string) ([]UserReligionTaskUploadCore, error)
 
 	FindAllUserReligionTaskUpload() ([]UserReligionTaskUploadCore, error)
 	FindSpecificUserReligionTaskUpload(id string) (UserReligionTaskUploadCore, error)
-	UpdateReligionTaskStatus(id string, data UserReligionTaskUploadCore) error
 
 	UploadReligionTaskRequest(input UserReligionReqTaskCore, image *multipart.FileHeader) error
 	FindAllReligionTaskRequestHistory(userId string) ([]UserReligionReqTaskCore, error)
 	FindSpesificReligionTaskRequest(id string) (UserReligionReqTaskCore, error)
 
 	GetAllUserReligionTaskRequest()([]UserReligionReqTaskCore, error)
-	UpdateTaskReligionReqStatus(id string, data UserReligionReqTaskCore) error
+
+	ReviewSubmission(kind string, id string, data ReviewCore) error
+	ResubmitSubmission(kind string, id string, userId string, description string, image *multipart.FileHeader) error
+	FindSubmissionRevisions(kind string, id string) (SubmissionCore, []RevisionCore, error)
+
+	FindReviewQueue(filter ReviewQueueFilter) ([]ReviewQueueCore, error)
+	ClaimReviewQueue(filter ReviewQueueFilter) ([]ReviewQueueCore, error)
+	ReleaseReviewClaims(reviewerId string, items []SubmissionRef) error
+	BulkReviewSubmission(items []SubmissionRef, data ReviewCore) ([]ReviewResultCore, error)
+
+	FindSubmissionComments(kind string, id string, userId string, reviewer bool) (CommentThreadCore, error)
+	AddSubmissionComment(kind string, id string, input CommentCore, reviewer bool, image *multipart.FileHeader) (CommentCore, error)
+	ReadSubmissionComments(kind string, id string, userId string, reviewer bool) error
 }
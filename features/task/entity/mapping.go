@@ -6,36 +6,42 @@ import (
 
 func TaskCoreToTaskModel(data TaskCore) model.Task {
 	return model.Task{
-		ID:          data.ID,
-		AdminId:     data.AdminId,
-		Title:       data.Title,
-		Description: data.Description,
-		Point:       data.Point,
-		Message:     data.Message,
-		Status:      data.Status,
-		Type:        data.Type,
-		Start_date:  data.Start_date,
-		End_date:    data.End_date,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
+		ID:             data.ID,
+		AdminId:        data.AdminId,
+		Title:          data.Title,
+		Description:    data.Description,
+		Point:          data.Point,
+		Message:        data.Message,
+		Status:         data.Status,
+		Type:           data.Type,
+		Start_date:     data.Start_date,
+		End_date:       data.End_date,
+		SeriesId:       optionalId(data.SeriesId),
+		OccurrenceDate: data.OccurrenceDate,
+		Detached:       data.Detached,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
 	}
 
 }
 
 func TaskModelToTaskCore(data model.Task) TaskCore {
 	return TaskCore{
-		ID:          data.ID,
-		AdminId:     data.AdminId,
-		Title:       data.Title,
-		Description: data.Description,
-		Point:       data.Point,
-		Message:     data.Message,
-		Status:      data.Status,
-		Type:        data.Type,
-		Start_date:  data.Start_date,
-		End_date:    data.End_date,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
+		ID:             data.ID,
+		AdminId:        data.AdminId,
+		Title:          data.Title,
+		Description:    data.Description,
+		Point:          data.Point,
+		Message:        data.Message,
+		Status:         data.Status,
+		Type:           data.Type,
+		Start_date:     data.Start_date,
+		End_date:       data.End_date,
+		SeriesId:       optionalIdValue(data.SeriesId),
+		OccurrenceDate: data.OccurrenceDate,
+		Detached:       data.Detached,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
 	}
 
 }
@@ -49,12 +55,23 @@ func ListTaskModelToTaskCore(data []model.Task) []TaskCore {
 	return dataTask
 }
 
+// NewImageMatch returns the match stored on an upload, nil when id is
+// empty.
+func NewImageMatch(id string, kind string, distance int) *ImageMatch {
+	if id == "" {
+		return nil
+	}
+	return &ImageMatch{Id: id, Type: kind, Distance: distance}
+}
+
 func TaskUserModelToTaskUserCore(data model.UserTaskUpload) UserTaskUploadCore {
 	return UserTaskUploadCore{
 		Id:          data.Id,
 		TaskId:      data.TaskId,
 		UserId:      data.UserId,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
+		Duplicate:   NewImageMatch(data.DuplicateOf, data.DuplicateType, data.DuplicateDistance),
 		Description: data.Description,
 		Status:      data.Status,
 		Message:     data.Message,
@@ -70,6 +87,7 @@ func TaskUserCoreToTaskUserModel(data UserTaskUploadCore) model.UserTaskUpload {
 		TaskId:      data.TaskId,
 		UserId:      data.UserId,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Message:     data.Message,
@@ -94,6 +112,7 @@ func TaskUserReqModelToTaskUserReqCore(data model.UserTaskSubmission) UserTaskSu
 		Point:       data.Point,
 		UserId:      data.UserId,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Message:     data.Message,
@@ -110,6 +129,7 @@ func TaskUserReqCoreToTaskUserReqModel(data UserTaskSubmissionCore) model.UserTa
 		Point:       data.Point,
 		UserId:      data.UserId,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Message:     data.Message,
@@ -130,32 +150,40 @@ func ListTaskUserReqModelToTaskUserReqCore(data []model.UserTaskSubmission) []Us
 
 func ReligionTaskCoreToTaskModel(data ReligionTaskCore) model.ReligionTask {
 	return model.ReligionTask{
-		Id:          data.Id,
-		Title:       data.Title,
-		Description: data.Description,
-		Type:        data.Type,
-		Religion:    data.Religion,
-		Point:       data.Point,
-		Start_date:  data.Start_date,
-		End_date:    data.End_date,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.CreatedAt,
+		Id:             data.Id,
+		Title:          data.Title,
+		Description:    data.Description,
+		Type:           data.Type,
+		Religion:       data.Religion,
+		Point:          data.Point,
+		Start_date:     data.Start_date,
+		End_date:       data.End_date,
+		TemplateId:     optionalId(data.TemplateId),
+		OccurrenceDate: data.OccurrenceDate,
+		StartAt:        data.StartAt,
+		EndAt:          data.EndAt,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.CreatedAt,
 	}
 
 }
 
 func ReligionTaskModelToTaskCore(data model.ReligionTask) ReligionTaskCore {
 	return ReligionTaskCore{
-		Id:          data.Id,
-		Title:       data.Title,
-		Description: data.Description,
-		Type:        data.Type,
-		Start_date:  data.Start_date,
-		End_date:    data.End_date,
-		Point:       data.Point,
-		Religion:    data.Religion,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
+		Id:             data.Id,
+		Title:          data.Title,
+		Description:    data.Description,
+		Type:           data.Type,
+		Start_date:     data.Start_date,
+		End_date:       data.End_date,
+		Point:          data.Point,
+		Religion:       data.Religion,
+		TemplateId:     optionalIdValue(data.TemplateId),
+		OccurrenceDate: data.OccurrenceDate,
+		StartAt:        data.StartAt,
+		EndAt:          data.EndAt,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
 	}
 
 }
@@ -175,6 +203,7 @@ func ReligionTaskUploadCoreToReligionTaskUploadModel(data UserReligionTaskUpload
 		TaskId:      data.TaskId,
 		UserId:      data.UserId,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Type:        data.Type,
 		Description: data.Description,
 		Status:      data.Status,
@@ -190,6 +219,8 @@ func ReligionTaskUploadModelToReligionTaskUploadCore(data model.UserReligionTask
 		TaskId:      data.TaskId,
 		UserId:      data.UserId,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
+		Duplicate:   NewImageMatch(data.DuplicateOf, data.DuplicateType, data.DuplicateDistance),
 		Type:        data.Type,
 		Description: data.Description,
 		Status:      data.Status,
@@ -206,6 +237,7 @@ func ReligionTaskReqModelToReligionTaskReqCore(data model.UserReligionReqTask) U
 		Point:       data.Point,
 		UserId:      data.UserId,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Message:     data.Message,
@@ -222,6 +254,7 @@ func ReligionTaskReqCoreToReligioinTaskReqModel(data UserReligionReqTaskCore) mo
 		Point:       data.Point,
 		UserId:      data.UserId,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Message:     data.Message,
@@ -239,3 +272,168 @@ func ListReligionReqTaskModelToReligionReqTaskCore(data []model.UserReligionReqT
 	}
 	return dataTask
 }
+
+// optionalId stores an empty id as NULL so unique indexes ignore it.
+func optionalId(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
+
+func optionalIdValue(id *string) string {
+	if id == nil {
+		return ""
+	}
+	return *id
+}
+
+func TaskSeriesCoreToTaskSeriesModel(data TaskSeriesCore) model.TaskSeries {
+	return model.TaskSeries{
+		Id:             data.Id,
+		AdminId:        data.AdminId,
+		Title:          data.Title,
+		Description:    data.Description,
+		Point:          data.Point,
+		Rule:           data.Rule,
+		StartDate:      data.StartDate,
+		DurationDays:   data.DurationDays,
+		GeneratedUntil: data.GeneratedUntil,
+		Status:         data.Status,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
+	}
+}
+
+func TaskSeriesModelToTaskSeriesCore(data model.TaskSeries) TaskSeriesCore {
+	return TaskSeriesCore{
+		Id:             data.Id,
+		AdminId:        data.AdminId,
+		Title:          data.Title,
+		Description:    data.Description,
+		Point:          data.Point,
+		Rule:           data.Rule,
+		StartDate:      data.StartDate,
+		DurationDays:   data.DurationDays,
+		GeneratedUntil: data.GeneratedUntil,
+		Status:         data.Status,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
+	}
+}
+
+func ReligionTemplateCoreToReligionTemplateModel(data ReligionTemplateCore) model.ReligionTaskTemplate {
+	return model.ReligionTaskTemplate{
+		Id:           data.Id,
+		Religion:     data.Religion,
+		Title:        data.Title,
+		Description:  data.Description,
+		Point:        data.Point,
+		Rule:         data.Rule,
+		StartDate:    data.StartDate,
+		DurationDays: data.DurationDays,
+		PrayerWindow: data.PrayerWindow,
+		Holiday:      data.Holiday,
+		Active:       data.Active,
+		CreatedAt:    data.CreatedAt,
+		UpdatedAt:    data.UpdatedAt,
+	}
+}
+
+func ReligionTemplateModelToReligionTemplateCore(data model.ReligionTaskTemplate) ReligionTemplateCore {
+	return ReligionTemplateCore{
+		Id:           data.Id,
+		Religion:     data.Religion,
+		Title:        data.Title,
+		Description:  data.Description,
+		Point:        data.Point,
+		Rule:         data.Rule,
+		StartDate:    data.StartDate,
+		DurationDays: data.DurationDays,
+		PrayerWindow: data.PrayerWindow,
+		Holiday:      data.Holiday,
+		Active:       data.Active,
+		CreatedAt:    data.CreatedAt,
+		UpdatedAt:    data.UpdatedAt,
+	}
+}
+
+func ListReligionTemplateModelToReligionTemplateCore(data []model.ReligionTaskTemplate) []ReligionTemplateCore {
+	dataTemplate := []ReligionTemplateCore{}
+	for _, v := range data {
+		dataTemplate = append(dataTemplate, ReligionTemplateModelToReligionTemplateCore(v))
+	}
+	return dataTemplate
+}
+
+func RevisionCoreToRevisionModel(data RevisionCore) model.SubmissionRevision {
+	return model.SubmissionRevision{
+		Id:           data.Id,
+		SubmissionId: data.SubmissionId,
+		Type:         data.Type,
+		UserId:       data.UserId,
+		FromStatus:   data.FromStatus,
+		Status:       data.Status,
+		Message:      data.Message,
+		Image:        data.Image,
+		Thumbnail:    data.Thumbnail,
+		Description:  data.Description,
+		CreatedAt:    data.CreatedAt,
+	}
+}
+
+func ListRevisionModelToRevisionCore(data []model.SubmissionRevision) []RevisionCore {
+	dataRevision := []RevisionCore{}
+	for _, v := range data {
+		dataRevision = append(dataRevision, RevisionCore{
+			Id:           v.Id,
+			SubmissionId: v.SubmissionId,
+			Type:         v.Type,
+			UserId:       v.UserId,
+			FromStatus:   v.FromStatus,
+			Status:       v.Status,
+			Message:      v.Message,
+			Image:        v.Image,
+			Thumbnail:    v.Thumbnail,
+			Description:  v.Description,
+			CreatedAt:    v.CreatedAt,
+		})
+	}
+	return dataRevision
+}
+
+func CommentCoreToCommentModel(data CommentCore) model.SubmissionComment {
+	return model.SubmissionComment{
+		Id:           data.Id,
+		SubmissionId: data.SubmissionId,
+		Type:         data.Type,
+		UserId:       data.UserId,
+		Body:         data.Body,
+		Image:        data.Image,
+		Thumbnail:    data.Thumbnail,
+	}
+}
+
+func CommentModelToCommentCore(data model.SubmissionComment) CommentCore {
+	return CommentCore{
+		Id:           data.Id,
+		SubmissionId: data.SubmissionId,
+		Type:         data.Type,
+		UserId:       data.UserId,
+		Body:         data.Body,
+		Image:        data.Image,
+		Thumbnail:    data.Thumbnail,
+		CreatedAt:    data.CreatedAt,
+	}
+}
+
+func ListCommentReadModelToCommentReadCore(data []model.SubmissionCommentRead) []CommentReadCore {
+	dataRead := []CommentReadCore{}
+	for _, v := range data {
+		dataRead = append(dataRead, CommentReadCore{
+			UserId: v.UserId,
+			ReadAt: v.ReadAt,
+		})
+	}
+	return dataRead
+}
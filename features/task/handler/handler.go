@@ -2,9 +2,11 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 	"tugaskita/features/task/dto"
 	"tugaskita/features/task/entity"
 	user "tugaskita/features/user/entity"
+	"tugaskita/utils/authz"
 	middleware "tugaskita/utils/jwt"
 
 	"github.com/google/uuid"
@@ -24,19 +26,13 @@ func New(taskUC entity.TaskUseCaseInterface, userUC user.UserUseCaseInterface) *
 }
 
 func (handler *TaskController) AddTask(e echo.Context) error {
-	userId, role, _, err := middleware.ExtractTokenUserId(e)
+	userId, _, _, err := middleware.ExtractTokenUserId(e)
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
 			"message": err.Error(),
 		})
 	}
 
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	input := new(dto.TaskRequest)
 	errBind := e.Bind(&input)
 	if errBind != nil {
@@ -68,14 +64,14 @@ func (handler *TaskController) AddTask(e echo.Context) error {
 }
 
 func (handler *TaskController) ReadAllTask(e echo.Context) error {
-	userId, role, _, err := middleware.ExtractTokenUserId(e)
+	userId, _, _, err := middleware.ExtractTokenUserId(e)
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
 			"message": err.Error(),
 		})
 	}
 
-	if role == "admin" {
+	if authz.Can(e, authz.TaskManage) {
 		data, err := handler.taskUsecase.FindAllTask()
 		if err != nil {
 			return e.JSON(http.StatusBadRequest, map[string]any{
@@ -102,38 +98,33 @@ func (handler *TaskController) ReadAllTask(e echo.Context) error {
 			"message": "get all admin task",
 			"data":    dataList,
 		})
+	}
 
-	} else if role == "user" {
-		data, err := handler.taskUsecase.FindTasksNotClaimedByUser(userId)
-		if err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]any{
-				"message": "error get all task",
-			})
-		}
+	data, err := handler.taskUsecase.FindTasksNotClaimedByUser(userId)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all task",
+		})
+	}
 
-		dataList := []dto.TaskResponse{}
-		for _, v := range data {
-			result := dto.TaskResponse{
-				Id:          v.ID.String(),
-				Title:       v.Title,
-				Point:       v.Point,
-				Status:      v.Status,
-				Type:        v.Type,
-				Start_date:  v.Start_date,
-				End_date:    v.End_date,
-				Description: v.Description,
-			}
-			dataList = append(dataList, result)
+	dataList := []dto.TaskResponse{}
+	for _, v := range data {
+		result := dto.TaskResponse{
+			Id:          v.ID.String(),
+			Title:       v.Title,
+			Point:       v.Point,
+			Status:      v.Status,
+			Type:        v.Type,
+			Start_date:  v.Start_date,
+			End_date:    v.End_date,
+			Description: v.Description,
 		}
-
-		return e.JSON(http.StatusOK, map[string]any{
-			"message": "get all user task",
-			"data":    dataList,
-		})
+		dataList = append(dataList, result)
 	}
 
-	return e.JSON(http.StatusBadRequest, map[string]any{
-		"message": "access denied",
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all user task",
+		"data":    dataList,
 	})
 }
 
@@ -175,19 +166,6 @@ func (handler *TaskController) ReadSpecificTask(e echo.Context) error {
 }
 
 func (handler *TaskController) DeleteTask(e echo.Context) error {
-	_, role, _, errRole := middleware.ExtractTokenUserId(e)
-	if errRole != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": errRole.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 	err := handler.taskUsecase.DeleteTask(idParams)
 	if err != nil {
@@ -203,19 +181,13 @@ func (handler *TaskController) DeleteTask(e echo.Context) error {
 }
 
 func (handler *TaskController) UpdateTask(e echo.Context) error {
-	adminId, role, _, err := middleware.ExtractTokenUserId(e)
+	adminId, _, _, err := middleware.ExtractTokenUserId(e)
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
 			"message": err.Error(),
 		})
 	}
 
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := new(dto.TaskRequest)
@@ -249,19 +221,6 @@ func (handler *TaskController) UpdateTask(e echo.Context) error {
 }
 
 func (handler *TaskController) UpdateTaskStatus(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := dto.UserTaskUploadRequest{}
@@ -271,19 +230,20 @@ func (handler *TaskController) UpdateTaskStatus(e echo.Context) error {
 		})
 	}
 
-	//get userId & taskId
-	dataTask, _ := handler.taskUsecase.FindUserTaskById(idParams)
+	adminId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": errToken.Error(),
+		})
+	}
 
-	status := entity.UserTaskUploadCore{
-		TaskId:      dataTask.TaskId,
-		UserId:      dataTask.UserId,
-		Image:       data.Image,
-		Description: data.Description,
-		Status:      data.Status,
-		Message:     data.Message,
+	status := entity.ReviewCore{
+		ReviewerId: adminId,
+		Status:     data.Status,
+		Message:    data.Message,
 	}
 
-	errUpdate := handler.taskUsecase.UpdateTaskStatus(idParams, status)
+	errUpdate := handler.taskUsecase.ReviewSubmission(entity.KindTask, idParams, status)
 	if errUpdate != nil {
 		return e.JSON(http.StatusBadRequest, map[string]interface{}{
 			"message": "Error updating task status",
@@ -297,19 +257,6 @@ func (handler *TaskController) UpdateTaskStatus(e echo.Context) error {
 }
 
 func (handler *TaskController) UpdateTaskReqStatus(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := dto.UserReqTaskRequest{}
@@ -319,21 +266,21 @@ func (handler *TaskController) UpdateTaskReqStatus(e echo.Context) error {
 		})
 	}
 
-	//get userId & taskId
-	dataTask, _ := handler.taskUsecase.FindUserTaskReqById(idParams)
+	adminId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": errToken.Error(),
+		})
+	}
 
-	status := entity.UserTaskSubmissionCore{
-		UserId:      dataTask.UserId,
-		UserName:    dataTask.UserName,
-		Title:       data.Title,
-		Image:       data.Image,
-		Description: data.Description,
-		Point:       data.Point,
-		Status:      data.Status,
-		Message:     data.Message,
+	status := entity.ReviewCore{
+		ReviewerId: adminId,
+		Status:     data.Status,
+		Message:    data.Message,
+		Point:      data.Point,
 	}
 
-	errUpdate := handler.taskUsecase.UpdateTaskReqStatus(idParams, status)
+	errUpdate := handler.taskUsecase.ReviewSubmission(entity.KindSubmission, idParams, status)
 	if errUpdate != nil {
 		return e.JSON(http.StatusBadRequest, map[string]interface{}{
 			"message": "Error updating request task status",
@@ -374,6 +321,7 @@ func (handler *TaskController) ReadHistoryTaskUser(e echo.Context) error {
 			UserId:      v.UserId,
 			UserName:    userData.Name,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Status:      v.Status,
 			Type:        taskData.Type,
@@ -438,20 +386,22 @@ func (handler *TaskController) UploadTaskUser(e echo.Context) error {
 	})
 }
 
-func (handler *TaskController) FindAllUserTask(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
+// uploadLinks are the admin endpoints of the uploads by type.
+var uploadLinks = map[string]string{
+	"Task":     "/admin-task/user/",
+	"Religion": "/admin-task/religion/user/",
+}
 
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
+func linkImageMatch(match *entity.ImageMatch) *entity.ImageMatch {
+	if match == nil {
+		return nil
 	}
+	linked := *match
+	linked.Link = uploadLinks[match.Type] + match.Id
+	return &linked
+}
 
+func (handler *TaskController) FindAllUserTask(e echo.Context) error {
 	data, err := handler.taskUsecase.FindAllUserTask()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
@@ -472,6 +422,8 @@ func (handler *TaskController) FindAllUserTask(e echo.Context) error {
 			UserId:      v.UserId,
 			UserName:    userData.Name,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
+			Duplicate:   linkImageMatch(v.Duplicate),
 			Description: v.Description,
 			Status:      v.Status,
 			Type:        v.Type,
@@ -498,6 +450,19 @@ func (handler *TaskController) FindUserTaskById(e echo.Context) error {
 		})
 	}
 
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	if data.UserId != userId && !authz.Can(e, authz.TaskReview) {
+		return e.JSON(http.StatusForbidden, map[string]any{
+			"message": "access denied",
+		})
+	}
+
 	userData, _ := handler.userUsecase.ReadSpecificUser(data.UserId)
 	taskData, _ := handler.taskUsecase.FindById(data.TaskId)
 
@@ -508,12 +473,14 @@ func (handler *TaskController) FindUserTaskById(e echo.Context) error {
 		TaskId:      data.TaskId,
 		TaskName:    taskData.Title,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Type:        data.Type,
 		Message:     data.Message,
 		CreatedAt:   data.CreatedAt,
 		UpdatedAt:   data.UpdatedAt,
+		Thread:      handler.commentThread(e, entity.KindTask, idParams, userId),
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
@@ -532,6 +499,19 @@ func (handler *TaskController) FindUserTaskReqyId(e echo.Context) error {
 		})
 	}
 
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	if data.UserId != userId && !authz.Can(e, authz.TaskReview) {
+		return e.JSON(http.StatusForbidden, map[string]any{
+			"message": "access denied",
+		})
+	}
+
 	userData, _ := handler.userUsecase.ReadSpecificUser(data.UserId)
 
 	response := entity.UserTaskSubmissionCore{
@@ -541,12 +521,14 @@ func (handler *TaskController) FindUserTaskReqyId(e echo.Context) error {
 		UserName:    userData.Name,
 		Point:       data.Point,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Type:        data.Type,
 		Message:     data.Message,
 		CreatedAt:   data.CreatedAt,
 		UpdatedAt:   data.UpdatedAt,
+		Thread:      handler.commentThread(e, entity.KindSubmission, idParams, userId),
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
@@ -605,19 +587,6 @@ func (handler *TaskController) UploadRequestTaskUser(e echo.Context) error {
 }
 
 func (handler *TaskController) FindAllUserRequestTask(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	data, err := handler.taskUsecase.FindAllRequestTask()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
@@ -637,6 +606,7 @@ func (handler *TaskController) FindAllUserRequestTask(e echo.Context) error {
 			UserId:      v.UserId,
 			UserName:    userData.Name,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Status:      v.Status,
 			Type:        v.Type,
@@ -676,6 +646,7 @@ func (handler *TaskController) FindAllRequestTaskHistory(e echo.Context) error {
 			Point:       v.Point,
 			UserId:      v.UserId,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Message:     v.Message,
 			Status:      v.Status,
@@ -714,19 +685,6 @@ func (handler *TaskController) CountUserClearTask(e echo.Context) error {
 }
 
 func (handler *TaskController) AddReligionTask(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	input := new(dto.ReligionTaskRequest)
 	errBind := e.Bind(&input)
 	if errBind != nil {
@@ -758,71 +716,31 @@ func (handler *TaskController) AddReligionTask(e echo.Context) error {
 }
 
 func (handler *TaskController) ReadAllReligionTask(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
+	data, err := handler.taskUsecase.FindAllTaskReligion()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
+			"message": "error get all task",
 		})
 	}
 
-	if role == "admin" {
-		data, err := handler.taskUsecase.FindAllTaskReligion()
-		if err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]any{
-				"message": "error get all task",
-			})
-		}
-
-		dataList := []entity.ReligionTaskCore{}
-		for _, v := range data {
-			result := entity.ReligionTaskCore{
-				Id:          v.Id,
-				Title:       v.Title,
-				Description: v.Description,
-				Type:        v.Type,
-				Start_date:  v.Start_date,
-				End_date:    v.End_date,
-				Point:       v.Point,
-				Religion:    v.Religion,
-			}
-			dataList = append(dataList, result)
+	dataList := []entity.ReligionTaskCore{}
+	for _, v := range data {
+		result := entity.ReligionTaskCore{
+			Id:          v.Id,
+			Title:       v.Title,
+			Description: v.Description,
+			Type:        v.Type,
+			Start_date:  v.Start_date,
+			End_date:    v.End_date,
+			Point:       v.Point,
+			Religion:    v.Religion,
 		}
-
-		return e.JSON(http.StatusOK, map[string]any{
-			"message": "get all admin task",
-			"data":    dataList,
-		})
-
-	} else if role == "user" {
-		// data, err := handler.taskUsecase.FindTasksNotClaimedByUser(userId)
-		// if err != nil {
-		// 	return e.JSON(http.StatusBadRequest, map[string]any{
-		// 		"message": "error get all task",
-		// 	})
-		// }
-
-		// dataList := []dto.TaskResponse{}
-		// for _, v := range data {
-		// 	result := dto.TaskResponse{
-		// 		Id:         v.ID.String(),
-		// 		Title:      v.Title,
-		// 		Point:      v.Point,
-		// 		Status:     v.Status,
-		// 		Type:       v.Type,
-		// 		Start_date: v.Start_date,
-		// 		End_date:   v.End_date,
-		// 	}
-		// 	dataList = append(dataList, result)
-		// }
-
-		return e.JSON(http.StatusOK, map[string]any{
-			"message": "get all user task",
-			// "data":    dataList,
-		})
+		dataList = append(dataList, result)
 	}
 
-	return e.JSON(http.StatusBadRequest, map[string]any{
-		"message": "access denied",
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all admin task",
+		"data":    dataList,
 	})
 }
 
@@ -864,19 +782,6 @@ func (handler *TaskController) ReadSpecificReligionTask(e echo.Context) error {
 }
 
 func (handler *TaskController) DeleteReligionTask(e echo.Context) error {
-	_, role, _, errRole := middleware.ExtractTokenUserId(e)
-	if errRole != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": errRole.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 	err := handler.taskUsecase.DeleteTaskReligion(idParams)
 	if err != nil {
@@ -892,19 +797,6 @@ func (handler *TaskController) DeleteReligionTask(e echo.Context) error {
 }
 
 func (handler *TaskController) UpdateReligionTask(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := new(dto.ReligionTaskRequest)
@@ -998,6 +890,7 @@ func (handler *TaskController) ReligionTaskHistoryUser(e echo.Context) error {
 			TaskId:      v.TaskId,
 			TaskName:    taskData.Title,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Status:      v.Status,
 			Message:     v.Message,
@@ -1062,19 +955,6 @@ func (handler *TaskController) UploadTaskReligionUser(e echo.Context) error {
 }
 
 func (handler *TaskController) FindAllUserReligionTask(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	data, err := handler.taskUsecase.FindAllUserReligionTaskUpload()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
@@ -1095,6 +975,8 @@ func (handler *TaskController) FindAllUserReligionTask(e echo.Context) error {
 			UserId:      v.UserId,
 			UserName:    userData.Name,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
+			Duplicate:   linkImageMatch(v.Duplicate),
 			Description: v.Description,
 			Status:      v.Status,
 			Type:        v.Type,
@@ -1121,6 +1003,13 @@ func (handler *TaskController) FindSpecificUserReligionTask(e echo.Context) erro
 		})
 	}
 
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
 	userData, _ := handler.userUsecase.ReadSpecificUser(data.UserId)
 	taskData, _ := handler.taskUsecase.FindByIdReligionTask(data.TaskId)
 
@@ -1131,12 +1020,14 @@ func (handler *TaskController) FindSpecificUserReligionTask(e echo.Context) erro
 		TaskId:      data.TaskId,
 		TaskName:    taskData.Title,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Type:        data.Type,
 		Message:     data.Message,
 		CreatedAt:   data.CreatedAt,
 		UpdatedAt:   data.UpdatedAt,
+		Thread:      handler.commentThread(e, entity.KindReligion, idParams, userId),
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
@@ -1146,19 +1037,6 @@ func (handler *TaskController) FindSpecificUserReligionTask(e echo.Context) erro
 }
 
 func (handler *TaskController) UpdateReligionTaskStatus(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := dto.ReligionTaskUploadRequest{}
@@ -1168,21 +1046,20 @@ func (handler *TaskController) UpdateReligionTaskStatus(e echo.Context) error {
 		})
 	}
 
-	//get userId & taskId
-	dataTask, _ := handler.taskUsecase.FindSpecificUserReligionTaskUpload(idParams)
+	adminId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": errToken.Error(),
+		})
+	}
 
-	status := entity.UserReligionTaskUploadCore{
-		Id:          dataTask.Id,
-		UserId:      dataTask.UserId,
-		TaskId:      data.TaskId,
-		TaskName:    dataTask.TaskName,
-		Image:       data.Image,
-		Description: data.Description,
-		Status:      data.Status,
-		Message:     data.Message,
+	status := entity.ReviewCore{
+		ReviewerId: adminId,
+		Status:     data.Status,
+		Message:    data.Message,
 	}
 
-	errUpdate := handler.taskUsecase.UpdateReligionTaskStatus(dataTask.TaskId, status)
+	errUpdate := handler.taskUsecase.ReviewSubmission(entity.KindReligion, idParams, status)
 	if errUpdate != nil {
 		return e.JSON(http.StatusBadRequest, map[string]interface{}{
 			"message": "Error updating religion task status",
@@ -1223,6 +1100,7 @@ func (handler *TaskController) FindAllReligionTaskRequestHistory(e echo.Context)
 			Type:        v.Type,
 			Point:       v.Point,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Status:      v.Status,
 			Message:     v.Message,
@@ -1248,6 +1126,19 @@ func (handler *TaskController) FindSpesificReligionTaskRequest(e echo.Context) e
 		})
 	}
 
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	if data.UserId != userId && !authz.Can(e, authz.TaskReview) {
+		return e.JSON(http.StatusForbidden, map[string]any{
+			"message": "access denied",
+		})
+	}
+
 	userData, _ := handler.userUsecase.ReadSpecificUser(data.UserId)
 
 	response := entity.UserReligionReqTaskCore{
@@ -1257,12 +1148,14 @@ func (handler *TaskController) FindSpesificReligionTaskRequest(e echo.Context) e
 		Title:       data.Title,
 		Point:       data.Point,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		Type:        data.Type,
 		Message:     data.Message,
 		CreatedAt:   data.CreatedAt,
 		UpdatedAt:   data.UpdatedAt,
+		Thread:      handler.commentThread(e, entity.KindReligionRequest, idParams, userId),
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
@@ -1321,23 +1214,10 @@ func (handler *TaskController) UploadReligionTaskRequest(e echo.Context) error {
 }
 
 func (handler *TaskController) GetAllUserReligionTaskRequest(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
+	data, err := handler.taskUsecase.GetAllUserReligionTaskRequest()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
-	data, err := handler.taskUsecase.GetAllUserReligionTaskRequest()
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "error get all user religion request task",
+			"message": "error get all user religion request task",
 		})
 	}
 
@@ -1353,6 +1233,7 @@ func (handler *TaskController) GetAllUserReligionTaskRequest(e echo.Context) err
 			UserId:      v.UserId,
 			UserName:    userData.Name,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Status:      v.Status,
 			Type:        v.Type,
@@ -1370,19 +1251,6 @@ func (handler *TaskController) GetAllUserReligionTaskRequest(e echo.Context) err
 }
 
 func (handler *TaskController) UpdateTaskReligionReqStatus(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	idParams := e.Param("id")
 
 	data := dto.UserReqReligionTaskRequest{}
@@ -1392,21 +1260,21 @@ func (handler *TaskController) UpdateTaskReligionReqStatus(e echo.Context) error
 		})
 	}
 
-	//get userId & taskId
-	dataTask, _ := handler.taskUsecase.FindSpesificReligionTaskRequest(idParams)
+	adminId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": errToken.Error(),
+		})
+	}
 
-	status := entity.UserReligionReqTaskCore{
-		UserId:      dataTask.UserId,
-		UserName:    dataTask.UserName,
-		Title:       data.Title,
-		Image:       data.Image,
-		Description: data.Description,
-		Point:       data.Point,
-		Status:      data.Status,
-		Message:     data.Message,
+	status := entity.ReviewCore{
+		ReviewerId: adminId,
+		Status:     data.Status,
+		Message:    data.Message,
+		Point:      data.Point,
 	}
 
-	errUpdate := handler.taskUsecase.UpdateTaskReligionReqStatus(idParams, status)
+	errUpdate := handler.taskUsecase.ReviewSubmission(entity.KindReligionRequest, idParams, status)
 	if errUpdate != nil {
 		return e.JSON(http.StatusBadRequest, map[string]interface{}{
 			"message": "Error updating religion request task status",
@@ -1418,3 +1286,551 @@ func (handler *TaskController) UpdateTaskReligionReqStatus(e echo.Context) error
 		"message": "religion task request status updated",
 	})
 }
+
+func (handler *TaskController) AddTaskSeries(e echo.Context) error {
+	userId, _, _, err := middleware.ExtractTokenUserId(e)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": err.Error(),
+		})
+	}
+
+	input := dto.TaskSeriesRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.TaskSeriesCore{
+		AdminId:      userId,
+		Title:        input.Title,
+		Description:  input.Description,
+		Point:        input.Point,
+		Rule:         input.Rule,
+		StartDate:    input.StartDate,
+		DurationDays: input.DurationDays,
+	}
+
+	series, errSeries := handler.taskUsecase.CreateTaskSeries(data)
+	if errSeries != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error create task series",
+			"error":   errSeries.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "succes create task series",
+		"data":    series,
+	})
+}
+
+func (handler *TaskController) ReadAllTaskSeries(e echo.Context) error {
+	data, err := handler.taskUsecase.FindAllTaskSeries()
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all task series",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all task series",
+		"data":    data,
+	})
+}
+
+func (handler *TaskController) ReadSpecificTaskSeries(e echo.Context) error {
+	series, occurrences, err := handler.taskUsecase.FindTaskSeriesById(e.Param("id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get task series",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get task series",
+		"data": map[string]any{
+			"series":      series,
+			"occurrences": occurrences,
+		},
+	})
+}
+
+func (handler *TaskController) UpdateTaskSeries(e echo.Context) error {
+	input := dto.TaskSeriesRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.TaskSeriesCore{
+		Title:        input.Title,
+		Description:  input.Description,
+		Point:        input.Point,
+		Rule:         input.Rule,
+		StartDate:    input.StartDate,
+		DurationDays: input.DurationDays,
+	}
+
+	err := handler.taskUsecase.UpdateTaskSeries(e.Param("id"), data)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error update task series",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success update task series",
+	})
+}
+
+func (handler *TaskController) CancelTaskSeries(e echo.Context) error {
+	err := handler.taskUsecase.CancelTaskSeries(e.Param("id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error cancel task series",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success cancel task series",
+	})
+}
+
+func religionTemplateCore(input dto.ReligionTemplateRequest) entity.ReligionTemplateCore {
+	active := true
+	if input.Active != nil {
+		active = *input.Active
+	}
+
+	return entity.ReligionTemplateCore{
+		Religion:     input.Religion,
+		Title:        input.Title,
+		Description:  input.Description,
+		Point:        input.Point,
+		Rule:         input.Rule,
+		StartDate:    input.StartDate,
+		DurationDays: input.DurationDays,
+		PrayerWindow: input.PrayerWindow,
+		Holiday:      input.Holiday,
+		Active:       active,
+	}
+}
+
+func (handler *TaskController) AddReligionTemplate(e echo.Context) error {
+	input := dto.ReligionTemplateRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	template, err := handler.taskUsecase.CreateReligionTemplate(religionTemplateCore(input))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error create religion template",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "succes create religion template",
+		"data":    template,
+	})
+}
+
+func (handler *TaskController) ReadAllReligionTemplate(e echo.Context) error {
+	data, err := handler.taskUsecase.FindAllReligionTemplate(e.QueryParam("religion"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all religion template",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all religion template",
+		"data":    data,
+	})
+}
+
+func (handler *TaskController) ReadSpecificReligionTemplate(e echo.Context) error {
+	data, err := handler.taskUsecase.FindReligionTemplateById(e.Param("id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get religion template",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get religion template",
+		"data":    data,
+	})
+}
+
+func (handler *TaskController) UpdateReligionTemplate(e echo.Context) error {
+	input := dto.ReligionTemplateRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	err := handler.taskUsecase.UpdateReligionTemplate(e.Param("id"), religionTemplateCore(input))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error update religion template",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success update religion template",
+	})
+}
+
+func (handler *TaskController) DeleteReligionTemplate(e echo.Context) error {
+	err := handler.taskUsecase.DeleteReligionTemplate(e.Param("id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error delete religion template",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success delete religion template",
+	})
+}
+
+// ResubmitSubmission returns the handler a student sends a submission of
+// kind again with after a reviewer asked for a revision. The image is
+// optional, the one sent before is kept without it.
+func (handler *TaskController) ResubmitSubmission(kind string) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		data := dto.ResubmitRequest{}
+		if errBind := e.Bind(&data); errBind != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "error bind data",
+			})
+		}
+
+		image, err := e.FormFile("image")
+		if err != nil && err != http.ErrMissingFile && err != http.ErrNotMultipart {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "Error uploading file",
+			})
+		}
+
+		userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+		if errToken != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": errToken.Error(),
+			})
+		}
+
+		errResubmit := handler.taskUsecase.ResubmitSubmission(kind, e.Param("id"), userId, data.Description, image)
+		if errResubmit != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "error send submission again",
+				"error":   errResubmit.Error(),
+			})
+		}
+
+		return e.JSON(http.StatusOK, map[string]any{
+			"message": "success send submission again",
+		})
+	}
+}
+
+// FindSubmissionRevisions returns the handler listing the review history
+// of a submission of kind, for its student and for reviewers.
+func (handler *TaskController) FindSubmissionRevisions(kind string) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		submission, revisions, err := handler.taskUsecase.FindSubmissionRevisions(kind, e.Param("id"))
+		if err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "error get submission revisions",
+				"error":   err.Error(),
+			})
+		}
+
+		userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+		if errToken != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": errToken.Error(),
+			})
+		}
+
+		if submission.UserId != userId && !authz.Can(e, authz.TaskReview) {
+			return e.JSON(http.StatusForbidden, map[string]any{
+				"message": "access denied",
+			})
+		}
+
+		return e.JSON(http.StatusOK, map[string]any{
+			"message": "get submission revisions",
+			"data":    revisions,
+		})
+	}
+}
+
+// commentThread returns the comment thread of a submission for a detail
+// response, nil when it can't be read.
+func (handler *TaskController) commentThread(e echo.Context, kind string, id string, userId string) *entity.CommentThreadCore {
+	thread, err := handler.taskUsecase.FindSubmissionComments(kind, id, userId, authz.Can(e, authz.TaskReview))
+	if err != nil {
+		return nil
+	}
+	return &thread
+}
+
+// FindSubmissionComments returns the handler listing the comment thread
+// of a submission of kind, for its student and for reviewers.
+func (handler *TaskController) FindSubmissionComments(kind string) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+		if errToken != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": errToken.Error(),
+			})
+		}
+
+		thread, err := handler.taskUsecase.FindSubmissionComments(kind, e.Param("id"), userId, authz.Can(e, authz.TaskReview))
+		if err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "error get comments",
+				"error":   err.Error(),
+			})
+		}
+
+		return e.JSON(http.StatusOK, map[string]any{
+			"message": "get comments",
+			"data":    thread,
+		})
+	}
+}
+
+// AddSubmissionComment returns the handler posting a comment, with an
+// optional image, on the thread of a submission of kind.
+func (handler *TaskController) AddSubmissionComment(kind string) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		data := dto.CommentRequest{}
+		if errBind := e.Bind(&data); errBind != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "error bind data",
+			})
+		}
+
+		image, err := e.FormFile("image")
+		if err != nil && err != http.ErrMissingFile && err != http.ErrNotMultipart {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "Error uploading file",
+			})
+		}
+
+		userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+		if errToken != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": errToken.Error(),
+			})
+		}
+
+		input := entity.CommentCore{
+			UserId: userId,
+			Body:   data.Body,
+		}
+		comment, errComment := handler.taskUsecase.AddSubmissionComment(kind, e.Param("id"), input, authz.Can(e, authz.TaskReview), image)
+		if errComment != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "error add comment",
+				"error":   errComment.Error(),
+			})
+		}
+
+		return e.JSON(http.StatusCreated, map[string]any{
+			"message": "success add comment",
+			"data":    comment,
+		})
+	}
+}
+
+// ReadSubmissionComments returns the handler marking the comment thread
+// of a submission of kind as read by the user.
+func (handler *TaskController) ReadSubmissionComments(kind string) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+		if errToken != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": errToken.Error(),
+			})
+		}
+
+		err := handler.taskUsecase.ReadSubmissionComments(kind, e.Param("id"), userId, authz.Can(e, authz.TaskReview))
+		if err != nil {
+			return e.JSON(http.StatusBadRequest, map[string]any{
+				"message": "error read comments",
+				"error":   err.Error(),
+			})
+		}
+
+		return e.JSON(http.StatusOK, map[string]any{
+			"message": "success read comments",
+		})
+	}
+}
+
+func submissionRefs(items []dto.SubmissionRefRequest) []entity.SubmissionRef {
+	refs := []entity.SubmissionRef{}
+	for _, v := range items {
+		refs = append(refs, entity.SubmissionRef{Type: v.Type, Id: v.Id})
+	}
+	return refs
+}
+
+func linkReviewQueue(queue []entity.ReviewQueueCore) []entity.ReviewQueueCore {
+	for i := range queue {
+		queue[i].Duplicate = linkImageMatch(queue[i].Duplicate)
+	}
+	return queue
+}
+
+func (handler *TaskController) ReadReviewQueue(e echo.Context) error {
+	reviewerId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	limit, _ := strconv.Atoi(e.QueryParam("limit"))
+
+	data, err := handler.taskUsecase.FindReviewQueue(entity.ReviewQueueFilter{
+		Type:       e.QueryParam("type"),
+		ReviewerId: reviewerId,
+		Limit:      limit,
+	})
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get review queue",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get review queue",
+		"data":    linkReviewQueue(data),
+	})
+}
+
+func (handler *TaskController) ClaimReviewQueue(e echo.Context) error {
+	input := dto.ReviewQueueRequest{}
+	if errBind := e.Bind(&input); errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	reviewerId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	data, err := handler.taskUsecase.ClaimReviewQueue(entity.ReviewQueueFilter{
+		Type:       input.Type,
+		ReviewerId: reviewerId,
+		Limit:      input.Limit,
+	})
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error claim review queue",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success claim review queue",
+		"data":    linkReviewQueue(data),
+	})
+}
+
+func (handler *TaskController) ReleaseReviewClaims(e echo.Context) error {
+	input := dto.ReviewReleaseRequest{}
+	if errBind := e.Bind(&input); errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	reviewerId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	err := handler.taskUsecase.ReleaseReviewClaims(reviewerId, submissionRefs(input.Items))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error release review claims",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success release review claims",
+	})
+}
+
+func (handler *TaskController) BulkReviewSubmission(e echo.Context) error {
+	input := dto.ReviewBulkRequest{}
+	if errBind := e.Bind(&input); errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	reviewerId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	data, err := handler.taskUsecase.BulkReviewSubmission(submissionRefs(input.Items), entity.ReviewCore{
+		ReviewerId: reviewerId,
+		Status:     input.Status,
+		Message:    input.Message,
+	})
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bulk review",
+			"error":   err.Error(),
+		})
+	}
+
+	failed := 0
+	for _, v := range data {
+		if v.Error != "" {
+			failed++
+		}
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message":   "bulk review done",
+		"succeeded": len(data) - failed,
+		"failed":    failed,
+		"data":      data,
+	})
+}
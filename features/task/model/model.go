@@ -15,23 +15,53 @@ type Task struct {
 	Message     string
 	Status      string `gorm:"type:varchar(20);default:'Active'" json:"status"`
 	Type        string `gorm:"default:'Task'" json:"type"`
-	Start_date  string
-	End_date    string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Start_date  string `gorm:"type:varchar(10)"`
+	End_date    string `gorm:"type:varchar(10)"`
+	// occurrences of a TaskSeries, Detached ones were edited on their own
+	// and are left alone by series edits
+	SeriesId       *string `gorm:"type:varchar(50);uniqueIndex:idx_task_series_occurrence"`
+	OccurrenceDate string  `gorm:"type:varchar(10);uniqueIndex:idx_task_series_occurrence"`
+	Detached       bool    `gorm:"not null;default:false"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TaskSeries is a recurring task template. Its occurrences are
+// materialized ahead of time as Task rows up to GeneratedUntil.
+type TaskSeries struct {
+	Id             uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	AdminId        string
+	Title          string `gorm:"not null"`
+	Description    string
+	Point          int
+	Rule           string `gorm:"type:varchar(255);not null"`
+	StartDate      string `gorm:"type:varchar(10);not null"`
+	DurationDays   int    `gorm:"not null;default:1"`
+	GeneratedUntil string `gorm:"type:varchar(10)"`
+	Status         string `gorm:"type:varchar(20);default:'Active'"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 type UserTaskUpload struct {
 	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
-	TaskId      string
-	UserId      string
+	TaskId      string    `gorm:"type:varchar(50);index"`
+	UserId      string    `gorm:"type:varchar(50);index"`
 	Image       string
+	Thumbnail   string
 	Description string
 	Status      string `gorm:"type:varchar(20);default:'Perlu Review'" json:"status"`
 	Type        string `gorm:"default:'Task'" json:"type"`
 	Message     string
-	CreatedAt   time.Time
+	CreatedAt   time.Time `gorm:"index"`
 	UpdatedAt   time.Time
+	// fingerprints of the image, see imageproc.Match, and the earlier
+	// upload it matches
+	ImageHash         string `gorm:"type:varchar(64);index"`
+	ImageDhash        string `gorm:"type:varchar(16)"`
+	DuplicateOf       string `gorm:"type:varchar(50)"`
+	DuplicateType     string `gorm:"type:varchar(20)"`
+	DuplicateDistance int    `gorm:"not null;default:0"`
 }
 
 type UserTaskSubmission struct {
@@ -39,6 +69,7 @@ type UserTaskSubmission struct {
 	Title       string
 	UserId      string
 	Image       string
+	Thumbnail   string
 	Type        string `gorm:"type:varchar(20);default:'Submission'" json:"type"`
 	Description string
 	Point       int
@@ -55,23 +86,55 @@ type ReligionTask struct {
 	Religion    string
 	Type        string `gorm:"type:varchar(20);default:'Religion'" json:"type"`
 	Point       int
-	Start_date  string
-	End_date    string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Start_date  string `gorm:"type:varchar(10)"`
+	End_date    string `gorm:"type:varchar(10)"`
+	// set on tasks generated from a ReligionTaskTemplate, one per day
+	TemplateId     *string `gorm:"type:varchar(50);uniqueIndex:idx_religion_task_template_occurrence"`
+	OccurrenceDate string  `gorm:"type:varchar(10);uniqueIndex:idx_religion_task_template_occurrence"`
+	// uploads are only accepted within [StartAt, EndAt] when set
+	StartAt   *time.Time
+	EndAt     *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ReligionTaskTemplate describes a recurring observance of a religion. The
+// generator creates a ReligionTask for every day matched by Rule.
+type ReligionTaskTemplate struct {
+	Id           uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	Religion     string    `gorm:"type:varchar(25);not null;index"`
+	Title        string    `gorm:"not null"`
+	Description  string
+	Point        int
+	Rule         string `gorm:"type:varchar(255);not null"`
+	StartDate    string `gorm:"type:varchar(10);not null"`
+	DurationDays int    `gorm:"not null;default:0"`
+	PrayerWindow string `gorm:"type:varchar(10)"`
+	Holiday      string `gorm:"type:varchar(50);not null;default:''"`
+	Active       bool   `gorm:"not null;default:true"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 type UserReligionTaskUpload struct {
 	Id          uuid.UUID `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
-	TaskId      string
-	UserId      string
+	TaskId      string    `gorm:"type:varchar(50);index"`
+	UserId      string    `gorm:"type:varchar(50);index"`
 	Image       string
+	Thumbnail   string
 	Type        string `gorm:"type:varchar(20);default:'Religion'" json:"type"`
 	Description string
 	Status      string `gorm:"type:varchar(20);default:'Perlu Review'" json:"status"`
 	Message     string
-	CreatedAt   time.Time
+	CreatedAt   time.Time `gorm:"index"`
 	UpdatedAt   time.Time
+	// fingerprints of the image, see imageproc.Match, and the earlier
+	// upload it matches
+	ImageHash         string `gorm:"type:varchar(64);index"`
+	ImageDhash        string `gorm:"type:varchar(16)"`
+	DuplicateOf       string `gorm:"type:varchar(50)"`
+	DuplicateType     string `gorm:"type:varchar(20)"`
+	DuplicateDistance int    `gorm:"not null;default:0"`
 }
 
 type UserReligionReqTask struct {
@@ -79,6 +142,7 @@ type UserReligionReqTask struct {
 	Title       string
 	UserId      string
 	Image       string
+	Thumbnail   string
 	Type        string `gorm:"type:varchar(20);default:'Religion Request'" json:"type"`
 	Description string
 	Point       int
@@ -86,4 +150,53 @@ type UserReligionReqTask struct {
 	Message     string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
-}
\ No newline at end of file
+}
+
+// SubmissionRevision is an entry of the review history of a submission of
+// any Type, written when it is uploaded, reviewed and sent again. Image and
+// Description are what the student sent, Message is the reviewer's note.
+type SubmissionRevision struct {
+	Id           uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	SubmissionId string    `gorm:"type:varchar(50);not null;index"`
+	Type         string    `gorm:"type:varchar(20);not null"`
+	UserId       string    `gorm:"type:varchar(50)"`
+	FromStatus   string    `gorm:"type:varchar(20)"`
+	Status       string    `gorm:"type:varchar(20);not null"`
+	Message      string
+	Image        string
+	Thumbnail    string
+	Description  string
+	CreatedAt    time.Time
+}
+
+// ReviewClaim hands a waiting submission to a reviewer until ExpiredAt,
+// the review queue doesn't give it to other reviewers meanwhile.
+type ReviewClaim struct {
+	SubmissionId string    `gorm:"type:varchar(50);primaryKey;not null"`
+	Type         string    `gorm:"type:varchar(20);primaryKey;not null"`
+	ReviewerId   string    `gorm:"type:varchar(50);not null;index"`
+	ExpiredAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+// SubmissionComment is a comment of the thread of a submission of any
+// Type, posted by its student or by a reviewer.
+type SubmissionComment struct {
+	Id           uuid.UUID `gorm:"type:varchar(50);primaryKey;not null"`
+	SubmissionId string    `gorm:"type:varchar(50);not null;index"`
+	Type         string    `gorm:"type:varchar(20);not null"`
+	UserId       string    `gorm:"type:varchar(50);not null"`
+	Body         string    `gorm:"type:text"`
+	Image        string
+	Thumbnail    string
+	CreatedAt    time.Time
+}
+
+// SubmissionCommentRead is when a user last read the thread of a
+// submission.
+type SubmissionCommentRead struct {
+	SubmissionId string    `gorm:"type:varchar(50);primaryKey;not null"`
+	Type         string    `gorm:"type:varchar(20);primaryKey;not null"`
+	UserId       string    `gorm:"type:varchar(50);primaryKey;not null"`
+	ReadAt       time.Time `gorm:"not null"`
+}
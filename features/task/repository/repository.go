@@ -1,32 +1,35 @@
 package repository
 
 import (
+	"context"
 	"errors"
-	"io"
+	"fmt"
 	"mime/multipart"
-	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
+	"sort"
 	"time"
 	"tugaskita/features/task/entity"
 	"tugaskita/features/task/model"
 	user "tugaskita/features/user/entity"
-	userModel "tugaskita/features/user/model"
+	"tugaskita/utils/imageproc"
+	"tugaskita/utils/review"
+	"tugaskita/utils/storage"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository struct {
 	db             *gorm.DB
 	userRepository user.UserDataInterface
+	storage        storage.Storage
 }
 
-func NewTaskRepository(db *gorm.DB, userRepository user.UserDataInterface) entity.TaskDataInterface {
+func NewTaskRepository(db *gorm.DB, userRepository user.UserDataInterface, store storage.Storage) entity.TaskDataInterface {
 	return &TaskRepository{
 		db:             db,
 		userRepository: userRepository,
+		storage:        store,
 	}
 }
 
@@ -107,119 +110,271 @@ func (taskRepo *TaskRepository) UpdateTask(taskId string, data entity.TaskCore)
 	return nil
 }
 
-// UpdateTaskStatus implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) UpdateTaskStatus(taskId string, data entity.UserTaskUploadCore) error {
-	var pointTask model.Task
-	var userData userModel.Users
-	taskData := entity.TaskUserCoreToTaskUserModel(data)
+// CancelTask implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) CancelTask(taskId string) error {
+	tx := taskRepo.db.Model(&model.Task{}).Where("id = ?", taskId).Updates(map[string]any{
+		"status":   "Cancelled",
+		"detached": true,
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
 
-	// get task data
-	errData := taskRepo.db.Where("id=?", data.TaskId).First(&pointTask).Error
-	if errData != nil {
-		return errData
+	if tx.RowsAffected == 0 {
+		return errors.New("task not found")
 	}
 
-	// get user
-	errUser := taskRepo.db.Where("id=?", data.UserId).First(&userData).Error
-	if errUser != nil {
-		return errUser
+	return nil
+}
+
+// CreateTaskSeries implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) CreateTaskSeries(input entity.TaskSeriesCore) (entity.TaskSeriesCore, error) {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return entity.TaskSeriesCore{}, UUIDerr
 	}
 
-	// update status
-	tx := taskRepo.db.Where("id=?", taskId).Updates(taskData)
+	data := entity.TaskSeriesCoreToTaskSeriesModel(input)
+	data.Id = newUUID
+	data.Status = "Active"
+	tx := taskRepo.db.Create(&data)
 	if tx.Error != nil {
-		return tx.Error
+		return entity.TaskSeriesCore{}, tx.Error
 	}
+	return entity.TaskSeriesModelToTaskSeriesCore(data), nil
+}
 
-	if taskData.Status == "Diterima" {
-		//update user
-		userPoint, _ := strconv.Atoi(userData.Point)
-		userTotalPoint, _ := strconv.Atoi(userData.TotalPoint)
+// FindAllTaskSeries implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindAllTaskSeries() ([]entity.TaskSeriesCore, error) {
+	var series []model.TaskSeries
 
-		count := userPoint + pointTask.Point
-		countTotal := userTotalPoint + pointTask.Point
+	errData := taskRepo.db.Order("created_at desc").Find(&series).Error
+	if errData != nil {
+		return nil, errData
+	}
 
-		userData.Point = strconv.Itoa(count)
-		userData.TotalPoint = strconv.Itoa(countTotal)
+	dataSeries := []entity.TaskSeriesCore{}
+	for _, v := range series {
+		dataSeries = append(dataSeries, entity.TaskSeriesModelToTaskSeriesCore(v))
+	}
+	return dataSeries, nil
+}
 
-		saveUser := user.UserModelToUserCore(userData)
+// FindActiveTaskSeries implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindActiveTaskSeries() ([]entity.TaskSeriesCore, error) {
+	var series []model.TaskSeries
 
-		updateUser := taskRepo.userRepository.UpdatePoint(data.UserId, saveUser)
-		if updateUser != nil {
-			return updateUser
-		}
+	errData := taskRepo.db.Where("status = ?", "Active").Find(&series).Error
+	if errData != nil {
+		return nil, errData
+	}
 
-		//update history
-		historyData := user.UserPointCore{
-			UserId:   data.UserId,
-			Type:     "Task",
-			Point:    pointTask.Point,
-			TaskName: pointTask.Title,
-		}
-		errUserHistory := taskRepo.userRepository.PostUserPointHistory(historyData)
-		if errUserHistory != nil {
-			return errors.New("failed add user history point")
-		}
+	dataSeries := []entity.TaskSeriesCore{}
+	for _, v := range series {
+		dataSeries = append(dataSeries, entity.TaskSeriesModelToTaskSeriesCore(v))
 	}
+	return dataSeries, nil
+}
 
-	return nil
+// FindTaskSeriesById implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindTaskSeriesById(id string) (entity.TaskSeriesCore, error) {
+	series := model.TaskSeries{}
+
+	tx := taskRepo.db.Where("id = ?", id).First(&series)
+	if tx.Error != nil {
+		return entity.TaskSeriesCore{}, tx.Error
+	}
+
+	return entity.TaskSeriesModelToTaskSeriesCore(series), nil
 }
 
-// UpdateTaskReqStatus implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) UpdateTaskReqStatus(id string, data entity.UserTaskSubmissionCore) error {
-	var pointTask model.UserTaskSubmission
-	var userData userModel.Users
-	taskData := entity.TaskUserReqCoreToTaskUserReqModel(data)
+// FindTaskSeriesOccurrences implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindTaskSeriesOccurrences(id string) ([]entity.TaskCore, error) {
+	var tasks []model.Task
 
-	// get user
-	errUser := taskRepo.db.Where("id=?", data.UserId).First(&userData).Error
-	if errUser != nil {
-		return errUser
+	errData := taskRepo.db.Where("series_id = ?", id).Order("occurrence_date").Find(&tasks).Error
+	if errData != nil {
+		return nil, errData
 	}
 
-	// update status
-	tx := taskRepo.db.Where("id=?", id).Updates(taskData)
-	if tx.Error != nil {
-		return tx.Error
+	return entity.ListTaskModelToTaskCore(tasks), nil
+}
+
+// futureOccurrences returns the occurrences of a series on or after from
+// that still follow the series, together with whether they have uploads.
+func futureOccurrences(tx *gorm.DB, id string, from string, detached bool) ([]model.Task, map[string]bool, error) {
+	var tasks []model.Task
+
+	query := tx.Where("series_id = ? AND occurrence_date >= ? AND status = ?", id, from, "Active")
+	if !detached {
+		query = query.Where("detached = ?", false)
+	}
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, nil, err
 	}
 
-	// get task data
-	errData := taskRepo.db.Where("id=?", id).First(&pointTask).Error
-	if errData != nil {
-		return errData
+	uploaded := map[string]bool{}
+	if len(tasks) == 0 {
+		return tasks, uploaded, nil
+	}
+
+	ids := []string{}
+	for _, v := range tasks {
+		ids = append(ids, v.ID.String())
+	}
+
+	var taskIds []string
+	errUpload := tx.Model(&model.UserTaskUpload{}).Where("task_id IN ?", ids).Distinct().Pluck("task_id", &taskIds).Error
+	if errUpload != nil {
+		return nil, nil, errUpload
+	}
+	for _, v := range taskIds {
+		uploaded[v] = true
 	}
 
-	if taskData.Status == "Diterima" {
-		userPoint, _ := strconv.Atoi(userData.Point)
-		userTotalPoint, _ := strconv.Atoi(userData.TotalPoint)
+	return tasks, uploaded, nil
+}
+
+// UpdateTaskSeries implements entity.TaskDataInterface.
+//
+// Occurrences from the given date that nobody uploaded yet are removed so
+// the generator recreates them from the new rule, the others only take the
+// new title, description and point.
+func (taskRepo *TaskRepository) UpdateTaskSeries(id string, data entity.TaskSeriesCore, from string) error {
+	return taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		update := tx.Model(&model.TaskSeries{}).Where("id = ?", id).Updates(map[string]any{
+			"title":           data.Title,
+			"description":     data.Description,
+			"point":           data.Point,
+			"rule":            data.Rule,
+			"start_date":      data.StartDate,
+			"duration_days":   data.DurationDays,
+			"generated_until": "",
+		})
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if update.RowsAffected == 0 {
+			return errors.New("task series not found")
+		}
+
+		tasks, uploaded, err := futureOccurrences(tx, id, from, false)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range tasks {
+			if !uploaded[v.ID.String()] {
+				if err := tx.Delete(&v).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			err := tx.Model(&v).Updates(map[string]any{
+				"title":       data.Title,
+				"description": data.Description,
+				"point":       data.Point,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// CancelTaskSeries implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) CancelTaskSeries(id string, from string) error {
+	return taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		update := tx.Model(&model.TaskSeries{}).Where("id = ?", id).Update("status", "Cancelled")
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if update.RowsAffected == 0 {
+			return errors.New("task series not found")
+		}
+
+		tasks, uploaded, err := futureOccurrences(tx, id, from, true)
+		if err != nil {
+			return err
+		}
+
+		//occurrences somebody already uploaded stay open for review
+		for _, v := range tasks {
+			if uploaded[v.ID.String()] {
+				continue
+			}
+			if err := tx.Model(&v).Update("status", "Cancelled").Error; err != nil {
+				return err
+			}
+		}
 
-		count := userPoint + pointTask.Point
-		countTotal := userTotalPoint + pointTask.Point
+		return nil
+	})
+}
 
-		userData.Point = strconv.Itoa(count)
-		userData.TotalPoint = strconv.Itoa(countTotal)
+// GenerateTaskSeries implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) GenerateTaskSeries(id string, dates []string, until string) (int, error) {
+	created := 0
 
-		saveUser := user.UserModelToUserCore(userData)
+	errTx := taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		var series model.TaskSeries
+		if err := tx.Where("id = ?", id).First(&series).Error; err != nil {
+			return err
+		}
 
-		updateUser := taskRepo.userRepository.UpdatePoint(data.UserId, saveUser)
-		if updateUser != nil {
-			return updateUser
+		var existing []string
+		errExisting := tx.Model(&model.Task{}).Where("series_id = ?", id).Pluck("occurrence_date", &existing).Error
+		if errExisting != nil {
+			return errExisting
 		}
 
-		//update history
-		historyData := user.UserPointCore{
-			UserId:   data.UserId,
-			Type:     "Submission",
-			Point:    pointTask.Point,
-			TaskName: pointTask.Title,
+		found := map[string]bool{}
+		for _, v := range existing {
+			found[v] = true
 		}
-		errUserHistory := taskRepo.userRepository.PostUserPointHistory(historyData)
-		if errUserHistory != nil {
-			return errors.New("failed add user history point")
+
+		seriesId := series.Id.String()
+		for _, date := range dates {
+			if found[date] {
+				continue
+			}
+
+			start, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				return err
+			}
+
+			task := model.Task{
+				ID:             uuid.New(),
+				AdminId:        series.AdminId,
+				Title:          series.Title,
+				Description:    series.Description,
+				Point:          series.Point,
+				Status:         "Active",
+				Type:           "Task",
+				Start_date:     date,
+				End_date:       start.AddDate(0, 0, series.DurationDays).Format("2006-01-02"),
+				SeriesId:       &seriesId,
+				OccurrenceDate: date,
+			}
+			if err := tx.Create(&task).Error; err != nil {
+				return err
+			}
+			created++
 		}
+
+		return tx.Model(&series).Update("generated_until", until).Error
+	})
+	if errTx != nil {
+		return 0, errTx
 	}
 
-	return nil
+	return created, nil
 }
 
 // FindAllClaimedTask implements entity.TaskDataInterface.
@@ -234,6 +389,7 @@ func (taskRepo *TaskRepository) FindAllClaimedTask(userId string) ([]entity.User
 			TaskId:      v.TaskId,
 			UserId:      v.UserId,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Status:      v.Status,
 			Message:     v.Message,
@@ -261,6 +417,7 @@ func (taskRepo *TaskRepository) FindAllRequestTaskHistory(userId string) ([]enti
 			Type:        v.Type,
 			Point:       v.Point,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Status:      v.Status,
 			Message:     v.Message,
@@ -282,67 +439,131 @@ func (taskRepo *TaskRepository) FindTasksNotClaimedByUser(userId string) ([]enti
 	WHERE id NOT IN (
 		SELECT task_id FROM user_task_uploads 
 		WHERE user_id = ? AND status != 'Ditolak'
-	) AND status = 'Active' AND end_date >= ?
-`, userId, currentDate).Scan(&tasks)
+	) AND status = 'Active' AND start_date <= ? AND end_date >= ?
+`, userId, currentDate, currentDate).Scan(&tasks)
 
 	data := entity.ListTaskModelToTaskCore(tasks)
 	return data, nil
 }
 
-// UploadTask implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) UploadTask(input entity.UserTaskUploadCore, image *multipart.FileHeader) error {
+// UploadTask implements entity.TaskDataInterface and returns the id of the
+// submission.
+func (taskRepo *TaskRepository) UploadTask(input entity.UserTaskUploadCore, image *multipart.FileHeader) (string, error) {
 	newUUID, UUIDerr := uuid.NewRandom()
 	if UUIDerr != nil {
-		return UUIDerr
+		return "", UUIDerr
 	}
 
-	file, err := image.Open()
+	stored, err := storage.SaveImage(context.Background(), taskRepo.storage, "images/uploadTask", image)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer file.Close()
-
-	// Define the directory where you want to save the image
-	saveDir := "public/images/uploadTask"
-	os.MkdirAll(saveDir, os.ModePerm)
-
-	// Define the file path
-	filePath := filepath.Join(saveDir, image.Filename)
 
-	// Replace backslashes with forward slashes for consistency
-	filePath = strings.ReplaceAll(filePath, "\\", "/")
+	input.Image = stored.URL
+	input.Thumbnail = stored.ThumbnailURL
 
-	// Create the destination file
-	dst, err := os.Create(filePath)
+	match, err := taskRepo.findImageMatch(&model.UserTaskUpload{}, input.TaskId, input.UserId, stored.Hash, stored.DHash, "")
 	if err != nil {
-		return err
-	}
-	defer dst.Close()
-
-	// Copy the uploaded file data to the destination file
-	if _, err = io.Copy(dst, file); err != nil {
-		return err
+		storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+		return "", err
 	}
 
-	input.Image = filePath
-
 	var inputData = model.UserTaskUpload{
 		Id:          newUUID,
 		TaskId:      input.TaskId,
 		UserId:      input.UserId,
 		Image:       input.Image,
+		Thumbnail:   input.Thumbnail,
+		ImageHash:   stored.Hash,
+		ImageDhash:  stored.DHash,
 		Description: input.Description,
-		Status:      input.Status,
+		Status:      review.Pending,
 		CreatedAt:   input.CreatedAt,
 		UpdatedAt:   input.UpdatedAt,
 	}
 
-	errUpload := taskRepo.db.Save(&inputData)
-	if errUpload != nil {
-		return errUpload.Error
+	if match != nil {
+		inputData.DuplicateOf = match.Id
+		inputData.DuplicateType = match.Type
+		inputData.DuplicateDistance = match.Distance
 	}
 
-	return nil
+	err = taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&inputData).Error; err != nil {
+			return err
+		}
+
+		return createRevision(tx, entity.RevisionCore{
+			SubmissionId: newUUID.String(),
+			Type:         entity.KindTask,
+			UserId:       input.UserId,
+			Status:       review.Pending,
+			Image:        input.Image,
+			Thumbnail:    input.Thumbnail,
+			Description:  input.Description,
+		})
+	})
+	if err != nil {
+		storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+		return "", err
+	}
+
+	return newUUID.String(), nil
+}
+
+// similarImageCandidates is the most uploads of a task, and of a student,
+// whose difference hashes are compared with a new upload.
+const similarImageCandidates = 200
+
+// findImageMatch returns the earlier task or religion upload whose image
+// matches the fingerprints best, nil when none does. The same file is
+// looked up among the uploads of every student by its indexed hash.
+// Similar images are looked for in table only, among the recent uploads
+// of the task, copied from a classmate, and of the student, sent again
+// for another day, see imageproc.MatchSince. The upload exclude is
+// skipped, it is the one sent again.
+func (taskRepo *TaskRepository) findImageMatch(table any, taskId string, userId string, hash string, dhash string, exclude string) (*entity.ImageMatch, error) {
+	if hash != "" {
+		for _, table := range []any{&model.UserTaskUpload{}, &model.UserReligionTaskUpload{}} {
+			var uploads []entity.ImageMatch
+			err := taskRepo.db.Model(table).Select("id", "type").
+				Where("image_hash = ? AND id <> ?", hash, exclude).Order("created_at").Limit(1).Find(&uploads).Error
+			if err != nil {
+				return nil, err
+			}
+
+			if len(uploads) > 0 {
+				return &uploads[0], nil
+			}
+		}
+	}
+
+	if dhash == "" {
+		return nil, nil
+	}
+
+	var best *entity.ImageMatch
+	for _, owner := range []struct{ column, id string }{{"task_id", taskId}, {"user_id", userId}} {
+		var uploads []struct {
+			Id         string
+			Type       string
+			ImageDhash string
+		}
+		err := taskRepo.db.Model(table).Select("id", "type", "image_dhash").
+			Where(owner.column+" = ? AND created_at >= ? AND image_dhash <> '' AND id <> ?", owner.id, imageproc.MatchSince(), exclude).
+			Order("created_at desc").Limit(similarImageCandidates).Find(&uploads).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range uploads {
+			distance, ok := imageproc.Match("", dhash, "", v.ImageDhash)
+			if ok && (best == nil || distance < best.Distance) {
+				best = &entity.ImageMatch{Id: v.Id, Type: v.Type, Distance: distance}
+			}
+		}
+	}
+	return best, nil
 }
 
 // FindUserTask implements entity.TaskDataInterface.
@@ -361,6 +582,8 @@ func (taskRepo *TaskRepository) FindAllUserTask() ([]entity.UserTaskUploadCore,
 			TaskId:      v.TaskId,
 			UserId:      v.UserId,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
+			Duplicate:   entity.NewImageMatch(v.DuplicateOf, v.DuplicateType, v.DuplicateDistance),
 			Type:        v.Type,
 			Description: v.Description,
 			Status:      v.Status,
@@ -393,6 +616,7 @@ func (taskRepo *TaskRepository) FindUserTaskById(id string) (entity.UserTaskUplo
 		Type:        data.Type,
 		Message:     data.Message,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		CreatedAt:   data.CreatedAt,
@@ -419,6 +643,7 @@ func (taskRepo *TaskRepository) FindUserTaskReqById(id string) (entity.UserTaskS
 		UserId:      data.UserId,
 		UserName:    userData.Name,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Point:       data.Point,
 		Description: data.Description,
 		Status:      data.Status,
@@ -431,42 +656,21 @@ func (taskRepo *TaskRepository) FindUserTaskReqById(id string) (entity.UserTaskS
 	return userCore, nil
 }
 
-// UploadTaskRequest implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) UploadTaskRequest(input entity.UserTaskSubmissionCore, image *multipart.FileHeader) error {
+// UploadTaskRequest implements entity.TaskDataInterface and returns the id of the
+// submission.
+func (taskRepo *TaskRepository) UploadTaskRequest(input entity.UserTaskSubmissionCore, image *multipart.FileHeader) (string, error) {
 	newUUID, UUIDerr := uuid.NewRandom()
 	if UUIDerr != nil {
-		return UUIDerr
+		return "", UUIDerr
 	}
 
-	file, err := image.Open()
+	stored, err := storage.SaveImage(context.Background(), taskRepo.storage, "images/uploadTaskRequest", image)
 	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Define the directory where you want to save the image
-	saveDir := "public/images/uploadTaskRequest"
-	os.MkdirAll(saveDir, os.ModePerm)
-
-	// Define the file path
-	filePath := filepath.Join(saveDir, image.Filename)
-
-	// Replace backslashes with forward slashes for consistency
-	filePath = strings.ReplaceAll(filePath, "\\", "/")
-
-	// Create the destination file
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return err
+		return "", err
 	}
-	defer dst.Close()
 
-	// Copy the uploaded file data to the destination file
-	if _, err = io.Copy(dst, file); err != nil {
-		return err
-	}
-
-	input.Image = filePath
+	input.Image = stored.URL
+	input.Thumbnail = stored.ThumbnailURL
 
 	var inputData = model.UserTaskSubmission{
 		Id:          newUUID,
@@ -474,18 +678,34 @@ func (taskRepo *TaskRepository) UploadTaskRequest(input entity.UserTaskSubmissio
 		Title:       input.Title,
 		Point:       input.Point,
 		Image:       input.Image,
+		Thumbnail:   input.Thumbnail,
 		Description: input.Description,
-		Status:      input.Status,
+		Status:      review.Pending,
 		CreatedAt:   input.CreatedAt,
 		UpdatedAt:   input.UpdatedAt,
 	}
 
-	errUpload := taskRepo.db.Save(&inputData)
-	if errUpload != nil {
-		return errUpload.Error
+	err = taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&inputData).Error; err != nil {
+			return err
+		}
+
+		return createRevision(tx, entity.RevisionCore{
+			SubmissionId: newUUID.String(),
+			Type:         entity.KindSubmission,
+			UserId:       input.UserId,
+			Status:       review.Pending,
+			Image:        input.Image,
+			Thumbnail:    input.Thumbnail,
+			Description:  input.Description,
+		})
+	})
+	if err != nil {
+		storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+		return "", err
 	}
 
-	return nil
+	return newUUID.String(), nil
 }
 
 // FindAllRequestTask implements entity.TaskDataInterface.
@@ -504,6 +724,7 @@ func (taskRepo *TaskRepository) FindAllRequestTask() ([]entity.UserTaskSubmissio
 			UserId:      v.UserId,
 			Title:       v.Title,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Point:       v.Point,
 			Status:      v.Status,
@@ -524,7 +745,7 @@ func (taskRepo *TaskRepository) CountUserClearTask(id string) (int, error) {
 
 	// jumlah pada tabel UserTaskUpload
 	errUpload := taskRepo.db.Model(&model.UserTaskUpload{}).
-		Where("user_id = ? AND status = ?", id, "Diterima").
+		Where("user_id = ? AND status = ?", id, review.Approved).
 		Count(&countUpload).Error
 	if errUpload != nil {
 		return 0, errUpload
@@ -532,7 +753,7 @@ func (taskRepo *TaskRepository) CountUserClearTask(id string) (int, error) {
 
 	// jumlah pada tabel UserTaskSubmission
 	errSubmission := taskRepo.db.Model(&model.UserTaskSubmission{}).
-		Where("user_id = ? AND status = ?", id, "Diterima").
+		Where("user_id = ? AND status = ?", id, review.Approved).
 		Count(&countSubmission).Error
 	if errSubmission != nil {
 		return 0, errSubmission
@@ -540,7 +761,7 @@ func (taskRepo *TaskRepository) CountUserClearTask(id string) (int, error) {
 
 	// jumlah pada tabel UserReligionTaskUpload
 	errTask := taskRepo.db.Model(&model.UserReligionTaskUpload{}).
-		Where("user_id = ? AND status = ?", id, "Diterima").
+		Where("user_id = ? AND status = ?", id, review.Approved).
 		Count(&countTask).Error
 	if errTask != nil {
 		return 0, errTask
@@ -629,34 +850,147 @@ func (taskRepo *TaskRepository) UpdateTaskReligion(taskId string, data entity.Re
 	return nil
 }
 
-// FindTaskByDateAndReligion implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) FindTaskByDateAndReligion(date string, religion string) ([]entity.ReligionTaskCore, error) {
-	var tasks []model.ReligionTask
-	err := taskRepo.db.Where("start_date = ? AND religion = ? AND title = ?", date, religion, "Subuh").Find(&tasks).Error
-	if err != nil {
-		return nil, err
+// CreateReligionTemplate implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) CreateReligionTemplate(input entity.ReligionTemplateCore) (entity.ReligionTemplateCore, error) {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return entity.ReligionTemplateCore{}, UUIDerr
 	}
 
-	var taskCores []entity.ReligionTaskCore
-	for _, task := range tasks {
-		taskCores = append(taskCores, entity.ReligionTaskModelToTaskCore(task))
+	data := entity.ReligionTemplateCoreToReligionTemplateModel(input)
+	data.Id = newUUID
+	tx := taskRepo.db.Create(&data)
+	if tx.Error != nil {
+		return entity.ReligionTemplateCore{}, tx.Error
 	}
-	return taskCores, nil
+	return entity.ReligionTemplateModelToReligionTemplateCore(data), nil
 }
 
-// FindTaskByDateAndReligionNon implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) FindTaskByDateAndReligionNon(date string, religion string) ([]entity.ReligionTaskCore, error) {
-	var tasks []model.ReligionTask
-	err := taskRepo.db.Where("start_date = ? AND religion = ? AND title = ?", date, religion, "Ibadah Minggu").Find(&tasks).Error
-	if err != nil {
-		return nil, err
+// FindAllReligionTemplate implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindAllReligionTemplate(religion string) ([]entity.ReligionTemplateCore, error) {
+	var templates []model.ReligionTaskTemplate
+
+	query := taskRepo.db.Order("religion").Order("title")
+	if religion != "" {
+		query = query.Where("religion = ?", religion)
+	}
+
+	errData := query.Find(&templates).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	return entity.ListReligionTemplateModelToReligionTemplateCore(templates), nil
+}
+
+// FindActiveReligionTemplate implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindActiveReligionTemplate(religion string) ([]entity.ReligionTemplateCore, error) {
+	var templates []model.ReligionTaskTemplate
+
+	query := taskRepo.db.Where("active = ?", true)
+	if religion != "" {
+		query = query.Where("religion = ?", religion)
+	}
+
+	errData := query.Find(&templates).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	return entity.ListReligionTemplateModelToReligionTemplateCore(templates), nil
+}
+
+// FindReligionTemplateById implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindReligionTemplateById(id string) (entity.ReligionTemplateCore, error) {
+	template := model.ReligionTaskTemplate{}
+
+	tx := taskRepo.db.Where("id = ?", id).First(&template)
+	if tx.Error != nil {
+		return entity.ReligionTemplateCore{}, tx.Error
+	}
+
+	return entity.ReligionTemplateModelToReligionTemplateCore(template), nil
+}
+
+// UpdateReligionTemplate implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) UpdateReligionTemplate(id string, data entity.ReligionTemplateCore) error {
+	tx := taskRepo.db.Model(&model.ReligionTaskTemplate{}).Where("id = ?", id).Updates(map[string]any{
+		"religion":      data.Religion,
+		"title":         data.Title,
+		"description":   data.Description,
+		"point":         data.Point,
+		"rule":          data.Rule,
+		"start_date":    data.StartDate,
+		"duration_days": data.DurationDays,
+		"prayer_window": data.PrayerWindow,
+		"holiday":       data.Holiday,
+		"active":        data.Active,
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.New("template not found")
 	}
 
-	var taskCores []entity.ReligionTaskCore
-	for _, task := range tasks {
-		taskCores = append(taskCores, entity.ReligionTaskModelToTaskCore(task))
+	return nil
+}
+
+// DeleteReligionTemplate implements entity.TaskDataInterface. Tasks already
+// generated from the template are kept.
+func (taskRepo *TaskRepository) DeleteReligionTemplate(id string) error {
+	tx := taskRepo.db.Where("id = ?", id).Delete(&model.ReligionTaskTemplate{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return errors.New("template not found")
+	}
+
+	return nil
+}
+
+// GenerateReligionTasks implements entity.TaskDataInterface. Tasks whose
+// day already has a task from the template are skipped.
+func (taskRepo *TaskRepository) GenerateReligionTasks(templateId string, tasks []entity.ReligionTaskCore) (int, error) {
+	created := 0
+
+	errTx := taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		var existing []string
+		errExisting := tx.Model(&model.ReligionTask{}).Where("template_id = ?", templateId).Pluck("occurrence_date", &existing).Error
+		if errExisting != nil {
+			return errExisting
+		}
+
+		found := map[string]bool{}
+		for _, v := range existing {
+			found[v] = true
+		}
+
+		for _, v := range tasks {
+			if found[v.OccurrenceDate] {
+				continue
+			}
+
+			task := entity.ReligionTaskCoreToTaskModel(v)
+			task.Id = uuid.New()
+			task.Type = "Religion"
+			task.TemplateId = &templateId
+			if err := tx.Create(&task).Error; err != nil {
+				return err
+			}
+			created++
+		}
+
+		return nil
+	})
+	if errTx != nil {
+		return 0, errTx
 	}
-	return taskCores, nil
+
+	return created, nil
 }
 
 // FindAllReligionTask implements entity.TaskDataInterface.
@@ -670,9 +1004,10 @@ func (taskRepo *TaskRepository) FindAllReligionTaskUser(religion string, userId
 	WHERE id NOT IN (
 		SELECT task_id FROM user_religion_task_uploads 
 		WHERE user_id = ? AND status != 'Ditolak'
-	) AND DATE(end_date) >= ?
+	) AND end_date >= ? AND start_date <= ?
+	AND (end_at IS NULL OR end_at >= ?)
 	AND religion = ?
-`, userId, currentTime, religion).Scan(&religionTask).Error
+`, userId, currentTime, currentTime, time.Now(), religion).Scan(&religionTask).Error
 	if errData != nil {
 		return nil, errData
 	}
@@ -687,6 +1022,8 @@ func (taskRepo *TaskRepository) FindAllReligionTaskUser(religion string, userId
 			Religion:    v.Religion,
 			Start_date:  v.Start_date,
 			End_date:    v.End_date,
+			StartAt:     v.StartAt,
+			EndAt:       v.EndAt,
 			CreatedAt:   v.CreatedAt,
 			UpdatedAt:   v.UpdatedAt,
 		}
@@ -712,6 +1049,7 @@ func (taskRepo *TaskRepository) FindAllReligionTaskHistory(userId string) ([]ent
 			TaskId:      v.TaskId,
 			TaskName:    taskData.Title,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Type:        v.Type,
 			Status:      v.Status,
@@ -723,60 +1061,69 @@ func (taskRepo *TaskRepository) FindAllReligionTaskHistory(userId string) ([]ent
 	return mapData, nil
 }
 
-// UploadTaskReligion implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) UploadTaskReligion(input entity.UserReligionTaskUploadCore, image *multipart.FileHeader) error {
+// UploadTaskReligion implements entity.TaskDataInterface and returns the id of the
+// submission.
+func (taskRepo *TaskRepository) UploadTaskReligion(input entity.UserReligionTaskUploadCore, image *multipart.FileHeader) (string, error) {
 	newUUID, UUIDerr := uuid.NewRandom()
 	if UUIDerr != nil {
-		return UUIDerr
+		return "", UUIDerr
 	}
 
-	file, err := image.Open()
+	stored, err := storage.SaveImage(context.Background(), taskRepo.storage, "images/uploadTaskReligion", image)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer file.Close()
-
-	// Define the directory where you want to save the image
-	saveDir := "public/images/uploadTaskReligion"
-	os.MkdirAll(saveDir, os.ModePerm)
-
-	// Define the file path
-	filePath := filepath.Join(saveDir, image.Filename)
 
-	// Replace backslashes with forward slashes for consistency
-	filePath = strings.ReplaceAll(filePath, "\\", "/")
+	input.Image = stored.URL
+	input.Thumbnail = stored.ThumbnailURL
 
-	// Create the destination file
-	dst, err := os.Create(filePath)
+	match, err := taskRepo.findImageMatch(&model.UserReligionTaskUpload{}, input.TaskId, input.UserId, stored.Hash, stored.DHash, "")
 	if err != nil {
-		return err
+		storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+		return "", err
 	}
-	defer dst.Close()
-
-	// Copy the uploaded file data to the destination file
-	if _, err = io.Copy(dst, file); err != nil {
-		return err
-	}
-
-	input.Image = filePath
 
 	var inputData = model.UserReligionTaskUpload{
 		Id:          newUUID,
 		UserId:      input.UserId,
 		Image:       input.Image,
+		Thumbnail:   input.Thumbnail,
+		ImageHash:   stored.Hash,
+		ImageDhash:  stored.DHash,
 		TaskId:      input.TaskId,
 		Description: input.Description,
-		Status:      input.Status,
+		Status:      review.Pending,
 		CreatedAt:   input.CreatedAt,
 		UpdatedAt:   input.UpdatedAt,
 	}
 
-	errUpload := taskRepo.db.Save(&inputData)
-	if errUpload != nil {
-		return errUpload.Error
+	if match != nil {
+		inputData.DuplicateOf = match.Id
+		inputData.DuplicateType = match.Type
+		inputData.DuplicateDistance = match.Distance
 	}
 
-	return nil
+	err = taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&inputData).Error; err != nil {
+			return err
+		}
+
+		return createRevision(tx, entity.RevisionCore{
+			SubmissionId: newUUID.String(),
+			Type:         entity.KindReligion,
+			UserId:       input.UserId,
+			Status:       review.Pending,
+			Image:        input.Image,
+			Thumbnail:    input.Thumbnail,
+			Description:  input.Description,
+		})
+	})
+	if err != nil {
+		storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+		return "", err
+	}
+
+	return newUUID.String(), nil
 }
 
 // FindAllUserReligionTaskUpload implements entity.TaskDataInterface.
@@ -795,6 +1142,8 @@ func (taskRepo *TaskRepository) FindAllUserReligionTaskUpload() ([]entity.UserRe
 			TaskId:      v.TaskId,
 			UserId:      v.UserId,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
+			Duplicate:   entity.NewImageMatch(v.DuplicateOf, v.DuplicateType, v.DuplicateDistance),
 			Type:        v.Type,
 			Description: v.Description,
 			Status:      v.Status,
@@ -827,6 +1176,7 @@ func (taskRepo *TaskRepository) FindSpecificUserReligionTaskUpload(id string) (e
 		Type:        data.Type,
 		Message:     data.Message,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		CreatedAt:   data.CreatedAt,
@@ -836,63 +1186,6 @@ func (taskRepo *TaskRepository) FindSpecificUserReligionTaskUpload(id string) (e
 	return userCore, nil
 }
 
-// UpdateReligionTaskStatus implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) UpdateReligionTaskStatus(id string, data entity.UserReligionTaskUploadCore) error {
-	var pointTask model.ReligionTask
-	var userData userModel.Users
-	taskData := entity.ReligionTaskUploadCoreToReligionTaskUploadModel(data)
-
-	// get user
-	errUser := taskRepo.db.Where("id=?", data.UserId).First(&userData).Error
-	if errUser != nil {
-		return errUser
-	}
-
-	// update status
-	tx := taskRepo.db.Where("id=?", data.Id).Updates(taskData)
-	if tx.Error != nil {
-		return tx.Error
-	}
-
-	// get task data
-	errData := taskRepo.db.Where("id=?", id).First(&pointTask).Error
-	if errData != nil {
-		return errData
-	}
-
-	if taskData.Status == "Diterima" {
-		userPoint, _ := strconv.Atoi(userData.Point)
-		userTotalPoint, _ := strconv.Atoi(userData.TotalPoint)
-
-		count := userPoint + pointTask.Point
-		countTotal := userTotalPoint + pointTask.Point
-
-		userData.Point = strconv.Itoa(count)
-		userData.TotalPoint = strconv.Itoa(countTotal)
-
-		saveUser := user.UserModelToUserCore(userData)
-
-		updateUser := taskRepo.userRepository.UpdatePoint(data.UserId, saveUser)
-		if updateUser != nil {
-			return updateUser
-		}
-
-		//update history
-		historyData := user.UserPointCore{
-			UserId:   data.UserId,
-			Type:     "Religion",
-			Point:    pointTask.Point,
-			TaskName: pointTask.Title,
-		}
-		errUserHistory := taskRepo.userRepository.PostUserPointHistory(historyData)
-		if errUserHistory != nil {
-			return errors.New("failed add user history point")
-		}
-	}
-
-	return nil
-}
-
 // FindAllReligionTaskRequestHistory implements entity.TaskDataInterface.
 func (taskRepo *TaskRepository) FindAllReligionTaskRequestHistory(userId string) ([]entity.UserReligionReqTaskCore, error) {
 	var task []model.UserReligionReqTask
@@ -909,6 +1202,7 @@ func (taskRepo *TaskRepository) FindAllReligionTaskRequestHistory(userId string)
 			UserId:      v.UserId,
 			UserName:    userData.Name,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Status:      v.Status,
 			Point:       v.Point,
@@ -941,6 +1235,7 @@ func (taskRepo *TaskRepository) FindSpesificReligionTaskRequest(id string) (enti
 		Message:     data.Message,
 		Point:       data.Point,
 		Image:       data.Image,
+		Thumbnail:   data.Thumbnail,
 		Description: data.Description,
 		Status:      data.Status,
 		CreatedAt:   data.CreatedAt,
@@ -950,42 +1245,21 @@ func (taskRepo *TaskRepository) FindSpesificReligionTaskRequest(id string) (enti
 	return userCore, nil
 }
 
-// UploadReligionTaskRequest implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) UploadReligionTaskRequest(input entity.UserReligionReqTaskCore, image *multipart.FileHeader) error {
+// UploadReligionTaskRequest implements entity.TaskDataInterface and returns the id of the
+// submission.
+func (taskRepo *TaskRepository) UploadReligionTaskRequest(input entity.UserReligionReqTaskCore, image *multipart.FileHeader) (string, error) {
 	newUUID, UUIDerr := uuid.NewRandom()
 	if UUIDerr != nil {
-		return UUIDerr
+		return "", UUIDerr
 	}
 
-	file, err := image.Open()
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Define the directory where you want to save the image
-	saveDir := "public/images/uploadTaskReligionRequest"
-	os.MkdirAll(saveDir, os.ModePerm)
-
-	// Define the file path
-	filePath := filepath.Join(saveDir, image.Filename)
-
-	// Replace backslashes with forward slashes for consistency
-	filePath = strings.ReplaceAll(filePath, "\\", "/")
-
-	// Create the destination file
-	dst, err := os.Create(filePath)
+	stored, err := storage.SaveImage(context.Background(), taskRepo.storage, "images/uploadTaskReligionRequest", image)
 	if err != nil {
-		return err
-	}
-	defer dst.Close()
-
-	// Copy the uploaded file data to the destination file
-	if _, err = io.Copy(dst, file); err != nil {
-		return err
+		return "", err
 	}
 
-	input.Image = filePath
+	input.Image = stored.URL
+	input.Thumbnail = stored.ThumbnailURL
 
 	var inputData = model.UserReligionReqTask{
 		Id:          newUUID,
@@ -993,18 +1267,34 @@ func (taskRepo *TaskRepository) UploadReligionTaskRequest(input entity.UserRelig
 		Title:       input.Title,
 		Point:       input.Point,
 		Image:       input.Image,
+		Thumbnail:   input.Thumbnail,
 		Description: input.Description,
-		Status:      input.Status,
+		Status:      review.Pending,
 		CreatedAt:   input.CreatedAt,
 		UpdatedAt:   input.UpdatedAt,
 	}
 
-	errUpload := taskRepo.db.Save(&inputData)
-	if errUpload != nil {
-		return errUpload.Error
+	err = taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&inputData).Error; err != nil {
+			return err
+		}
+
+		return createRevision(tx, entity.RevisionCore{
+			SubmissionId: newUUID.String(),
+			Type:         entity.KindReligionRequest,
+			UserId:       input.UserId,
+			Status:       review.Pending,
+			Image:        input.Image,
+			Thumbnail:    input.Thumbnail,
+			Description:  input.Description,
+		})
+	})
+	if err != nil {
+		storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+		return "", err
 	}
 
-	return nil
+	return newUUID.String(), nil
 }
 
 // GetAllUserReligionTaskRequest implements entity.TaskDataInterface.
@@ -1023,6 +1313,7 @@ func (taskRepo *TaskRepository) GetAllUserReligionTaskRequest() ([]entity.UserRe
 			UserId:      v.UserId,
 			Title:       v.Title,
 			Image:       v.Image,
+			Thumbnail:   v.Thumbnail,
 			Description: v.Description,
 			Point:       v.Point,
 			Status:      v.Status,
@@ -1035,59 +1326,471 @@ func (taskRepo *TaskRepository) GetAllUserReligionTaskRequest() ([]entity.UserRe
 	return mapData, nil
 }
 
-// UpdateTaskReligionReqStatus implements entity.TaskDataInterface.
-func (taskRepo *TaskRepository) UpdateTaskReligionReqStatus(id string, data entity.UserReligionReqTaskCore) error {
-	var pointTask model.UserReligionReqTask
-	var userData userModel.Users
-	taskData := entity.ReligionTaskReqCoreToReligioinTaskReqModel(data)
+// submissionTable is where a kind of submission is stored, task is the
+// table of the tasks uploads are for, nil for requests.
+type submissionTable struct {
+	submission any
+	task       any
+	name       string
+	taskName   string
+	folder     string
+	pointType  string
+}
+
+func submissionTableOf(kind string) (submissionTable, error) {
+	switch kind {
+	case entity.KindTask:
+		return submissionTable{&model.UserTaskUpload{}, &model.Task{}, "user_task_uploads", "tasks", "images/uploadTask", user.PointTypeTask}, nil
+	case entity.KindSubmission:
+		return submissionTable{&model.UserTaskSubmission{}, nil, "user_task_submissions", "", "images/uploadTaskRequest", user.PointTypeSubmission}, nil
+	case entity.KindReligion:
+		return submissionTable{&model.UserReligionTaskUpload{}, &model.ReligionTask{}, "user_religion_task_uploads", "religion_tasks", "images/uploadTaskReligion", user.PointTypeReligion}, nil
+	case entity.KindReligionRequest:
+		return submissionTable{&model.UserReligionReqTask{}, nil, "user_religion_req_tasks", "", "images/uploadTaskReligionRequest", user.PointTypeReligionRequest}, nil
+	}
+	return submissionTable{}, errors.New("unknown submission type " + kind)
+}
 
-	// get user
-	errUser := taskRepo.db.Where("id=?", data.UserId).First(&userData).Error
-	if errUser != nil {
-		return errUser
+func createRevision(tx *gorm.DB, data entity.RevisionCore) error {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return UUIDerr
 	}
 
-	// update status
-	tx := taskRepo.db.Where("id=?", id).Updates(taskData)
-	if tx.Error != nil {
-		return tx.Error
+	data.Id = newUUID
+	revision := entity.RevisionCoreToRevisionModel(data)
+	return tx.Create(&revision).Error
+}
+
+// FindSubmission implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindSubmission(kind string, id string) (entity.SubmissionCore, error) {
+	table, err := submissionTableOf(kind)
+	if err != nil {
+		return entity.SubmissionCore{}, err
 	}
 
-	// get task data
-	errData := taskRepo.db.Where("id=?", id).First(&pointTask).Error
+	var data entity.SubmissionCore
+	errData := taskRepo.db.Model(table.submission).Select("*").Where("id=?", id).Take(&data).Error
 	if errData != nil {
-		return errData
+		return entity.SubmissionCore{}, errData
+	}
+	data.Type = kind
+
+	// uploads are worth the point of their task
+	if table.task != nil {
+		var task struct {
+			Title string
+			Point int
+		}
+		errTask := taskRepo.db.Model(table.task).Select("title", "point").Where("id=?", data.TaskId).Take(&task).Error
+		if errTask != nil {
+			return entity.SubmissionCore{}, errTask
+		}
+		data.Title, data.Point = task.Title, task.Point
+	}
+
+	return data, nil
+}
+
+// ReviewSubmission implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) ReviewSubmission(submission entity.SubmissionCore, data entity.ReviewCore) error {
+	table, err := submissionTableOf(submission.Type)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]any{"status": data.Status, "message": data.Message}
+	if data.Point > 0 && table.task == nil {
+		values["point"] = data.Point
+		submission.Point = data.Point
 	}
 
-	if taskData.Status == "Diterima" {
-		userPoint, _ := strconv.Atoi(userData.Point)
-		userTotalPoint, _ := strconv.Atoi(userData.TotalPoint)
+	return taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		// only the status the review was checked against is replaced, so
+		// two reviewers can't both review a submission and the final
+		// approval credits the point once, and only while no other
+		// reviewer holds the claim
+		update := tx.Model(table.submission).Where("id=? AND status=?", submission.Id, submission.Status).
+			Where("NOT EXISTS (SELECT 1 FROM review_claims WHERE submission_id=? AND type=? AND reviewer_id<>? AND expired_at>?)",
+				submission.Id, submission.Type, data.ReviewerId, time.Now()).
+			Updates(values)
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if update.RowsAffected == 0 {
+			claim, errClaim := findReviewClaim(tx, submission.Type, submission.Id)
+			if errClaim != nil {
+				return errClaim
+			}
+			if claim.ReviewerId != "" && claim.ReviewerId != data.ReviewerId {
+				return fmt.Errorf("%w until %s", review.ErrClaimed, claim.ExpiredAt.Format("15:04"))
+			}
+			return review.ErrConflict
+		}
+
+		errClaim := tx.Where("submission_id=? AND type=?", submission.Id, submission.Type).Delete(&model.ReviewClaim{}).Error
+		if errClaim != nil {
+			return errClaim
+		}
+
+		errRevision := createRevision(tx, entity.RevisionCore{
+			SubmissionId: submission.Id,
+			Type:         submission.Type,
+			UserId:       data.ReviewerId,
+			FromStatus:   submission.Status,
+			Status:       data.Status,
+			Message:      data.Message,
+		})
+		if errRevision != nil {
+			return errRevision
+		}
+
+		if data.Status == review.Approved {
+			//update user point
+			historyData := user.UserPointCore{
+				UserId:          submission.UserId,
+				Type:            table.pointType,
+				SourceId:        submission.Id,
+				Point:           submission.Point,
+				PointDelta:      submission.Point,
+				TotalPointDelta: submission.Point,
+				TaskName:        submission.Title,
+			}
+			_, errUserHistory := taskRepo.userRepository.PostPointEntry(tx, historyData)
+			if errUserHistory != nil {
+				return errUserHistory
+			}
+		}
 
-		count := userPoint + pointTask.Point
-		countTotal := userTotalPoint + pointTask.Point
+		return nil
+	})
+}
 
-		userData.Point = strconv.Itoa(count)
-		userData.TotalPoint = strconv.Itoa(countTotal)
+// ResubmitSubmission implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) ResubmitSubmission(submission entity.SubmissionCore, description string, image *multipart.FileHeader) error {
+	table, err := submissionTableOf(submission.Type)
+	if err != nil {
+		return err
+	}
 
-		saveUser := user.UserModelToUserCore(userData)
+	values := map[string]any{"status": review.Resubmitted, "description": description}
+	revision := entity.RevisionCore{
+		SubmissionId: submission.Id,
+		Type:         submission.Type,
+		UserId:       submission.UserId,
+		FromStatus:   submission.Status,
+		Status:       review.Resubmitted,
+		Image:        submission.Image,
+		Thumbnail:    submission.Thumbnail,
+		Description:  description,
+	}
+
+	// without a new image the one sent before is kept
+	var stored storage.StoredImage
+	if image != nil {
+		stored, err = storage.SaveImage(context.Background(), taskRepo.storage, table.folder, image)
+		if err != nil {
+			return err
+		}
 
-		updateUser := taskRepo.userRepository.UpdatePoint(data.UserId, saveUser)
-		if updateUser != nil {
-			return updateUser
+		values["image"] = stored.URL
+		values["thumbnail"] = stored.ThumbnailURL
+		revision.Image = stored.URL
+		revision.Thumbnail = stored.ThumbnailURL
+
+		if table.task != nil {
+			match, err := taskRepo.findImageMatch(table.submission, submission.TaskId, submission.UserId, stored.Hash, stored.DHash, submission.Id)
+			if err != nil {
+				storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+				return err
+			}
+
+			values["image_hash"] = stored.Hash
+			values["image_dhash"] = stored.DHash
+			values["duplicate_of"], values["duplicate_type"], values["duplicate_distance"] = "", "", 0
+			if match != nil {
+				values["duplicate_of"], values["duplicate_type"], values["duplicate_distance"] = match.Id, match.Type, match.Distance
+			}
 		}
+	}
 
-		//update history
-		historyData := user.UserPointCore{
-			UserId:   data.UserId,
-			Type:     "Religion Request",
-			Point:    pointTask.Point,
-			TaskName: pointTask.Title,
+	err = taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		update := tx.Model(table.submission).Where("id=? AND status=?", submission.Id, submission.Status).Updates(values)
+		if update.Error != nil {
+			return update.Error
 		}
-		errUserHistory := taskRepo.userRepository.PostUserPointHistory(historyData)
-		if errUserHistory != nil {
-			return errors.New("failed add user history point")
+
+		if update.RowsAffected == 0 {
+			return review.ErrConflict
 		}
+
+		return createRevision(tx, revision)
+	})
+	if err != nil {
+		storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+		return err
 	}
 
 	return nil
 }
+
+// FindRevisions implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindRevisions(kind string, id string) ([]entity.RevisionCore, error) {
+	var revisions []model.SubmissionRevision
+
+	errData := taskRepo.db.Where("submission_id=? AND type=?", id, kind).Order("created_at").Find(&revisions).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	return entity.ListRevisionModelToRevisionCore(revisions), nil
+}
+
+// reviewQueueRow is a submission of the review queue of any kind, the
+// columns a kind doesn't have stay empty.
+type reviewQueueRow struct {
+	Id                string
+	UserId            string
+	UserName          string
+	TaskId            string
+	Title             string
+	Point             int
+	Image             string
+	Thumbnail         string
+	Description       string
+	Status            string
+	Message           string
+	DuplicateOf       string
+	DuplicateType     string
+	DuplicateDistance int
+	ClaimedBy         string
+	ClaimExpiredAt    *time.Time
+	CreatedAt         time.Time
+}
+
+// FindReviewQueue implements entity.TaskDataInterface. The submissions
+// claimed by other reviewers are left out, the oldest come first.
+func (taskRepo *TaskRepository) FindReviewQueue(filter entity.ReviewQueueFilter) ([]entity.ReviewQueueCore, error) {
+	kinds := entity.SubmissionKinds
+	if filter.Type != "" {
+		kinds = []string{filter.Type}
+	}
+
+	now := time.Now()
+	queue := []entity.ReviewQueueCore{}
+	for _, kind := range kinds {
+		table, err := submissionTableOf(kind)
+		if err != nil {
+			return nil, err
+		}
+
+		columns := "s.id, s.user_id, u.name AS user_name, s.image, s.thumbnail, s.description, s.status, s.message, s.created_at, " +
+			"c.reviewer_id AS claimed_by, c.expired_at AS claim_expired_at"
+		query := taskRepo.db.Table(table.name+" s").
+			Joins("LEFT JOIN users u ON u.id = s.user_id").
+			Joins("LEFT JOIN review_claims c ON c.submission_id = s.id AND c.type = ? AND c.expired_at > ?", kind, now)
+		if table.task != nil {
+			columns += ", s.task_id, t.title, t.point, s.duplicate_of, s.duplicate_type, s.duplicate_distance"
+			query = query.Joins("LEFT JOIN " + table.taskName + " t ON t.id = s.task_id")
+		} else {
+			columns += ", s.title, s.point"
+		}
+
+		var rows []reviewQueueRow
+		errData := query.Select(columns).
+			Where("s.status IN ? AND (c.reviewer_id IS NULL OR c.reviewer_id = ?)", []string{review.Pending, review.Resubmitted}, filter.ReviewerId).
+			Order("s.created_at").Limit(filter.Limit).Scan(&rows).Error
+		if errData != nil {
+			return nil, errData
+		}
+
+		for _, v := range rows {
+			queue = append(queue, entity.ReviewQueueCore{
+				SubmissionCore: entity.SubmissionCore{
+					Id:          v.Id,
+					Type:        kind,
+					UserId:      v.UserId,
+					TaskId:      v.TaskId,
+					Title:       v.Title,
+					Point:       v.Point,
+					Image:       v.Image,
+					Thumbnail:   v.Thumbnail,
+					Description: v.Description,
+					Status:      v.Status,
+				},
+				UserName:       v.UserName,
+				Message:        v.Message,
+				Duplicate:      entity.NewImageMatch(v.DuplicateOf, v.DuplicateType, v.DuplicateDistance),
+				ClaimedBy:      v.ClaimedBy,
+				ClaimExpiredAt: v.ClaimExpiredAt,
+				CreatedAt:      v.CreatedAt,
+			})
+		}
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].CreatedAt.Before(queue[j].CreatedAt) })
+	if len(queue) > filter.Limit {
+		queue = queue[:filter.Limit]
+	}
+	return queue, nil
+}
+
+// findReviewClaim returns the claim of a submission that hasn't expired,
+// an empty one when there is none.
+func findReviewClaim(tx *gorm.DB, kind string, id string) (entity.ReviewClaimCore, error) {
+	var claims []model.ReviewClaim
+
+	errData := tx.Where("submission_id=? AND type=? AND expired_at > ?", id, kind, time.Now()).Find(&claims).Error
+	if errData != nil || len(claims) == 0 {
+		return entity.ReviewClaimCore{}, errData
+	}
+
+	return entity.ReviewClaimCore{
+		SubmissionId: claims[0].SubmissionId,
+		Type:         claims[0].Type,
+		ReviewerId:   claims[0].ReviewerId,
+		ExpiredAt:    claims[0].ExpiredAt,
+	}, nil
+}
+
+// ClaimSubmission implements entity.TaskDataInterface. The claim is taken
+// when it is new, expired or already held by the reviewer, whose lease is
+// extended then.
+func (taskRepo *TaskRepository) ClaimSubmission(kind string, id string, reviewerId string, until time.Time) (bool, error) {
+	claim := model.ReviewClaim{
+		SubmissionId: id,
+		Type:         kind,
+		ReviewerId:   reviewerId,
+		ExpiredAt:    until,
+	}
+	tx := taskRepo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&claim)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	if tx.RowsAffected == 1 {
+		return true, nil
+	}
+
+	tx = taskRepo.db.Model(&model.ReviewClaim{}).
+		Where("submission_id = ? AND type = ? AND (expired_at < ? OR reviewer_id = ?)", id, kind, time.Now(), reviewerId).
+		Updates(map[string]any{
+			"reviewer_id": reviewerId,
+			"expired_at":  until,
+		})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+// ReleaseReviewClaims implements entity.TaskDataInterface. Every claim of
+// the reviewer is released when items is empty.
+func (taskRepo *TaskRepository) ReleaseReviewClaims(reviewerId string, items []entity.SubmissionRef) error {
+	if len(items) == 0 {
+		return taskRepo.db.Where("reviewer_id=?", reviewerId).Delete(&model.ReviewClaim{}).Error
+	}
+
+	return taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		for _, v := range items {
+			err := tx.Where("reviewer_id=? AND submission_id=? AND type=?", reviewerId, v.Id, v.Type).Delete(&model.ReviewClaim{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// commentRow is a comment with the name of its author.
+type commentRow struct {
+	model.SubmissionComment
+	UserName string
+}
+
+// FindComments implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) FindComments(kind string, id string) ([]entity.CommentCore, []entity.CommentReadCore, error) {
+	var rows []commentRow
+
+	errData := taskRepo.db.Table("submission_comments c").
+		Select("c.*, u.name AS user_name").
+		Joins("LEFT JOIN users u ON u.id = c.user_id").
+		Where("c.submission_id=? AND c.type=?", id, kind).
+		Order("c.created_at").Scan(&rows).Error
+	if errData != nil {
+		return nil, nil, errData
+	}
+
+	var reads []model.SubmissionCommentRead
+	errData = taskRepo.db.Where("submission_id=? AND type=?", id, kind).Order("read_at").Find(&reads).Error
+	if errData != nil {
+		return nil, nil, errData
+	}
+
+	comments := []entity.CommentCore{}
+	for _, v := range rows {
+		comment := entity.CommentModelToCommentCore(v.SubmissionComment)
+		comment.UserName = v.UserName
+		comments = append(comments, comment)
+	}
+
+	return comments, entity.ListCommentReadModelToCommentReadCore(reads), nil
+}
+
+// CreateComment implements entity.TaskDataInterface. The thread counts as
+// read by the author up to the new comment.
+func (taskRepo *TaskRepository) CreateComment(input entity.CommentCore, image *multipart.FileHeader) (entity.CommentCore, error) {
+	newUUID, UUIDerr := uuid.NewRandom()
+	if UUIDerr != nil {
+		return entity.CommentCore{}, UUIDerr
+	}
+
+	var stored storage.StoredImage
+	if image != nil {
+		var err error
+		stored, err = storage.SaveImage(context.Background(), taskRepo.storage, "images/comments", image)
+		if err != nil {
+			return entity.CommentCore{}, err
+		}
+
+		input.Image = stored.URL
+		input.Thumbnail = stored.ThumbnailURL
+	}
+
+	dataComment := entity.CommentCoreToCommentModel(input)
+	dataComment.Id = newUUID
+
+	err := taskRepo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&dataComment).Error; err != nil {
+			return err
+		}
+
+		return markCommentsRead(tx, input.Type, input.SubmissionId, input.UserId, dataComment.CreatedAt)
+	})
+	if err != nil {
+		storage.DeleteImage(context.Background(), taskRepo.storage, stored)
+		return entity.CommentCore{}, err
+	}
+
+	return entity.CommentModelToCommentCore(dataComment), nil
+}
+
+// MarkCommentsRead implements entity.TaskDataInterface.
+func (taskRepo *TaskRepository) MarkCommentsRead(kind string, id string, userId string, at time.Time) error {
+	return markCommentsRead(taskRepo.db, kind, id, userId, at)
+}
+
+func markCommentsRead(tx *gorm.DB, kind string, id string, userId string, at time.Time) error {
+	read := model.SubmissionCommentRead{
+		SubmissionId: id,
+		Type:         kind,
+		UserId:       userId,
+		ReadAt:       at,
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "submission_id"}, {Name: "type"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"read_at"}),
+	}).Create(&read).Error
+}
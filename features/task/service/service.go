@@ -2,18 +2,32 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"mime/multipart"
+	"strings"
 	"time"
+	calendar "tugaskita/features/calendar/entity"
 	"tugaskita/features/task/entity"
+	"tugaskita/utils/event"
+	"tugaskita/utils/imageproc"
+	"tugaskita/utils/prayertime"
+	"tugaskita/utils/recurrence"
+	"tugaskita/utils/review"
 )
 
 type taskService struct {
-	TaskRepo entity.TaskDataInterface
+	TaskRepo   entity.TaskDataInterface
+	CalendarUC calendar.CalendarUseCaseInterface
+	Prayer     *prayertime.Calculator
 }
 
-func NewTaskService(taskRepo entity.TaskDataInterface) entity.TaskUseCaseInterface {
+// NewTaskService returns the task usecase, prayer may be nil in which case
+// prayer tasks are open the whole day.
+func NewTaskService(taskRepo entity.TaskDataInterface, calendarUC calendar.CalendarUseCaseInterface, prayer *prayertime.Calculator) entity.TaskUseCaseInterface {
 	return &taskService{
-		TaskRepo: taskRepo,
+		TaskRepo:   taskRepo,
+		CalendarUC: calendarUC,
+		Prayer:     prayer,
 	}
 }
 
@@ -65,11 +79,16 @@ func (taskUC *taskService) DeleteTask(taskId string) error {
 		return errors.New("insert task id")
 	}
 
-	_, err := taskUC.TaskRepo.FindById(taskId)
+	task, err := taskUC.TaskRepo.FindById(taskId)
 	if err != nil {
 		return errors.New("task not found")
 	}
 
+	//a deleted occurrence would be generated again, cancel it instead
+	if task.SeriesId != "" {
+		return taskUC.TaskRepo.CancelTask(taskId)
+	}
+
 	errDelete := taskUC.TaskRepo.DeleteTask(taskId)
 	if errDelete != nil {
 		return errors.New("can't delete task")
@@ -136,6 +155,16 @@ func (taskUC *taskService) UpdateTask(taskId string, data entity.TaskCore) error
 		return errors.New("point must be more than 0")
 	}
 
+	task, errTask := taskUC.TaskRepo.FindById(taskId)
+	if errTask != nil {
+		return errors.New("task not found")
+	}
+
+	//an occurrence edited on its own no longer follows its series
+	if task.SeriesId != "" {
+		data.Detached = true
+	}
+
 	err := taskUC.TaskRepo.UpdateTask(taskId, data)
 	if err != nil {
 		return err
@@ -144,50 +173,172 @@ func (taskUC *taskService) UpdateTask(taskId string, data entity.TaskCore) error
 	return nil
 }
 
-// UpdateTaskStatus implements entity.TaskUseCaseInterface.
-func (taskUC *taskService) UpdateTaskStatus(taskId string, data entity.UserTaskUploadCore) error {
-	if data.Status == "" {
-		return errors.New("status can't be empty")
+func validateTaskSeries(data entity.TaskSeriesCore) error {
+	if data.Title == "" || data.Description == "" {
+		return errors.New("title and description can't empty")
 	}
 
-	taskData, errData := taskUC.TaskRepo.FindUserTaskById(taskId)
-	if errData != nil {
-		return errors.New("task not found")
+	if data.Point <= 0 {
+		return errors.New("point must be more than 0")
 	}
 
-	if data.Status == taskData.Status {
-		return errors.New("you already updated this task to " + data.Status)
+	if _, err := recurrence.Parse(data.Rule); err != nil {
+		return errors.New("invalid rule: " + err.Error())
 	}
 
-	err := taskUC.TaskRepo.UpdateTaskStatus(taskId, data)
-	if err != nil {
-		return err
+	if _, err := time.Parse("2006-01-02", data.StartDate); err != nil {
+		return errors.New("start date must be in 'yyyy-mm-dd' format")
+	}
+
+	if data.DurationDays < 1 {
+		return errors.New("duration days must be at least 1")
 	}
 
 	return nil
 }
 
-// UpdateTaskReqStatus implements entity.TaskUseCaseInterface.
-func (taskUC *taskService) UpdateTaskReqStatus(id string, data entity.UserTaskSubmissionCore) error {
-	if data.Status == "" {
-		return errors.New("status can't be empty")
+// generateSeries materializes the occurrences of a series from today up
+// to until and returns how many were created.
+func (taskUC *taskService) generateSeries(series entity.TaskSeriesCore, until time.Time) (int, error) {
+	rule, err := recurrence.Parse(series.Rule)
+	if err != nil {
+		return 0, err
 	}
 
-	taskData, errData := taskUC.TaskRepo.FindUserTaskReqById(id)
-	if errData != nil {
-		return errors.New("request task not found")
+	start, err := time.Parse("2006-01-02", series.StartDate)
+	if err != nil {
+		return 0, err
+	}
+
+	today := time.Now().Format("2006-01-02")
+	from, _ := time.Parse("2006-01-02", today)
+	if start.After(from) {
+		from = start
 	}
 
-	if data.Status == taskData.Status {
-		return errors.New("you already updated this task to " + data.Status)
+	dates := []string{}
+	for _, v := range rule.Between(start, from, until) {
+		dates = append(dates, v.Format("2006-01-02"))
 	}
 
-	err := taskUC.TaskRepo.UpdateTaskReqStatus(id, data)
+	return taskUC.TaskRepo.GenerateTaskSeries(series.Id.String(), dates, until.Format("2006-01-02"))
+}
+
+func seriesHorizon() time.Time {
+	return time.Now().AddDate(0, 0, entity.TaskSeriesHorizonDays)
+}
+
+// CreateTaskSeries implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) CreateTaskSeries(data entity.TaskSeriesCore) (entity.TaskSeriesCore, error) {
+	if data.DurationDays == 0 {
+		data.DurationDays = 1
+	}
+
+	errValidate := validateTaskSeries(data)
+	if errValidate != nil {
+		return entity.TaskSeriesCore{}, errValidate
+	}
+
+	if data.StartDate < time.Now().Format("2006-01-02") {
+		return entity.TaskSeriesCore{}, errors.New("please choose at least today")
+	}
+
+	series, err := taskUC.TaskRepo.CreateTaskSeries(data)
+	if err != nil {
+		return entity.TaskSeriesCore{}, err
+	}
+
+	_, errGenerate := taskUC.generateSeries(series, seriesHorizon())
+	if errGenerate != nil {
+		return entity.TaskSeriesCore{}, errGenerate
+	}
+
+	return series, nil
+}
+
+// FindAllTaskSeries implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) FindAllTaskSeries() ([]entity.TaskSeriesCore, error) {
+	data, err := taskUC.TaskRepo.FindAllTaskSeries()
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindTaskSeriesById implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) FindTaskSeriesById(id string) (entity.TaskSeriesCore, []entity.TaskCore, error) {
+	series, err := taskUC.TaskRepo.FindTaskSeriesById(id)
+	if err != nil {
+		return entity.TaskSeriesCore{}, nil, errors.New("task series not found")
+	}
+
+	occurrences, err := taskUC.TaskRepo.FindTaskSeriesOccurrences(id)
+	if err != nil {
+		return entity.TaskSeriesCore{}, nil, errors.New("error get data")
+	}
+
+	return series, occurrences, nil
+}
+
+// UpdateTaskSeries implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) UpdateTaskSeries(id string, data entity.TaskSeriesCore) error {
+	current, errSeries := taskUC.TaskRepo.FindTaskSeriesById(id)
+	if errSeries != nil {
+		return errors.New("task series not found")
+	}
+
+	if current.Status != "Active" {
+		return errors.New("task series is cancelled")
+	}
+
+	if data.DurationDays == 0 {
+		data.DurationDays = 1
+	}
+
+	errValidate := validateTaskSeries(data)
+	if errValidate != nil {
+		return errValidate
+	}
+
+	err := taskUC.TaskRepo.UpdateTaskSeries(id, data, time.Now().Format("2006-01-02"))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	series, errSeries := taskUC.TaskRepo.FindTaskSeriesById(id)
+	if errSeries != nil {
+		return errSeries
+	}
+
+	_, errGenerate := taskUC.generateSeries(series, seriesHorizon())
+	return errGenerate
+}
+
+// CancelTaskSeries implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) CancelTaskSeries(id string) error {
+	return taskUC.TaskRepo.CancelTaskSeries(id, time.Now().Format("2006-01-02"))
+}
+
+// GenerateTaskSeries implements entity.TaskUseCaseInterface. It returns
+// how many occurrences were created.
+func (taskUC *taskService) GenerateTaskSeries(until time.Time) (int, error) {
+	series, err := taskUC.TaskRepo.FindActiveTaskSeries()
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var errs []error
+	for _, v := range series {
+		count, errGenerate := taskUC.generateSeries(v, until)
+		if errGenerate != nil {
+			errs = append(errs, fmt.Errorf("series %s: %w", v.Id, errGenerate))
+		}
+		created += count
+	}
+
+	return created, errors.Join(errs...)
 }
 
 // FindAllClaimedTask implements entity.TaskUseCaseInterface.
@@ -222,24 +373,43 @@ func (taskUC *taskService) FindTasksNotClaimedByUser(userId string) ([]entity.Ta
 
 // UploadTask implements entity.TaskUseCaseInterface.
 func (taskUC *taskService) UploadTask(data entity.UserTaskUploadCore, image *multipart.FileHeader) error {
-	_, errTask := taskUC.TaskRepo.FindById(data.TaskId)
+	task, errTask := taskUC.TaskRepo.FindById(data.TaskId)
 	if errTask != nil {
 		return errors.New("task not found")
 	}
 
+	if task.Status == "Cancelled" {
+		return errors.New("task is cancelled")
+	}
+
 	if data.Description == "" {
 		return errors.New("description can't empty")
 	}
 
-	if image != nil && image.Size > 10*1024*1024 {
-		return errors.New("image file size should be less than 10 MB")
+	if err := imageproc.Check(image); err != nil {
+		return err
 	}
 
-	err := taskUC.TaskRepo.UploadTask(data, image)
+	id, err := taskUC.TaskRepo.UploadTask(data, image)
 	if err != nil {
+		if errors.Is(err, imageproc.ErrInvalid) {
+			return err
+		}
 		return errors.New("failed upload task")
 	}
 
+	event.Publish(event.Event{
+		Type:   event.SubmissionCreated,
+		UserId: data.UserId,
+		Data: event.Submission{
+			Kind:   entity.KindTask,
+			Id:     id,
+			Title:  task.Title,
+			Status: review.Pending,
+			Point:  task.Point,
+		},
+	})
+
 	return nil
 }
 
@@ -283,15 +453,30 @@ func (taskUC *taskService) UploadTaskRequest(input entity.UserTaskSubmissionCore
 		return errors.New("point can't less then 0")
 	}
 
-	if image != nil && image.Size > 10*1024*1024 {
-		return errors.New("image file size should be less than 10 MB")
+	if err := imageproc.Check(image); err != nil {
+		return err
 	}
 
-	err := taskUC.TaskRepo.UploadTaskRequest(input, image)
+	id, err := taskUC.TaskRepo.UploadTaskRequest(input, image)
 	if err != nil {
+		if errors.Is(err, imageproc.ErrInvalid) {
+			return err
+		}
 		return errors.New("failed upload request task")
 	}
 
+	event.Publish(event.Event{
+		Type:   event.SubmissionCreated,
+		UserId: input.UserId,
+		Data: event.Submission{
+			Kind:   entity.KindSubmission,
+			Id:     id,
+			Title:  input.Title,
+			Status: review.Pending,
+			Point:  input.Point,
+		},
+	})
+
 	return nil
 }
 
@@ -328,157 +513,297 @@ func (taskUC *taskService) CreateTaskReligion(input entity.ReligionTaskCore) err
 		return errors.New("religion can't empty")
 	}
 
-	// Kondisi khusus untuk agama Islam
-	if input.Religion == "Islam" && input.Title == "" {
-		//cek apakah hari ini sudah upload atau belum
-		existingTasks, err := taskUC.TaskRepo.FindTaskByDateAndReligion(currentTime.Format(layout), "Islam")
+	//without a title the tasks come from the religion's templates
+	if input.Title == "" {
+		today, _ := time.Parse(layout, time.Now().Format(layout))
+		created, err := taskUC.GenerateReligionTasks(input.Religion, today, today.AddDate(0, 0, entity.ReligionTaskHorizonDays))
 		if err != nil {
 			return err
 		}
 
-		if len(existingTasks) > 0 {
-			return errors.New("shalat 5 waktu sudah dibuat untuk hari ini")
-		}
-
-		dayData := time.Now().Weekday().String()
-		println(dayData)
-
-		if dayData == "Friday" {
-			//post shalat 5 waktu
-			prayers := []string{"Subuh", "Jum'at", "Ashar", "Maghrib", "Isya"}
-			for _, prayer := range prayers {
-				task := entity.ReligionTaskCore{
-					Title:       prayer,
-					Point:       250,
-					Religion:    input.Religion,
-					Start_date:  currentTime.Format(layout),
-					End_date:    currentTime.Format(layout),
-					Description: "Tugas Shalat " + prayer,
-				}
-				err := taskUC.TaskRepo.CreateTaskReligion(task)
-				if err != nil {
-					return err
-				}
-			}
+		if created == 0 {
+			return errors.New("religion tasks for " + input.Religion + " are already up to date")
 		}
+	} else {
 
-		//post shalat 5 waktu
-		prayers := []string{"Subuh", "Dzuhur", "Ashar", "Maghrib", "Isya"}
-		for _, prayer := range prayers {
-			task := entity.ReligionTaskCore{
-				Title:       prayer,
-				Point:       250,
-				Religion:    input.Religion,
-				Start_date:  currentTime.Format(layout),
-				End_date:    currentTime.Format(layout),
-				Description: "Tugas Shalat " + prayer,
-			}
-			err := taskUC.TaskRepo.CreateTaskReligion(task)
-			if err != nil {
-				return err
-			}
+		if input.Religion == "" || input.Title == "" {
+			return errors.New("religion and title can't empty")
 		}
-	} else if input.Religion == "Kristen" && input.Title == "" {
-		currentWeekday := time.Now().Weekday()
 
-		// Hitung jarak hari ke hari Minggu berikutnya
-		daysUntilSunday := (7 - int(currentWeekday))
+		start, errStart := time.Parse(layout, input.Start_date)
 
-		// Jika hari ini bukan hari Minggu, tambahkan jarak hari ke tanggal saat ini
-		if daysUntilSunday != 0 {
-			currentTime = currentTime.AddDate(0, 0, daysUntilSunday)
+		if errStart != nil {
+			return errors.New("start date must be in 'yyyy-mm-dd' format")
+		}
+		if start.Before(currentTime) {
+			return errors.New("please choose at least today")
 		}
 
-		//cek apakah hari ini sudah upload atau belum
-		existingTasks, errCheck := taskUC.TaskRepo.FindTaskByDateAndReligionNon(currentTime.Format(layout), "Kristen")
-		if errCheck != nil {
-			return errCheck
+		end, errEnd := time.Parse(layout, input.End_date)
+		if errEnd != nil {
+			return errors.New("end date must be in 'yyyy-mm-dd' format")
 		}
 
-		if len(existingTasks) > 0 {
-			return errors.New("ibadah minggu telah ditambahkan pada minggu ini")
+		if end.Before(start) {
+			return errors.New("end date must be after start date")
 		}
 
-		task := entity.ReligionTaskCore{
-			Title:       "Ibadah Minggu",
-			Point:       300,
-			Religion:    input.Religion,
-			Start_date:  currentTime.Format(layout),
-			End_date:    currentTime.Format(layout),
-			Description: "Laksanakan ibadah minggu ke gereja",
+		if end.Equal(start) {
+			return errors.New("end date must be different from start date")
 		}
-		err := taskUC.TaskRepo.CreateTaskReligion(task)
+
+		err := taskUC.TaskRepo.CreateTaskReligion(input)
 		if err != nil {
 			return err
 		}
-	} else if input.Religion == "Katolik" && input.Title == "" {
-		currentWeekday := time.Now().Weekday()
+	}
 
-		// Hitung jarak hari ke hari Minggu berikutnya
-		daysUntilSunday := (7 - int(currentWeekday))
+	return nil
+}
 
-		// Jika hari ini bukan hari Minggu, tambahkan jarak hari ke tanggal saat ini
-		if daysUntilSunday != 0 {
-			currentTime = currentTime.AddDate(0, 0, daysUntilSunday)
-		}
+func validateReligionTemplate(data entity.ReligionTemplateCore) error {
+	if data.Religion == "" || data.Title == "" {
+		return errors.New("religion and title can't empty")
+	}
 
-		//cek apakah hari ini sudah upload atau belum
-		existingTasks, errCheck := taskUC.TaskRepo.FindTaskByDateAndReligionNon(currentTime.Format(layout), "Katolik")
-		if errCheck != nil {
-			return errCheck
-		}
+	if len(data.Religion) > 25 {
+		return errors.New("religion must be at most 25 characters")
+	}
 
-		if len(existingTasks) > 0 {
-			return errors.New("ibadah minggu telah ditambahkan pada minggu ini")
-		}
+	if data.Point <= 0 {
+		return errors.New("point must be more than 0")
+	}
+
+	if _, err := recurrence.Parse(data.Rule); err != nil {
+		return errors.New("invalid rule: " + err.Error())
+	}
+
+	if _, err := time.Parse("2006-01-02", data.StartDate); err != nil {
+		return errors.New("start date must be in 'yyyy-mm-dd' format")
+	}
 
-		task := entity.ReligionTaskCore{
-			Title:       "Ibadah Minggu",
-			Point:       300,
-			Religion:    input.Religion,
-			Start_date:  currentTime.Format(layout),
-			End_date:    currentTime.Format(layout),
-			Description: "Laksanakan ibadah minggu ke gereja",
+	if data.DurationDays < 0 {
+		return errors.New("duration days can't be negative")
+	}
+
+	if data.Holiday != "" && (len(data.Holiday) > 50 || strings.ToLower(data.Holiday) != data.Holiday) {
+		return errors.New("holiday must be the key of a calendar holiday, e.g. 'ramadhan'")
+	}
+
+	if data.PrayerWindow != "" {
+		valid := false
+		for _, v := range prayertime.Prayers {
+			if data.PrayerWindow == v {
+				valid = true
+			}
 		}
-		err := taskUC.TaskRepo.CreateTaskReligion(task)
-		if err != nil {
-			return err
+		if !valid {
+			return errors.New("prayer window must be one of fajr, dhuhr, asr, maghrib or isha")
 		}
-	} else {
+	}
 
-		if input.Religion == "" || input.Title == "" {
-			return errors.New("religion and title can't empty")
+	return nil
+}
+
+// CreateReligionTemplate implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) CreateReligionTemplate(data entity.ReligionTemplateCore) (entity.ReligionTemplateCore, error) {
+	if data.StartDate == "" {
+		data.StartDate = time.Now().Format("2006-01-02")
+	}
+	if data.Holiday != "" && data.Rule == "" {
+		data.Rule = "FREQ=DAILY"
+	}
+
+	errValidate := validateReligionTemplate(data)
+	if errValidate != nil {
+		return entity.ReligionTemplateCore{}, errValidate
+	}
+
+	return taskUC.TaskRepo.CreateReligionTemplate(data)
+}
+
+// FindAllReligionTemplate implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) FindAllReligionTemplate(religion string) ([]entity.ReligionTemplateCore, error) {
+	data, err := taskUC.TaskRepo.FindAllReligionTemplate(religion)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindReligionTemplateById implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) FindReligionTemplateById(id string) (entity.ReligionTemplateCore, error) {
+	data, err := taskUC.TaskRepo.FindReligionTemplateById(id)
+	if err != nil {
+		return entity.ReligionTemplateCore{}, errors.New("template not found")
+	}
+
+	return data, nil
+}
+
+// UpdateReligionTemplate implements entity.TaskUseCaseInterface. Tasks
+// already generated keep their values.
+func (taskUC *taskService) UpdateReligionTemplate(id string, data entity.ReligionTemplateCore) error {
+	current, err := taskUC.TaskRepo.FindReligionTemplateById(id)
+	if err != nil {
+		return errors.New("template not found")
+	}
+
+	if data.StartDate == "" {
+		data.StartDate = current.StartDate
+	}
+	if data.Holiday != "" && data.Rule == "" {
+		data.Rule = "FREQ=DAILY"
+	}
+
+	errValidate := validateReligionTemplate(data)
+	if errValidate != nil {
+		return errValidate
+	}
+
+	return taskUC.TaskRepo.UpdateReligionTemplate(id, data)
+}
+
+// DeleteReligionTemplate implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) DeleteReligionTemplate(id string) error {
+	return taskUC.TaskRepo.DeleteReligionTemplate(id)
+}
+
+// holidayDays returns the days between from and until that belong to the
+// holiday with the given key.
+func (taskUC *taskService) holidayDays(key string, from time.Time, until time.Time) (map[string]bool, error) {
+	holidays, err := taskUC.CalendarUC.FindAllHoliday(calendar.HolidayFilter{
+		Key:  key,
+		From: from.Format("2006-01-02"),
+		To:   until.Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	days := map[string]bool{}
+	for _, v := range holidays {
+		start, errStart := time.Parse("2006-01-02", v.StartDate)
+		end, errEnd := time.Parse("2006-01-02", v.EndDate)
+		if errStart != nil || errEnd != nil {
+			continue
 		}
 
-		start, errStart := time.Parse(layout, input.Start_date)
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			days[d.Format("2006-01-02")] = true
+		}
+	}
 
-		if errStart != nil {
-			return errors.New("start date must be in 'yyyy-mm-dd' format")
+	return days, nil
+}
+
+// religionOccurrence builds the task of template for day. Templates with a
+// prayer window get the time of that prayer when prayer times are
+// configured.
+func (taskUC *taskService) religionOccurrence(template entity.ReligionTemplateCore, day time.Time) (entity.ReligionTaskCore, error) {
+	date := day.Format("2006-01-02")
+	task := entity.ReligionTaskCore{
+		Title:          template.Title,
+		Description:    template.Description,
+		Religion:       template.Religion,
+		Point:          template.Point,
+		Start_date:     date,
+		End_date:       day.AddDate(0, 0, template.DurationDays).Format("2006-01-02"),
+		OccurrenceDate: date,
+	}
+
+	if template.PrayerWindow == "" || taskUC.Prayer == nil {
+		return task, nil
+	}
+
+	local := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, taskUC.Prayer.Location)
+	startAt, endAt, err := taskUC.Prayer.Window(template.PrayerWindow, local)
+	if err != nil {
+		return entity.ReligionTaskCore{}, err
+	}
+
+	task.End_date = endAt.Format("2006-01-02")
+	task.StartAt = &startAt
+	task.EndAt = &endAt
+	return task, nil
+}
+
+// GenerateReligionTasks implements entity.TaskUseCaseInterface. It creates
+// the tasks of every active template of religion, or of all religions when
+// empty, for the days in [from, until] and returns how many were created.
+func (taskUC *taskService) GenerateReligionTasks(religion string, from time.Time, until time.Time) (int, error) {
+	templates, err := taskUC.TaskRepo.FindActiveReligionTemplate(religion)
+	if err != nil {
+		return 0, err
+	}
+
+	if religion != "" && len(templates) == 0 {
+		return 0, errors.New("no active schedule for religion " + religion)
+	}
+
+	created := 0
+	religions := map[string]int{}
+	var errs []error
+	for _, v := range templates {
+		rule, errRule := recurrence.Parse(v.Rule)
+		if errRule != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", v.Id, errRule))
+			continue
 		}
-		if start.Before(currentTime) {
-			return errors.New("please choose at least today")
+
+		start, errStart := time.Parse("2006-01-02", v.StartDate)
+		if errStart != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", v.Id, errStart))
+			continue
 		}
 
-		end, errEnd := time.Parse(layout, input.End_date)
-		if errEnd != nil {
-			return errors.New("end date must be in 'yyyy-mm-dd' format")
+		days := rule.Between(start, from, until)
+		if v.Holiday != "" {
+			holidayDays, errHoliday := taskUC.holidayDays(v.Holiday, from, until)
+			if errHoliday != nil {
+				errs = append(errs, fmt.Errorf("template %s: %w", v.Id, errHoliday))
+				continue
+			}
+
+			filtered := []time.Time{}
+			for _, d := range days {
+				if holidayDays[d.Format("2006-01-02")] {
+					filtered = append(filtered, d)
+				}
+			}
+			days = filtered
 		}
 
-		if end.Before(start) {
-			return errors.New("end date must be after start date")
+		tasks := []entity.ReligionTaskCore{}
+		for _, d := range days {
+			task, errTask := taskUC.religionOccurrence(v, d)
+			if errTask != nil {
+				errs = append(errs, fmt.Errorf("template %s: %w", v.Id, errTask))
+				break
+			}
+			tasks = append(tasks, task)
 		}
 
-		if end.Equal(start) {
-			return errors.New("end date must be different from start date")
+		count, errGenerate := taskUC.TaskRepo.GenerateReligionTasks(v.Id.String(), tasks)
+		if errGenerate != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", v.Id, errGenerate))
+			continue
 		}
+		created += count
+		religions[v.Religion] += count
+	}
 
-		err := taskUC.TaskRepo.CreateTaskReligion(input)
-		if err != nil {
-			return err
+	for religion, count := range religions {
+		if count > 0 {
+			event.Publish(event.Event{
+				Type: event.ReligionTasksGenerated,
+				Data: event.ReligionTasks{Religion: religion, Count: count},
+			})
 		}
 	}
 
-	return nil
+	return created, errors.Join(errs...)
 }
 
 // DeleteTaskReligion implements entity.TaskUseCaseInterface.
@@ -564,24 +889,52 @@ func (taskUC *taskService) FindAllReligionTaskHistory(userId string) ([]entity.U
 
 // UploadTaskReligion implements entity.TaskUseCaseInterface.
 func (taskUC *taskService) UploadTaskReligion(input entity.UserReligionTaskUploadCore, image *multipart.FileHeader) error {
-	_, errTask := taskUC.TaskRepo.FindByIdReligionTask(input.TaskId)
+	task, errTask := taskUC.TaskRepo.FindByIdReligionTask(input.TaskId)
 	if errTask != nil {
 		return errors.New("religion task not found")
 	}
 
+	loc := time.Local
+	if taskUC.Prayer != nil {
+		loc = taskUC.Prayer.Location
+	}
+
+	now := time.Now()
+	if task.StartAt != nil && now.Before(*task.StartAt) {
+		return errors.New("task can only be submitted from " + task.StartAt.In(loc).Format("2006-01-02 15:04"))
+	}
+	if task.EndAt != nil && now.After(*task.EndAt) {
+		return errors.New("task could only be submitted until " + task.EndAt.In(loc).Format("2006-01-02 15:04"))
+	}
+
 	if input.Description == "" {
 		return errors.New("description can't empty")
 	}
 
-	if image != nil && image.Size > 10*1024*1024 {
-		return errors.New("image file size should be less than 10 MB")
+	if err := imageproc.Check(image); err != nil {
+		return err
 	}
 
-	err := taskUC.TaskRepo.UploadTaskReligion(input, image)
+	id, err := taskUC.TaskRepo.UploadTaskReligion(input, image)
 	if err != nil {
+		if errors.Is(err, imageproc.ErrInvalid) {
+			return err
+		}
 		return errors.New("failed upload religion task")
 	}
 
+	event.Publish(event.Event{
+		Type:   event.SubmissionCreated,
+		UserId: input.UserId,
+		Data: event.Submission{
+			Kind:   entity.KindReligion,
+			Id:     id,
+			Title:  task.Title,
+			Status: review.Pending,
+			Point:  task.Point,
+		},
+	})
+
 	return nil
 }
 
@@ -605,29 +958,6 @@ func (taskUC *taskService) FindSpecificUserReligionTaskUpload(id string) (entity
 	return task, nil
 }
 
-// UpdateReligionTaskStatus implements entity.TaskUseCaseInterface.
-func (taskUC *taskService) UpdateReligionTaskStatus(id string, data entity.UserReligionTaskUploadCore) error {
-	if data.Status == "" {
-		return errors.New("status can't be empty")
-	}
-
-	taskData, errData := taskUC.TaskRepo.FindSpecificUserReligionTaskUpload(data.Id.String())
-	if errData != nil {
-		return errors.New("religion task not found")
-	}
-
-	if data.Status == taskData.Status {
-		return errors.New("you already updated this task to " + data.Status)
-	}
-
-	err := taskUC.TaskRepo.UpdateReligionTaskStatus(id, data)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // FindAllReligionTaskRequestHistory implements entity.TaskUseCaseInterface.
 func (taskUC *taskService) FindAllReligionTaskRequestHistory(userId string) ([]entity.UserReligionReqTaskCore, error) {
 	data, err := taskUC.TaskRepo.FindAllReligionTaskRequestHistory(userId)
@@ -658,15 +988,30 @@ func (taskUC *taskService) UploadReligionTaskRequest(input entity.UserReligionRe
 		return errors.New("point can't less then 0")
 	}
 
-	if image != nil && image.Size > 10*1024*1024 {
-		return errors.New("image file size should be less than 10 MB")
+	if err := imageproc.Check(image); err != nil {
+		return err
 	}
 
-	err := taskUC.TaskRepo.UploadReligionTaskRequest(input, image)
+	id, err := taskUC.TaskRepo.UploadReligionTaskRequest(input, image)
 	if err != nil {
+		if errors.Is(err, imageproc.ErrInvalid) {
+			return err
+		}
 		return errors.New("failed upload religion request task")
 	}
 
+	event.Publish(event.Event{
+		Type:   event.SubmissionCreated,
+		UserId: input.UserId,
+		Data: event.Submission{
+			Kind:   entity.KindReligionRequest,
+			Id:     id,
+			Title:  input.Title,
+			Status: review.Pending,
+			Point:  input.Point,
+		},
+	})
+
 	return nil
 }
 
@@ -680,25 +1025,316 @@ func (taskUC *taskService) GetAllUserReligionTaskRequest() ([]entity.UserReligio
 	return userTask, nil
 }
 
-// UpdateTaskReligionReqStatus implements entity.TaskUseCaseInterface.
-func (taskUC *taskService) UpdateTaskReligionReqStatus(id string, data entity.UserReligionReqTaskCore) error {
-	if data.Status == "" {
-		return errors.New("status can't be empty")
+// ReviewSubmission implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) ReviewSubmission(kind string, id string, data entity.ReviewCore) error {
+	submission, errData := taskUC.TaskRepo.FindSubmission(kind, id)
+	if errData != nil {
+		return errors.New("submission not found")
+	}
+
+	if err := review.Review(submission.Status, data.Status, data.Message); err != nil {
+		return err
+	}
+
+	if data.Point < 0 {
+		return errors.New("point can't less then 0")
+	}
+
+	if err := taskUC.TaskRepo.ReviewSubmission(submission, data); err != nil {
+		return err
+	}
+
+	point := submission.Point
+	if data.Point > 0 && kind != entity.KindTask && kind != entity.KindReligion {
+		point = data.Point
+	}
+	event.Publish(event.Event{
+		Type:   event.SubmissionReviewed,
+		UserId: submission.UserId,
+		Data: event.Submission{
+			Kind:    kind,
+			Id:      id,
+			Title:   submission.Title,
+			Status:  data.Status,
+			Message: data.Message,
+			Point:   point,
+		},
+	})
+
+	if data.Status == review.Approved {
+		event.Publish(event.Event{
+			Type:   event.PointsChanged,
+			UserId: submission.UserId,
+			Data:   event.Points{Source: kind},
+		})
+	}
+
+	return nil
+}
+
+// ResubmitSubmission implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) ResubmitSubmission(kind string, id string, userId string, description string, image *multipart.FileHeader) error {
+	submission, errData := taskUC.TaskRepo.FindSubmission(kind, id)
+	if errData != nil || submission.UserId != userId {
+		return errors.New("submission not found")
+	}
+
+	if err := review.Resubmit(submission.Status); err != nil {
+		return err
+	}
+
+	if description == "" {
+		return errors.New("description can't empty")
+	}
+
+	if err := imageproc.Check(image); err != nil {
+		return err
+	}
+
+	err := taskUC.TaskRepo.ResubmitSubmission(submission, description, image)
+	if err != nil {
+		if errors.Is(err, imageproc.ErrInvalid) || errors.Is(err, review.ErrConflict) {
+			return err
+		}
+		return errors.New("failed send submission again")
 	}
 
-	taskData, errData := taskUC.TaskRepo.FindSpesificReligionTaskRequest(id)
+	event.Publish(event.Event{
+		Type:   event.SubmissionResubmitted,
+		UserId: submission.UserId,
+		Data: event.Submission{
+			Kind:   kind,
+			Id:     id,
+			Title:  submission.Title,
+			Status: review.Resubmitted,
+			Point:  submission.Point,
+		},
+	})
+
+	return nil
+}
+
+// FindSubmissionRevisions implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) FindSubmissionRevisions(kind string, id string) (entity.SubmissionCore, []entity.RevisionCore, error) {
+	submission, errData := taskUC.TaskRepo.FindSubmission(kind, id)
 	if errData != nil {
-		return errors.New("religion request task not found")
+		return entity.SubmissionCore{}, nil, errors.New("submission not found")
+	}
+
+	revisions, err := taskUC.TaskRepo.FindRevisions(kind, id)
+	if err != nil {
+		return entity.SubmissionCore{}, nil, errors.New("error get submission revisions")
+	}
+
+	return submission, revisions, nil
+}
+
+func validateReviewQueue(filter *entity.ReviewQueueFilter) error {
+	if filter.Type != "" && !validKind(filter.Type) {
+		return errors.New("type must be " + strings.Join(entity.SubmissionKinds, ", "))
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+	return nil
+}
+
+func validKind(kind string) bool {
+	for _, v := range entity.SubmissionKinds {
+		if v == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// FindReviewQueue implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) FindReviewQueue(filter entity.ReviewQueueFilter) ([]entity.ReviewQueueCore, error) {
+	if err := validateReviewQueue(&filter); err != nil {
+		return nil, err
+	}
+
+	queue, err := taskUC.TaskRepo.FindReviewQueue(filter)
+	if err != nil {
+		return nil, errors.New("error get review queue")
+	}
+
+	return queue, nil
+}
+
+// ClaimReviewQueue implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) ClaimReviewQueue(filter entity.ReviewQueueFilter) ([]entity.ReviewQueueCore, error) {
+	if err := validateReviewQueue(&filter); err != nil {
+		return nil, err
+	}
+
+	until := time.Now().Add(entity.ReviewClaimLease)
+	claimed := []entity.ReviewQueueCore{}
+	seen := map[string]bool{}
+
+	// another reviewer can claim a submission after the queue was read,
+	// it is read again to replace the ones lost that way
+	for round := 0; round < 3 && len(claimed) < filter.Limit; round++ {
+		queue, err := taskUC.TaskRepo.FindReviewQueue(filter)
+		if err != nil {
+			return nil, errors.New("error get review queue")
+		}
+
+		lost := false
+		for _, v := range queue {
+			if len(claimed) == filter.Limit {
+				break
+			}
+			if seen[v.Type+v.Id] {
+				continue
+			}
+
+			ok, err := taskUC.TaskRepo.ClaimSubmission(v.Type, v.Id, filter.ReviewerId, until)
+			if err != nil {
+				return nil, errors.New("error claim submission")
+			}
+			if !ok {
+				lost = true
+				continue
+			}
+
+			v.ClaimedBy, v.ClaimExpiredAt = filter.ReviewerId, &until
+			claimed = append(claimed, v)
+			seen[v.Type+v.Id] = true
+		}
+
+		if !lost {
+			break
+		}
+	}
+
+	return claimed, nil
+}
+
+// ReleaseReviewClaims implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) ReleaseReviewClaims(reviewerId string, items []entity.SubmissionRef) error {
+	for _, v := range items {
+		if !validKind(v.Type) {
+			return errors.New("type must be " + strings.Join(entity.SubmissionKinds, ", "))
+		}
+	}
+
+	err := taskUC.TaskRepo.ReleaseReviewClaims(reviewerId, items)
+	if err != nil {
+		return errors.New("error release review claims")
+	}
+
+	return nil
+}
+
+// BulkReviewSubmission implements entity.TaskUseCaseInterface. Every
+// submission is reviewed on its own, the ones that fail don't stop the
+// others.
+func (taskUC *taskService) BulkReviewSubmission(items []entity.SubmissionRef, data entity.ReviewCore) ([]entity.ReviewResultCore, error) {
+	if len(items) == 0 {
+		return nil, errors.New("items can't be empty")
+	}
+
+	if len(items) > 100 {
+		return nil, errors.New("at most 100 submissions can be reviewed at once")
+	}
+
+	results := make([]entity.ReviewResultCore, len(items))
+	for i, v := range items {
+		results[i] = entity.ReviewResultCore{Type: v.Type, Id: v.Id}
+		if err := taskUC.ReviewSubmission(v.Type, v.Id, data); err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+
+	return results, nil
+}
+
+// findCommentedSubmission returns the submission whose thread a user
+// reads or writes, only its student and reviewers can.
+func (taskUC *taskService) findCommentedSubmission(kind string, id string, userId string, reviewer bool) (entity.SubmissionCore, error) {
+	submission, errData := taskUC.TaskRepo.FindSubmission(kind, id)
+	if errData != nil || (submission.UserId != userId && !reviewer) {
+		return entity.SubmissionCore{}, errors.New("submission not found")
+	}
+	return submission, nil
+}
+
+// FindSubmissionComments implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) FindSubmissionComments(kind string, id string, userId string, reviewer bool) (entity.CommentThreadCore, error) {
+	if _, err := taskUC.findCommentedSubmission(kind, id, userId, reviewer); err != nil {
+		return entity.CommentThreadCore{}, err
+	}
+
+	comments, reads, err := taskUC.TaskRepo.FindComments(kind, id)
+	if err != nil {
+		return entity.CommentThreadCore{}, errors.New("error get comments")
+	}
+
+	var readAt time.Time
+	for _, v := range reads {
+		if v.UserId == userId {
+			readAt = v.ReadAt
+		}
+	}
+
+	thread := entity.CommentThreadCore{Comments: comments, Reads: reads}
+	for _, v := range comments {
+		if v.UserId != userId && v.CreatedAt.After(readAt) {
+			thread.Unread++
+		}
+	}
+
+	return thread, nil
+}
+
+// AddSubmissionComment implements entity.TaskUseCaseInterface. A comment
+// needs a body, an image or both.
+func (taskUC *taskService) AddSubmissionComment(kind string, id string, input entity.CommentCore, reviewer bool, image *multipart.FileHeader) (entity.CommentCore, error) {
+	if _, err := taskUC.findCommentedSubmission(kind, id, input.UserId, reviewer); err != nil {
+		return entity.CommentCore{}, err
 	}
 
-	if data.Status == taskData.Status {
-		return errors.New("you already updated this task to " + data.Status)
+	input.Body = strings.TrimSpace(input.Body)
+	if input.Body == "" && image == nil {
+		return entity.CommentCore{}, errors.New("comment can't empty")
 	}
 
-	err := taskUC.TaskRepo.UpdateTaskReligionReqStatus(id, data)
+	if len([]rune(input.Body)) > entity.MaxCommentLength {
+		return entity.CommentCore{}, fmt.Errorf("comment can't be longer than %d characters", entity.MaxCommentLength)
+	}
+
+	if err := imageproc.Check(image); err != nil {
+		return entity.CommentCore{}, err
+	}
+
+	input.SubmissionId = id
+	input.Type = kind
+
+	comment, err := taskUC.TaskRepo.CreateComment(input, image)
 	if err != nil {
+		if errors.Is(err, imageproc.ErrInvalid) {
+			return entity.CommentCore{}, err
+		}
+		return entity.CommentCore{}, errors.New("failed add comment")
+	}
+
+	return comment, nil
+}
+
+// ReadSubmissionComments implements entity.TaskUseCaseInterface.
+func (taskUC *taskService) ReadSubmissionComments(kind string, id string, userId string, reviewer bool) error {
+	if _, err := taskUC.findCommentedSubmission(kind, id, userId, reviewer); err != nil {
 		return err
 	}
 
+	if err := taskUC.TaskRepo.MarkCommentsRead(kind, id, userId, time.Now()); err != nil {
+		return errors.New("failed mark comments as read")
+	}
+
 	return nil
 }
\ No newline at end of file
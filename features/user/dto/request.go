@@ -9,5 +9,28 @@ type UserRequest struct {
 	Religion string `json:"religion" form:"religion"`
 	Email    string `json:"email" form:"email"`
 	Password string `json:"password" form:"password"`
-	Point    string `json:"point" form:"point"`
+}
+
+type PointAdjustmentRequest struct {
+	PointDelta      int    `json:"point_delta"`
+	TotalPointDelta int    `json:"total_point_delta"`
+	Description     string `json:"description"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type VerifyResetCodeRequest struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+type ResetPasswordRequest struct {
+	ResetToken string `json:"reset_token"`
+	Password   string `json:"password"`
 }
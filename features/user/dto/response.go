@@ -6,15 +6,21 @@ type UserResponse struct {
 	Address    string `json:"address"`
 	School     string `json:"school"`
 	Class      string `json:"class"`
+	SchoolId   string `json:"school_id"`
+	ClassId    string `json:"class_id"`
 	Image      string `json:"image"`
+	Thumbnail  string `json:"thumbnail"`
 	Role       string `json:"role"`
 	Religion   string `json:"religion"`
 	Email      string `json:"email"`
-	Point      string `json:"point"`
-	TotalPoint string `json:"total_point"`
+	Point      int    `json:"point"`
+	TotalPoint int    `json:"total_point"`
 }
 
 type UserRankResponse struct {
+	Rank  int    `json:"rank"`
+	Id    string `json:"id"`
 	Name  string `json:"name"`
-	Point string `json:"point"`
+	Class string `json:"class"`
+	Point int    `json:"point"`
 }
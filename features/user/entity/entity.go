@@ -8,23 +8,160 @@ type UserCore struct {
 	Address    string    `json:"address"`
 	School     string    `json:"school"`
 	Class      string    `json:"class"`
+	SchoolId   string    `json:"school_id"`
+	ClassId    string    `json:"class_id"`
 	Image      string    `json:"image"`
+	Thumbnail  string    `json:"thumbnail"`
 	Email      string    `json:"email"`
 	Password   string    `json:"password"`
 	Role       string    `json:"role"`
 	Religion   string    `json:"religion"`
-	Point      string    `json:"point"`
-	TotalPoint string    `gorm:"Varchar(100);not null;default:user" json:"total_point"`
+	Point      int       `json:"point"`
+	TotalPoint int       `json:"total_point"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"update_at"`
 }
 
+// Leaderboard scopes, an empty scope ranks every student.
+const (
+	RankScopeClass  = "class"
+	RankScopeGrade  = "grade"
+	RankScopeSchool = "school"
+)
+
+// RankFilter narrows the leaderboard to a class, a grade of a school or a
+// school. For the grade scope ClassId can stand in for SchoolId and Grade.
+type RankFilter struct {
+	Scope        string
+	ClassId      string
+	SchoolId     string
+	Grade        int
+	AcademicYear string
+}
+
+// Point ledger entry types.
+const (
+	PointTypeTask            = "Task"
+	PointTypeSubmission      = "Submission"
+	PointTypeReligion        = "Religion"
+	PointTypeReligionRequest = "Religion Request"
+	PointTypeReward          = "Reward"
+	PointTypeRewardRefund    = "Reward Refund"
+	PointTypePenalty         = "Penalty"
+	PointTypeReset           = "Reset"
+	PointTypeAdjustment      = "Adjustment"
+)
+
+// UserPointCore is a point ledger entry. Point is the amount shown to the
+// user, PointDelta and TotalPointDelta are the signed changes applied to
+// the balances and the *Balance fields hold the balances after the entry.
 type UserPointCore struct {
-	Id        string    `json:"id"`
-	UserId    string    `json:"user_id"`
-	Type      string    `json:"type"`
-	TaskName  string    `json:"task_name"`
-	Point     int       `json:"point"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"update_at"`
+	Id                string    `json:"id"`
+	UserId            string    `json:"user_id"`
+	Type              string    `json:"type"`
+	TaskName          string    `json:"task_name"`
+	SourceId          string    `json:"source_id"`
+	Point             int       `json:"point"`
+	PointDelta        int       `json:"point_delta"`
+	TotalPointDelta   int       `json:"total_point_delta"`
+	PointBalance      int       `json:"point_balance"`
+	TotalPointBalance int       `json:"total_point_balance"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"update_at"`
+}
+
+// Point reset kinds and what triggered them.
+const (
+	ResetMonthly = "monthly"
+	ResetAnnual  = "annual"
+
+	ResetTriggerSchedule = "schedule"
+	ResetTriggerManual   = "manual"
+)
+
+// ResetPeriod returns the period a reset at t opens, "2006-01" for a
+// monthly reset and "2006" for an annual one. Each period is reset once.
+func ResetPeriod(kind string, t time.Time) string {
+	if kind == ResetAnnual {
+		return t.Format("2006")
+	}
+	return t.Format("2006-01")
+}
+
+type PointResetCore struct {
+	Id           string    `json:"id"`
+	Kind         string    `json:"kind"`
+	Period       string    `json:"period"`
+	Trigger      string    `json:"trigger"`
+	SnapshotId   string    `json:"snapshot_id"`
+	UserCount    int       `json:"user_count"`
+	ClearedPoint int       `json:"cleared_point"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SnapshotManual is the kind of a snapshot taken on demand.
+const SnapshotManual = "manual"
+
+type LeaderboardSnapshotCore struct {
+	Id         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	Period     string    `json:"period"`
+	EntryCount int       `json:"entry_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type LeaderboardEntryCore struct {
+	Rank       int    `json:"rank"`
+	UserId     string `json:"user_id"`
+	Name       string `json:"name"`
+	SchoolId   string `json:"school_id"`
+	ClassId    string `json:"class_id"`
+	Class      string `json:"class"`
+	Point      int    `json:"point"`
+	TotalPoint int    `json:"total_point"`
+}
+
+// RankTrajectoryCore is a user's standing in one archived snapshot.
+type RankTrajectoryCore struct {
+	SnapshotId   string    `json:"snapshot_id"`
+	Kind         string    `json:"kind"`
+	Period       string    `json:"period"`
+	Position     int       `json:"rank"`
+	Participants int       `json:"participants"`
+	Point        int       `json:"point"`
+	TotalPoint   int       `json:"total_point"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PasswordResetCore struct {
+	Id             string
+	UserId         string
+	CodeHash       string
+	ResetTokenHash string
+	Attempts       int
+	ExpiredAt      time.Time
+	VerifiedAt     *time.Time
+	UsedAt         *time.Time
+	CreatedAt      time.Time
+}
+
+// UserImportAccount is an account created by a roster import together with
+// its generated initial password.
+type UserImportAccount struct {
+	Row      int    `json:"row"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type UserImportRowError struct {
+	Row     int    `json:"row"`
+	Email   string `json:"email"`
+	Message string `json:"message"`
+}
+
+type UserImportResult struct {
+	Created []UserImportAccount  `json:"created"`
+	Errors  []UserImportRowError `json:"errors"`
 }
@@ -1,6 +1,11 @@
 package entity
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type UserDataInterface interface {
 	Register(data UserCore, image *multipart.FileHeader) (row int, err error)
@@ -9,35 +14,76 @@ type UserDataInterface interface {
 	ReadAllUser() ([]UserCore, error)
 	ReadSpecificUser(id string) (user UserCore, err error)
 	DeleteUser(id string) (err error)
-	UpdatePoint(id string, data UserCore) error
 
-	GetRankUser() ([]UserCore, error)
+	GetRankUser(filter RankFilter) ([]UserCore, error)
 	ChangePassword(id string, data UserCore) error
 
-	MonthlyResetPoint()(error)
-	AnnualResetPoint()(error)
+	ResetPoint(kind string, period string, trigger string) (PointResetCore, error)
+	FindPointReset(kind string, period string) (PointResetCore, error)
+	FindAllPointReset() ([]PointResetCore, error)
+
+	CreateSnapshot(kind string, period string) (LeaderboardSnapshotCore, error)
+	FindAllSnapshot(kind string, period string) ([]LeaderboardSnapshotCore, error)
+	FindSnapshotById(id string) (LeaderboardSnapshotCore, error)
+	FindSnapshotEntries(snapshotId string, classId string, schoolId string) ([]LeaderboardEntryCore, error)
+	GetPeriodRank(filter RankFilter, from time.Time, to time.Time) ([]UserCore, error)
+	GetRankTrajectory(userId string, kind string) ([]RankTrajectoryCore, error)
 
 	PostUserPointHistory(data UserPointCore) error
+	PostPointEntry(tx *gorm.DB, data UserPointCore) (UserPointCore, error)
 	GetAllUserPointHistory()([]UserPointCore, error)
 	GetSpecificUserPointHistory(id string)(UserPointCore, error)
 	GetUserPointHistory(id string)([]UserPointCore, error)
+
+	CreateRefreshToken(userId string, familyId string) (string, error)
+	RotateRefreshToken(token string) (UserCore, string, error)
+	RevokeRefreshToken(token string) error
+	RevokeToken(tokenId string, userId string, expiredAt time.Time) error
+	RevokeUserSessions(userId string) error
+	IsTokenRevoked(tokenId string, userId string, issuedAt time.Time) (bool, error)
+
+	FindUserByEmail(email string) (UserCore, error)
+	CheckSchoolClass(school string, class string) error
+	ImportUsers(data []UserCore) ([]UserCore, error)
+	CreatePasswordReset(data PasswordResetCore) error
+	FindActivePasswordReset(userId string) (PasswordResetCore, error)
+	AddPasswordResetAttempt(id string, maxAttempts int) (bool, error)
+	VerifyPasswordReset(id string, resetTokenHash string) error
+	FindPasswordResetByToken(resetTokenHash string) (PasswordResetCore, error)
+	ResetPassword(resetId string, userId string, password string) error
 }
 
 type UserUseCaseInterface interface {
 	Register(data UserCore, image *multipart.FileHeader) (row int, err error)
 	UpdateSiswa(id string, data UserCore, image *multipart.FileHeader) error
-	Login(email, password string) (UserCore, string, error)
+	Login(email, password string) (UserCore, string, string, error)
+	RefreshToken(token string) (string, string, error)
+	Logout(userId string, tokenId string, expiredAt time.Time, refreshToken string) error
+	RevokeUserSessions(userId string) error
+	ForgotPassword(email string) error
+	VerifyResetCode(email string, code string) (string, error)
+	ResetPassword(resetToken string, password string) error
 	ReadAllUser() ([]UserCore, error)
+	ImportUser(file *multipart.FileHeader) (UserImportResult, error)
+	ExportUser(format string) ([]byte, error)
 	ReadSpecificUser(id string) (user UserCore, err error)
 	DeleteUser(id string) (err error)
 
-	GetRankUser() ([]UserCore, error)
+	GetRankUser(userId string, filter RankFilter) ([]UserCore, error)
 	ChangePassword(id string, data UserCore) error
 
-	MonthlyResetPoint()(error)
-	AnnualResetPoint()(error)
+	ResetPoint(kind string, at time.Time, trigger string) (PointResetCore, error)
+	RunScheduledReset(kind string, at time.Time) error
+	FindAllPointReset() ([]PointResetCore, error)
+
+	CreateSnapshot() (LeaderboardSnapshotCore, error)
+	FindAllSnapshot(kind string, period string) ([]LeaderboardSnapshotCore, error)
+	FindSnapshot(id string, classId string, schoolId string) (LeaderboardSnapshotCore, []LeaderboardEntryCore, error)
+	GetPeriodRank(userId string, period string, filter RankFilter) ([]UserCore, error)
+	GetRankTrajectory(userId string, kind string) ([]RankTrajectoryCore, error)
 	
 	PostUserPointHistory(data UserPointCore) error
+	AdjustPoint(id string, data UserPointCore) error
 	GetAllUserPointHistory()([]UserPointCore, error)
 	GetSpecificUserPointHistory(id string)(UserPointCore, error)
 	GetUserPointHistory(id string)([]UserPointCore, error)
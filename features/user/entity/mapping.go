@@ -2,6 +2,22 @@ package entity
 
 import "tugaskita/features/user/model"
 
+// IdPointer maps an empty id to a NULL column.
+func IdPointer(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
+
+// IdValue maps a NULL id column to an empty id.
+func IdValue(id *string) string {
+	if id == nil {
+		return ""
+	}
+	return *id
+}
+
 func UserCoreToUserModel(data UserCore) model.Users {
 	return model.Users{
 		ID:         data.ID,
@@ -9,6 +25,8 @@ func UserCoreToUserModel(data UserCore) model.Users {
 		Address:    data.Address,
 		School:     data.School,
 		Class:      data.Class,
+		SchoolId:   IdPointer(data.SchoolId),
+		ClassId:    IdPointer(data.ClassId),
 		Email:      data.Email,
 		Password:   data.Password,
 		Role:       data.Role,
@@ -24,6 +42,8 @@ func UserModelToUserCore(data model.Users) UserCore {
 		Address:    data.Address,
 		School:     data.School,
 		Class:      data.Class,
+		SchoolId:   IdValue(data.SchoolId),
+		ClassId:    IdValue(data.ClassId),
 		Email:      data.Email,
 		Password:   data.Password,
 		Role:       data.Role,
@@ -34,25 +54,35 @@ func UserModelToUserCore(data model.Users) UserCore {
 
 func UserPointCoreToUserPointModel(data UserPointCore) model.UserPoint {
 	return model.UserPoint{
-		Id:        data.Id,
-		UserId:    data.UserId,
-		Type:      data.Type,
-		TaskName:  data.TaskName,
-		Point:     data.Point,
-		CreatedAt: data.CreatedAt,
-		UpdatedAt: data.UpdatedAt,
+		Id:                data.Id,
+		UserId:            data.UserId,
+		Type:              data.Type,
+		TaskName:          data.TaskName,
+		SourceId:          data.SourceId,
+		Point:             data.Point,
+		PointDelta:        data.PointDelta,
+		TotalPointDelta:   data.TotalPointDelta,
+		PointBalance:      data.PointBalance,
+		TotalPointBalance: data.TotalPointBalance,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
 	}
 }
 
 func UserPointModelToUserPointCore(data model.UserPoint) UserPointCore {
 	return UserPointCore{
-		Id:        data.Id,
-		UserId:    data.UserId,
-		Type:      data.Type,
-		TaskName:  data.TaskName,
-		Point:     data.Point,
-		CreatedAt: data.CreatedAt,
-		UpdatedAt: data.UpdatedAt,
+		Id:                data.Id,
+		UserId:            data.UserId,
+		Type:              data.Type,
+		TaskName:          data.TaskName,
+		SourceId:          data.SourceId,
+		Point:             data.Point,
+		PointDelta:        data.PointDelta,
+		TotalPointDelta:   data.TotalPointDelta,
+		PointBalance:      data.PointBalance,
+		TotalPointBalance: data.TotalPointBalance,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
 	}
 }
 
@@ -64,3 +94,52 @@ func ListUserPointModelToListUserPointCore(data []model.UserPoint) []UserPointCo
 	}
 	return dataUser
 }
+
+func PasswordResetModelToPasswordResetCore(data model.PasswordReset) PasswordResetCore {
+	return PasswordResetCore{
+		Id:             data.Id,
+		UserId:         data.UserId,
+		CodeHash:       data.CodeHash,
+		ResetTokenHash: data.ResetTokenHash,
+		Attempts:       data.Attempts,
+		ExpiredAt:      data.ExpiredAt,
+		VerifiedAt:     data.VerifiedAt,
+		UsedAt:         data.UsedAt,
+		CreatedAt:      data.CreatedAt,
+	}
+}
+
+func PointResetModelToPointResetCore(data model.PointReset) PointResetCore {
+	return PointResetCore{
+		Id:           data.Id,
+		Kind:         data.Kind,
+		Period:       data.Period,
+		Trigger:      data.Trigger,
+		SnapshotId:   data.SnapshotId,
+		UserCount:    data.UserCount,
+		ClearedPoint: data.ClearedPoint,
+		CreatedAt:    data.CreatedAt,
+	}
+}
+
+func LeaderboardSnapshotModelToLeaderboardSnapshotCore(data model.LeaderboardSnapshot) LeaderboardSnapshotCore {
+	return LeaderboardSnapshotCore{
+		Id:        data.Id,
+		Kind:      data.Kind,
+		Period:    data.Period,
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func LeaderboardEntryModelToLeaderboardEntryCore(data model.LeaderboardEntry) LeaderboardEntryCore {
+	return LeaderboardEntryCore{
+		Rank:       data.Position,
+		UserId:     data.UserId,
+		Name:       data.Name,
+		SchoolId:   data.SchoolId,
+		ClassId:    data.ClassId,
+		Class:      data.Class,
+		Point:      data.Point,
+		TotalPoint: data.TotalPoint,
+	}
+}
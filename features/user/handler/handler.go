@@ -3,9 +3,13 @@ package handler
 import (
 	"mime/multipart"
 	"net/http"
+	"strconv"
+	"time"
 	dto "tugaskita/features/user/dto"
 	"tugaskita/features/user/entity"
+	"tugaskita/utils/authz"
 	middleware "tugaskita/utils/jwt"
+	"tugaskita/utils/spreadsheet"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -76,7 +80,7 @@ func (handler *UserController) Login(e echo.Context) error {
 		Password: input.Password,
 	}
 
-	data, token, err := handler.userUsecase.Login(data.Email, data.Password)
+	data, token, refreshToken, err := handler.userUsecase.Login(data.Email, data.Password)
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
 			"message": "error login",
@@ -85,53 +89,100 @@ func (handler *UserController) Login(e echo.Context) error {
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
-		"message": "login success",
-		"email":   data.Email,
-		"token":   token,
+		"message":       "login success",
+		"email":         data.Email,
+		"token":         token,
+		"refresh_token": refreshToken,
 	})
 }
 
-func (handler *UserController) DeleteUser(e echo.Context) error {
-	_, role, _, errRole := middleware.ExtractTokenUserId(e)
-	if errRole != nil {
+func (handler *UserController) RefreshToken(e echo.Context) error {
+	input := dto.RefreshTokenRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": errRole.Error(),
+			"message": "error bind data",
+		})
+	}
+
+	token, refreshToken, err := handler.userUsecase.RefreshToken(input.RefreshToken)
+	if err != nil {
+		return e.JSON(http.StatusUnauthorized, map[string]any{
+			"message": "error refresh token",
+			"error":   err.Error(),
 		})
 	}
 
-	if role != "admin" {
+	return e.JSON(http.StatusOK, map[string]any{
+		"message":       "refresh token success",
+		"token":         token,
+		"refresh_token": refreshToken,
+	})
+}
+
+func (handler *UserController) Logout(e echo.Context) error {
+	userId, _, _, err := middleware.ExtractTokenUserId(e)
+	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
+			"message": err.Error(),
 		})
 	}
 
-	idParams := e.Param("id")
-	err := handler.userUsecase.DeleteUser(idParams)
+	tokenId, _, expiredAt, err := middleware.ExtractTokenId(e)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]interface{}{
-			"message": "Error deleting user",
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": err.Error(),
 		})
 	}
 
-	return e.JSON(http.StatusOK, map[string]interface{}{
-		"message": "User deleted successfully",
+	//the refresh token is optional, without it only this access token is revoked
+	input := dto.RefreshTokenRequest{}
+	e.Bind(&input)
+
+	errLogout := handler.userUsecase.Logout(userId, tokenId, expiredAt, input.RefreshToken)
+	if errLogout != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error logout",
+			"error":   errLogout.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "logout success",
 	})
 }
 
-func (handler *UserController) ReadSpecificUser(e echo.Context) error {
-	_, role, _, errRole := middleware.ExtractTokenUserId(e)
-	if errRole != nil {
+func (handler *UserController) RevokeUserSessions(e echo.Context) error {
+	idParams := e.Param("id")
+
+	err := handler.userUsecase.RevokeUserSessions(idParams)
+	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": errRole.Error(),
+			"message": "error revoke user sessions",
+			"error":   err.Error(),
 		})
 	}
 
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success revoke user sessions",
+	})
+}
+
+func (handler *UserController) DeleteUser(e echo.Context) error {
+	idParams := e.Param("id")
+	err := handler.userUsecase.DeleteUser(idParams)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Error deleting user",
 		})
 	}
 
+	return e.JSON(http.StatusOK, map[string]interface{}{
+		"message": "User deleted successfully",
+	})
+}
+
+func (handler *UserController) ReadSpecificUser(e echo.Context) error {
 	idParamstr := e.Param("id")
 
 	idParams, err := uuid.Parse(idParamstr)
@@ -154,10 +205,13 @@ func (handler *UserController) ReadSpecificUser(e echo.Context) error {
 		Address:    data.Address,
 		School:     data.School,
 		Class:      data.Class,
+		SchoolId:   data.SchoolId,
+		ClassId:    data.ClassId,
 		Role:       data.Role,
 		Religion:   data.Religion,
 		Email:      data.Email,
 		Image:      data.Image,
+		Thumbnail:  data.Thumbnail,
 		Point:      data.Point,
 		TotalPoint: data.TotalPoint,
 	}
@@ -198,7 +252,10 @@ func (handler *UserController) ReadProfileUser(e echo.Context) error {
 		Address:    data.Address,
 		School:     data.School,
 		Class:      data.Class,
+		SchoolId:   data.SchoolId,
+		ClassId:    data.ClassId,
 		Image:      data.Image,
+		Thumbnail:  data.Thumbnail,
 		Email:      data.Email,
 		Religion:   data.Religion,
 		Point:      data.Point,
@@ -212,19 +269,6 @@ func (handler *UserController) ReadProfileUser(e echo.Context) error {
 }
 
 func (handler *UserController) ReadAllUser(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
-		})
-	}
-
-	if role != "admin" {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
-		})
-	}
-
 	data, err := handler.userUsecase.ReadAllUser()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
@@ -240,7 +284,10 @@ func (handler *UserController) ReadAllUser(e echo.Context) error {
 			Address:    v.Address,
 			School:     v.School,
 			Class:      v.Class,
+			SchoolId:   v.SchoolId,
+			ClassId:    v.ClassId,
 			Image:      v.Image,
+			Thumbnail:  v.Thumbnail,
 			Email:      v.Email,
 			Role:       v.Role,
 			Religion:   v.Religion,
@@ -256,20 +303,48 @@ func (handler *UserController) ReadAllUser(e echo.Context) error {
 	})
 }
 
-func (handler *UserController) UpdateSiswa(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
+func (handler *UserController) ImportUser(e echo.Context) error {
+	file, err := e.FormFile("file")
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
+			"message": "error uploading file",
 		})
 	}
 
-	if role != "admin" {
+	result, errImport := handler.userUsecase.ImportUser(file)
+	if errImport != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
+			"message": "error import user",
+			"error":   errImport.Error(),
+			"data":    result,
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success import user",
+		"data":    result,
+	})
+}
+
+func (handler *UserController) ExportUser(e echo.Context) error {
+	format := e.QueryParam("format")
+	if format == "" {
+		format = spreadsheet.CSV
+	}
+
+	data, err := handler.userUsecase.ExportUser(format)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error export user",
+			"error":   err.Error(),
 		})
 	}
 
+	e.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=users."+format)
+	return e.Blob(http.StatusOK, spreadsheet.ContentType(format), data)
+}
+
+func (handler *UserController) UpdateSiswa(e echo.Context) error {
 	idParams := e.Param("id")
 
 	data := new(dto.UserRequest)
@@ -281,7 +356,7 @@ func (handler *UserController) UpdateSiswa(e echo.Context) error {
 
 	// Menginisialisasi variabel untuk file gambar
 	var image *multipart.FileHeader
-	image, err = e.FormFile("image")
+	image, err := e.FormFile("image")
 	if err != nil && err != http.ErrMissingFile {
 		return e.JSON(http.StatusBadRequest, map[string]interface{}{
 			"message": "Error uploading file",
@@ -297,7 +372,6 @@ func (handler *UserController) UpdateSiswa(e echo.Context) error {
 		Class:    data.Class,
 		Password: data.Password,
 		Religion: data.Religion,
-		Point:    data.Point,
 	}
 
 	// Panggil fungsi UpdateSiswa di usecase, kirimkan image jika ada
@@ -314,26 +388,157 @@ func (handler *UserController) UpdateSiswa(e echo.Context) error {
 	})
 }
 
-func (handler *UserController) GetRankUser(e echo.Context) error {
-	data, err := handler.userUsecase.GetRankUser()
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "error get all user",
-		})
+func rankFilter(e echo.Context) entity.RankFilter {
+	grade, _ := strconv.Atoi(e.QueryParam("grade"))
+	return entity.RankFilter{
+		Scope:        e.QueryParam("scope"),
+		ClassId:      e.QueryParam("class_id"),
+		SchoolId:     e.QueryParam("school_id"),
+		Grade:        grade,
+		AcademicYear: e.QueryParam("academic_year"),
 	}
+}
 
+func rankResponse(data []entity.UserCore) []dto.UserRankResponse {
 	dataList := []dto.UserRankResponse{}
-	for _, v := range data {
+	for i, v := range data {
+		//users with the same point share a rank
+		rank := i + 1
+		if i > 0 && v.Point == data[i-1].Point {
+			rank = dataList[i-1].Rank
+		}
+
 		result := dto.UserRankResponse{
+			Rank:  rank,
+			Id:    v.ID,
 			Name:  v.Name,
+			Class: v.Class,
 			Point: v.Point,
 		}
 		dataList = append(dataList, result)
 	}
+	return dataList
+}
+
+func (handler *UserController) GetRankUser(e echo.Context) error {
+	userId, _, _, err := middleware.ExtractTokenUserId(e)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": err.Error(),
+		})
+	}
+
+	data, err := handler.userUsecase.GetRankUser(userId, rankFilter(e))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all user",
+			"error":   err.Error(),
+		})
+	}
 
 	return e.JSON(http.StatusOK, map[string]any{
 		"message": "get all user rank",
-		"data":    dataList,
+		"data":    rankResponse(data),
+	})
+}
+
+func (handler *UserController) GetPeriodRank(e echo.Context) error {
+	userId, _, _, err := middleware.ExtractTokenUserId(e)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": err.Error(),
+		})
+	}
+
+	data, err := handler.userUsecase.GetPeriodRank(userId, e.QueryParam("period"), rankFilter(e))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get period rank",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get period rank",
+		"data":    rankResponse(data),
+	})
+}
+
+func (handler *UserController) GetRankTrajectory(e echo.Context) error {
+	userId, _, _, err := middleware.ExtractTokenUserId(e)
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": err.Error(),
+		})
+	}
+
+	target := e.QueryParam("user_id")
+	if target == "" {
+		target = userId
+	}
+
+	if target != userId && !authz.Can(e, authz.PointRead) {
+		return e.JSON(http.StatusForbidden, map[string]any{
+			"message": "access denied",
+		})
+	}
+
+	data, err := handler.userUsecase.GetRankTrajectory(target, e.QueryParam("kind"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get rank trajectory",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get rank trajectory",
+		"data":    append([]entity.RankTrajectoryCore{}, data...),
+	})
+}
+
+func (handler *UserController) CreateSnapshot(e echo.Context) error {
+	data, err := handler.userUsecase.CreateSnapshot()
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error create snapshot",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "success create snapshot",
+		"data":    data,
+	})
+}
+
+func (handler *UserController) ReadAllSnapshot(e echo.Context) error {
+	data, err := handler.userUsecase.FindAllSnapshot(e.QueryParam("kind"), e.QueryParam("period"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get all snapshot",
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all snapshot",
+		"data":    append([]entity.LeaderboardSnapshotCore{}, data...),
+	})
+}
+
+func (handler *UserController) ReadSpecificSnapshot(e echo.Context) error {
+	snapshot, entries, err := handler.userUsecase.FindSnapshot(e.Param("id"), e.QueryParam("class_id"), e.QueryParam("school_id"))
+	if err != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error get snapshot",
+			"error":   err.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get snapshot",
+		"data": map[string]any{
+			"snapshot": snapshot,
+			"entries":  entries,
+		},
 	})
 }
 
@@ -369,72 +574,111 @@ func (handler *UserController) ChangePassword(e echo.Context) error {
 	})
 }
 
-func (handler *UserController) AnnualResetPoint(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
+func (handler *UserController) ForgotPassword(e echo.Context) error {
+	input := dto.ForgotPasswordRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	err := handler.userUsecase.ForgotPassword(input.Email)
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
+			"message": "error request reset code",
+			"error":   err.Error(),
 		})
 	}
 
-	if role != "admin" {
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "if the email is registered a reset code has been sent",
+	})
+}
+
+func (handler *UserController) VerifyResetCode(e echo.Context) error {
+	input := dto.VerifyResetCodeRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
+			"message": "error bind data",
 		})
 	}
 
-	errReset := handler.userUsecase.AnnualResetPoint()
-	if errReset != nil {
+	resetToken, err := handler.userUsecase.VerifyResetCode(input.Email, input.Code)
+	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "failed reset point",
+			"message": "error verify reset code",
+			"error":   err.Error(),
 		})
 	}
 
-	return e.JSON(http.StatusOK, map[string]interface{}{
-		"message": "total point reset successfull",
+	return e.JSON(http.StatusOK, map[string]any{
+		"message":     "reset code verified",
+		"reset_token": resetToken,
 	})
 }
 
-func (handler *UserController) MonthlyResetPoint(e echo.Context) error {
-	_, role, _, err := middleware.ExtractTokenUserId(e)
-	if err != nil {
+func (handler *UserController) ResetPassword(e echo.Context) error {
+	input := dto.ResetPasswordRequest{}
+	errBind := e.Bind(&input)
+	if errBind != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": err.Error(),
+			"message": "error bind data",
 		})
 	}
 
-	if role != "admin" {
+	err := handler.userUsecase.ResetPassword(input.ResetToken, input.Password)
+	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
+			"message": "error reset password",
+			"error":   err.Error(),
 		})
 	}
 
-	errReset := handler.userUsecase.MonthlyResetPoint()
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "password updated",
+	})
+}
+
+func (handler *UserController) resetPoint(e echo.Context, kind string) error {
+	data, errReset := handler.userUsecase.ResetPoint(kind, time.Now(), entity.ResetTriggerManual)
 	if errReset != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
 			"message": "failed reset point",
+			"error":   errReset.Error(),
 		})
 	}
 
 	return e.JSON(http.StatusOK, map[string]interface{}{
 		"message": "point reset successfull",
+		"data":    data,
 	})
 }
 
-func (handler *UserController) GetAllUserPointHistory(e echo.Context) error {
-	_, role, _, errRole := middleware.ExtractTokenUserId(e)
-	if errRole != nil {
-		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": errRole.Error(),
-		})
-	}
+func (handler *UserController) AnnualResetPoint(e echo.Context) error {
+	return handler.resetPoint(e, entity.ResetAnnual)
+}
 
-	if role != "admin" {
+func (handler *UserController) MonthlyResetPoint(e echo.Context) error {
+	return handler.resetPoint(e, entity.ResetMonthly)
+}
+
+func (handler *UserController) ReadAllPointReset(e echo.Context) error {
+	data, err := handler.userUsecase.FindAllPointReset()
+	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
-			"message": "access denied",
+			"message": "error get all point reset",
 		})
 	}
 
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "get all point reset",
+		"data":    data,
+	})
+}
+
+func (handler *UserController) GetAllUserPointHistory(e echo.Context) error {
 	data, err := handler.userUsecase.GetAllUserPointHistory()
 	if err != nil {
 		return e.JSON(http.StatusBadRequest, map[string]any{
@@ -445,13 +689,18 @@ func (handler *UserController) GetAllUserPointHistory(e echo.Context) error {
 	dataList := []entity.UserPointCore{}
 	for _, v := range data {
 		result := entity.UserPointCore{
-			Id:        v.Id,
-			UserId:    v.UserId,
-			Type:      v.Type,
-			TaskName:  v.TaskName,
-			Point:     v.Point,
-			CreatedAt: v.CreatedAt,
-			UpdatedAt: v.UpdatedAt,
+			Id:                v.Id,
+			UserId:            v.UserId,
+			Type:              v.Type,
+			TaskName:          v.TaskName,
+			SourceId:          v.SourceId,
+			Point:             v.Point,
+			PointDelta:        v.PointDelta,
+			TotalPointDelta:   v.TotalPointDelta,
+			PointBalance:      v.PointBalance,
+			TotalPointBalance: v.TotalPointBalance,
+			CreatedAt:         v.CreatedAt,
+			UpdatedAt:         v.UpdatedAt,
 		}
 		dataList = append(dataList, result)
 	}
@@ -479,14 +728,32 @@ func (handler *UserController) GetSpecificUserPointHistory(e echo.Context) error
 		})
 	}
 
+	userId, _, _, errToken := middleware.ExtractTokenUserId(e)
+	if errToken != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": errToken.Error(),
+		})
+	}
+
+	if data.UserId != userId && !authz.Can(e, authz.PointRead) {
+		return e.JSON(http.StatusForbidden, map[string]any{
+			"message": "access denied",
+		})
+	}
+
 	response := entity.UserPointCore{
-		Id:        data.Id,
-		UserId:    data.UserId,
-		Type:      data.Type,
-		TaskName:  data.TaskName,
-		Point:     data.Point,
-		CreatedAt: data.CreatedAt,
-		UpdatedAt: data.UpdatedAt,
+		Id:                data.Id,
+		UserId:            data.UserId,
+		Type:              data.Type,
+		TaskName:          data.TaskName,
+		SourceId:          data.SourceId,
+		Point:             data.Point,
+		PointDelta:        data.PointDelta,
+		TotalPointDelta:   data.TotalPointDelta,
+		PointBalance:      data.PointBalance,
+		TotalPointBalance: data.TotalPointBalance,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
@@ -513,13 +780,18 @@ func (handler *UserController) GetUserPointHistory(e echo.Context) error {
 	dataList := []entity.UserPointCore{}
 	for _, v := range data {
 		result := entity.UserPointCore{
-			Id:        v.Id,
-			UserId:    v.UserId,
-			Type:      v.Type,
-			TaskName:  v.TaskName,
-			Point:     v.Point,
-			CreatedAt: v.CreatedAt,
-			UpdatedAt: v.UpdatedAt,
+			Id:                v.Id,
+			UserId:            v.UserId,
+			Type:              v.Type,
+			TaskName:          v.TaskName,
+			SourceId:          v.SourceId,
+			Point:             v.Point,
+			PointDelta:        v.PointDelta,
+			TotalPointDelta:   v.TotalPointDelta,
+			PointBalance:      v.PointBalance,
+			TotalPointBalance: v.TotalPointBalance,
+			CreatedAt:         v.CreatedAt,
+			UpdatedAt:         v.UpdatedAt,
 		}
 		dataList = append(dataList, result)
 	}
@@ -530,6 +802,35 @@ func (handler *UserController) GetUserPointHistory(e echo.Context) error {
 	})
 }
 
+func (handler *UserController) AdjustPoint(e echo.Context) error {
+	idParams := e.Param("id")
+
+	input := dto.PointAdjustmentRequest{}
+	if errBind := e.Bind(&input); errBind != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error bind data",
+		})
+	}
+
+	data := entity.UserPointCore{
+		TaskName:        input.Description,
+		PointDelta:      input.PointDelta,
+		TotalPointDelta: input.TotalPointDelta,
+	}
+
+	errAdjust := handler.userUsecase.AdjustPoint(idParams, data)
+	if errAdjust != nil {
+		return e.JSON(http.StatusBadRequest, map[string]any{
+			"message": "error adjust point",
+			"error":   errAdjust.Error(),
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"message": "point adjusted",
+	})
+}
+
 func (handler *UserController) PostUserPointHistory(e echo.Context) error {
 	input := entity.UserPointCore{}
 	errBind := e.Bind(&input)
@@ -2,31 +2,116 @@ package model
 
 import (
 	"time"
+	school "tugaskita/features/school/model"
 )
 
 type Users struct {
-	ID         string    `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
-	Name       string    `gorm:"varchar(50);not null" json:"username"`
-	Address    string    `gorm:"Varchar(50)" json:"address"`
-	School     string    `gorm:"Varchar(50)" json:"school"`
-	Class      string    `gorm:"Varchar(25)" json:"class"`
-	Image      string    `json:"image"`
-	Email      string    `gorm:"varchar(50);not null" json:"email"`
-	Password   string    `gorm:"varchar(50);not null" json:"password"`
-	Role       string    `gorm:"Varchar(25);not null" json:"role"`
-	Religion   string    `gorm:"Varchar(25)" json:"religion"`
-	Point      string    `gorm:"Varchar(100);not null" json:"point"`
-	TotalPoint string    `gorm:"Varchar(100);not null" json:"total_point"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"update_at"`
+	ID                string         `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	Name              string         `gorm:"varchar(50);not null" json:"username"`
+	Address           string         `gorm:"Varchar(50)" json:"address"`
+	School            string         `gorm:"Varchar(50)" json:"school"`
+	Class             string         `gorm:"Varchar(25)" json:"class"`
+	SchoolId          *string        `gorm:"type:varchar(50);index" json:"school_id"`
+	SchoolData        *school.School `gorm:"foreignKey:SchoolId;constraint:OnDelete:SET NULL" json:"-"`
+	ClassId           *string        `gorm:"type:varchar(50);index" json:"class_id"`
+	ClassData         *school.Class  `gorm:"foreignKey:ClassId;constraint:OnDelete:SET NULL" json:"-"`
+	Image             string         `json:"image"`
+	Thumbnail         string         `json:"thumbnail"`
+	Email             string         `gorm:"varchar(50);not null" json:"email"`
+	Password          string         `gorm:"varchar(50);not null" json:"password"`
+	Role              string         `gorm:"Varchar(25);not null" json:"role"`
+	Religion          string         `gorm:"Varchar(25)" json:"religion"`
+	Point             int            `gorm:"not null;default:0" json:"point"`
+	TotalPoint        int            `gorm:"not null;default:0" json:"total_point"`
+	SessionsRevokedAt *time.Time     `json:"-"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"update_at"`
 }
 
+// UserPoint is an append-only ledger entry, every change to a user's
+// Point or TotalPoint balance is recorded here in the same transaction.
 type UserPoint struct {
-	Id        string `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
-	UserId    string
-	Type      string
-	TaskName  string
-	Point     int
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"update_at"`
+	Id                string `gorm:"type:varchar(50);primaryKey;not null" json:"id"`
+	UserId            string `gorm:"type:varchar(50);index;not null"`
+	Type              string `gorm:"type:varchar(25);not null"`
+	TaskName          string
+	SourceId          string `gorm:"type:varchar(50);index"`
+	Point             int
+	PointDelta        int
+	TotalPointDelta   int
+	PointBalance      int
+	TotalPointBalance int
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"update_at"`
+}
+
+// RefreshToken only stores the hash of the token. Tokens rotated from the
+// same login share a FamilyId so a reused token can revoke the whole chain.
+type RefreshToken struct {
+	Id         string `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId     string `gorm:"type:varchar(50);index;not null"`
+	FamilyId   string `gorm:"type:varchar(50);index;not null"`
+	TokenHash  string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ReplacedBy string `gorm:"type:varchar(50)"`
+	ExpiredAt  time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// RevokedToken holds access token ids rejected until they expire.
+type RevokedToken struct {
+	Id        string    `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId    string    `gorm:"type:varchar(50);index;not null"`
+	ExpiredAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+// PasswordReset is a one time code sent by email. After the code is
+// verified ResetTokenHash is set and the token can change the password once.
+type PasswordReset struct {
+	Id             string `gorm:"type:varchar(50);primaryKey;not null"`
+	UserId         string `gorm:"type:varchar(50);index;not null"`
+	CodeHash       string `gorm:"not null"`
+	ResetTokenHash string `gorm:"type:varchar(64);index"`
+	Attempts       int    `gorm:"not null;default:0"`
+	ExpiredAt      time.Time
+	VerifiedAt     *time.Time
+	UsedAt         *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// PointReset records a monthly or annual reset. Kind and Period are unique
+// so a period is never reset twice, whoever triggers it.
+type PointReset struct {
+	Id           string `gorm:"type:varchar(50);primaryKey;not null"`
+	Kind         string `gorm:"type:varchar(25);not null;uniqueIndex:idx_point_reset_period"`
+	Period       string `gorm:"type:varchar(25);not null;uniqueIndex:idx_point_reset_period"`
+	Trigger      string `gorm:"type:varchar(25);not null"`
+	SnapshotId   string `gorm:"type:varchar(50)"`
+	UserCount    int
+	ClearedPoint int
+	CreatedAt    time.Time
+}
+
+// LeaderboardSnapshot archives the standings at a point in time.
+type LeaderboardSnapshot struct {
+	Id        string `gorm:"type:varchar(50);primaryKey;not null"`
+	Kind      string `gorm:"type:varchar(25);not null;index"`
+	Period    string `gorm:"type:varchar(25);not null;index"`
+	CreatedAt time.Time
+}
+
+type LeaderboardEntry struct {
+	Id         string `gorm:"type:varchar(50);primaryKey;not null"`
+	SnapshotId string `gorm:"type:varchar(50);not null;index"`
+	UserId     string `gorm:"type:varchar(50);not null;index"`
+	Name       string
+	SchoolId   string `gorm:"type:varchar(50)"`
+	ClassId    string `gorm:"type:varchar(50)"`
+	Class      string `gorm:"type:varchar(25)"`
+	Position   int    `gorm:"not null;index"`
+	Point      int
+	TotalPoint int
 }
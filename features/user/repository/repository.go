@@ -1,28 +1,35 @@
 package repository
 
 import (
+	"context"
 	"errors"
-	"io"
 	"mime/multipart"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
+	schoolModel "tugaskita/features/school/model"
 	"tugaskita/features/user/entity"
 	"tugaskita/features/user/model"
 	bcrypt "tugaskita/utils/bcrypt"
 	utils "tugaskita/utils/jwt"
+	"tugaskita/utils/storage"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// defaultUserImage is used for accounts created without a profile image.
+const defaultUserImage = "public/images/user/person.png"
+
 type userRepository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	storage storage.Storage
 }
 
-func New(db *gorm.DB) entity.UserDataInterface {
+func New(db *gorm.DB, store storage.Storage) entity.UserDataInterface {
 	return &userRepository{
-		db: db,
+		db:      db,
+		storage: store,
 	}
 }
 
@@ -84,8 +91,11 @@ func (userRepo *userRepository) ReadSpecificUser(id string) (user entity.UserCor
 		Address:    data.Address,
 		School:     data.School,
 		Class:      data.Class,
+		SchoolId:   entity.IdValue(data.SchoolId),
+		ClassId:    entity.IdValue(data.ClassId),
 		Email:      data.Email,
 		Image:      data.Image,
+		Thumbnail:  data.Thumbnail,
 		Religion:   data.Religion,
 		Point:      data.Point,
 		TotalPoint: data.TotalPoint,
@@ -108,48 +118,33 @@ func (userRepo *userRepository) Register(data entity.UserCore, image *multipart.
 		return 0, err
 	}
 
-	file, err := image.Open()
-	if err != nil {
-		return 0, err
-	}
-	defer file.Close()
-
-	// Define the directory where you want to save the image
-	saveDir := "public/images/user"
-	os.MkdirAll(saveDir, os.ModePerm)
-
-	// Define the file path
-	filePath := filepath.Join(saveDir, image.Filename)
-
-	filePath = strings.ReplaceAll(filePath, "\\", "/")
-
-	// Create the destination file
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return 0, err
-	}
-	defer dst.Close()
+	data.Image = defaultUserImage
+	data.Thumbnail = defaultUserImage
+	var stored storage.StoredImage
+	if image != nil {
+		stored, err = storage.SaveImage(context.Background(), userRepo.storage, "images/user", image)
+		if err != nil {
+			return 0, err
+		}
 
-	// Copy the uploaded file data to the destination file
-	if _, err = io.Copy(dst, file); err != nil {
-		return 0, err
+		// Set the image URL to the stored file
+		data.Image = stored.URL
+		data.Thumbnail = stored.ThumbnailURL
 	}
 
-	// Set the image URL to the file path
-	data.Image = filePath
-
 	var input = model.Users{
 		ID:         newUUID.String(),
 		Name:       data.Name,
 		Image:      data.Image,
+		Thumbnail:  data.Thumbnail,
 		Address:    data.Address,
 		School:     data.School,
 		Class:      data.Class,
 		Email:      data.Email,
 		Religion:   data.Religion,
 		Password:   hashPassword,
-		Point:      "0",
-		TotalPoint: "0",
+		Point:      0,
+		TotalPoint: 0,
 		Role:       "user",
 	}
 
@@ -157,14 +152,120 @@ func (userRepo *userRepository) Register(data entity.UserCore, image *multipart.
 	println(input.School)
 	println(input.Class)
 
-	erruser := userRepo.db.Save(&input)
-	if erruser.Error != nil {
-		return 0, erruser.Error
+	erruser := userRepo.db.Transaction(func(tx *gorm.DB) error {
+		if err := linkClass(tx, &input); err != nil {
+			return err
+		}
+		return tx.Save(&input).Error
+	})
+	if erruser != nil {
+		storage.DeleteImage(context.Background(), userRepo.storage, stored)
+		return 0, erruser
 	}
 
 	return 1, nil
 }
 
+// linkClass sets the school and class ids of user from its School and
+// Class names, and the names to those of the rows. Schools and classes are
+// added by admins, names no school or class of the school has are
+// rejected. A class is looked up in the latest academic year it exists.
+func linkClass(tx *gorm.DB, user *model.Users) error {
+	user.SchoolId, user.ClassId = nil, nil
+
+	schoolName, className := strings.TrimSpace(user.School), strings.TrimSpace(user.Class)
+	if schoolName == "" {
+		if className != "" {
+			return errors.New("school is required with a class")
+		}
+		return nil
+	}
+
+	var schools []schoolModel.School
+	err := tx.Where("LOWER(name) = LOWER(?)", schoolName).Limit(1).Find(&schools).Error
+	if err != nil {
+		return err
+	}
+	if len(schools) == 0 {
+		return errors.New("school " + schoolName + " not found")
+	}
+
+	schoolId := schools[0].Id.String()
+	user.SchoolId, user.School = &schoolId, schools[0].Name
+
+	if className == "" {
+		return nil
+	}
+
+	var classes []schoolModel.Class
+	err = tx.Where("school_id = ? AND LOWER(name) = LOWER(?)", schoolId, className).
+		Order("academic_year desc").Limit(1).Find(&classes).Error
+	if err != nil {
+		return err
+	}
+	if len(classes) == 0 {
+		return errors.New("class " + className + " not found in " + schools[0].Name)
+	}
+
+	classId := classes[0].Id.String()
+	user.ClassId, user.Class = &classId, classes[0].Name
+	return nil
+}
+
+// CheckSchoolClass implements entity.UserDataInterface.
+func (userRepo *userRepository) CheckSchoolClass(school string, class string) error {
+	return linkClass(userRepo.db, &model.Users{School: school, Class: class})
+}
+
+// ImportUsers implements entity.UserDataInterface.
+func (userRepo *userRepository) ImportUsers(data []entity.UserCore) ([]entity.UserCore, error) {
+	users := []model.Users{}
+	for _, v := range data {
+		hashPassword, err := bcrypt.HashPassword(v.Password)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, model.Users{
+			ID:         uuid.NewString(),
+			Name:       v.Name,
+			Image:      defaultUserImage,
+			Thumbnail:  defaultUserImage,
+			Address:    v.Address,
+			School:     v.School,
+			Class:      v.Class,
+			Email:      v.Email,
+			Religion:   v.Religion,
+			Password:   hashPassword,
+			Point:      0,
+			TotalPoint: 0,
+			Role:       "user",
+		})
+	}
+
+	errTx := userRepo.db.Transaction(func(tx *gorm.DB) error {
+		for i := range users {
+			if err := linkClass(tx, &users[i]); err != nil {
+				return err
+			}
+		}
+		return tx.CreateInBatches(&users, 100).Error
+	})
+	if errTx != nil {
+		return nil, errTx
+	}
+
+	result := make([]entity.UserCore, len(users))
+	for i, v := range users {
+		result[i] = data[i]
+		result[i].ID = v.ID
+		result[i].Image = v.Image
+		result[i].Thumbnail = v.Thumbnail
+	}
+
+	return result, nil
+}
+
 // ReadAllUser implements entity.UserDataInterface.
 func (userRepo *userRepository) ReadAllUser() ([]entity.UserCore, error) {
 	var dataUser []model.Users
@@ -183,7 +284,10 @@ func (userRepo *userRepository) ReadAllUser() ([]entity.UserCore, error) {
 			Address:    value.Address,
 			School:     value.School,
 			Class:      value.Class,
+			SchoolId:   entity.IdValue(value.SchoolId),
+			ClassId:    entity.IdValue(value.ClassId),
 			Image:      value.Image,
+			Thumbnail:  value.Thumbnail,
 			Role:       value.Role,
 			Religion:   value.Religion,
 			Point:      value.Point,
@@ -238,79 +342,116 @@ func (userRepo *userRepository) ReadAllUser() ([]entity.UserCore, error) {
 func (userRepo *userRepository) UpdateSiswa(id string, data entity.UserCore, image *multipart.FileHeader) error {
 	dataUser := entity.UserCoreToUserModel(data)
 
-	// If an image is uploaded, save it to a local folder
+	// If an image is uploaded, store it
+	var stored storage.StoredImage
 	if image != nil {
-		file, err := image.Open()
+		var err error
+		stored, err = storage.SaveImage(context.Background(), userRepo.storage, "images/user", image)
 		if err != nil {
 			return err
 		}
-		defer file.Close()
-
-		// Define the directory where you want to save the image
-		saveDir := "public/images/user"
-		os.MkdirAll(saveDir, os.ModePerm)
-
-		// Define the file path
-		filePath := filepath.Join(saveDir, image.Filename)
-
-		// Replace backslashes with forward slashes for consistency
-		filePath = strings.ReplaceAll(filePath, "\\", "/")
-
-		// Create the destination file
-		dst, err := os.Create(filePath)
-		if err != nil {
-			return err
-		}
-		defer dst.Close()
-
-		// Copy the uploaded file data to the destination file
-		if _, err = io.Copy(dst, file); err != nil {
-			return err
-		}
 
-		// Update the dataUser.Image field with the local file path
-		dataUser.Image = filePath
+		// Update the dataUser.Image field with the stored file URL
+		dataUser.Image = stored.URL
+		dataUser.Thumbnail = stored.ThumbnailURL
 	}
 
 	// Hash the password if it's not empty
 	if data.Password != "" {
 		hashPassword, err := bcrypt.HashPassword(data.Password)
 		if err != nil {
+			storage.DeleteImage(context.Background(), userRepo.storage, stored)
 			return err
 		}
 		dataUser.Password = hashPassword
 	}
 
-	// Update the user's data in the database
-	tx := userRepo.db.Where("id = ?", id).Updates(&dataUser)
-	if tx.Error != nil {
-		return tx.Error
-	}
+	// Update the user's data in the database, a new school or class
+	// links the user again
+	err := userRepo.db.Transaction(func(tx *gorm.DB) error {
+		update := tx.Where("id = ?", id).Updates(&dataUser)
+		if update.Error != nil {
+			return update.Error
+		}
 
-	if tx.RowsAffected == 0 {
-		return errors.New("user not found")
+		if update.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+
+		if data.School == "" && data.Class == "" {
+			return nil
+		}
+
+		var user model.Users
+		if err := tx.Where("id = ?", id).First(&user).Error; err != nil {
+			return err
+		}
+		if err := linkClass(tx, &user); err != nil {
+			return err
+		}
+
+		return tx.Model(&user).Updates(map[string]any{
+			"school":    user.School,
+			"class":     user.Class,
+			"school_id": user.SchoolId,
+			"class_id":  user.ClassId,
+		}).Error
+	})
+	if err != nil {
+		storage.DeleteImage(context.Background(), userRepo.storage, stored)
+		return err
 	}
 
 	return nil
 }
 
-// UpdatePoint implements entity.UserDataInterface.
-func (userRepo *userRepository) UpdatePoint(id string, data entity.UserCore) error {
-	userData := entity.UserCoreToUserModel(data)
+// rankScope narrows a query on users to the scope of filter.
+func (userRepo *userRepository) rankScope(query *gorm.DB, filter entity.RankFilter) (*gorm.DB, error) {
+	switch filter.Scope {
+	case entity.RankScopeClass:
+		query = query.Where("users.class_id = ?", filter.ClassId)
+	case entity.RankScopeSchool:
+		query = query.Where("users.school_id = ?", filter.SchoolId)
+	case entity.RankScopeGrade:
+		if filter.ClassId != "" && (filter.SchoolId == "" || filter.Grade == 0) {
+			var class struct {
+				SchoolId     string
+				Grade        int
+				AcademicYear string
+			}
+			errClass := userRepo.db.Table("classes").
+				Select("school_id, grade, academic_year").
+				Where("id = ?", filter.ClassId).
+				Take(&class).Error
+			if errClass != nil {
+				return nil, errors.New("class not found")
+			}
+			filter.SchoolId, filter.Grade = class.SchoolId, class.Grade
+			if filter.AcademicYear == "" {
+				filter.AcademicYear = class.AcademicYear
+			}
+		}
 
-	tx := userRepo.db.Where("id = ?", id).Updates(&userData)
-	if tx != nil {
-		return tx.Error
+		classes := userRepo.db.Table("classes").Select("id").Where("school_id = ? AND grade = ?", filter.SchoolId, filter.Grade)
+		if filter.AcademicYear != "" {
+			classes = classes.Where("academic_year = ?", filter.AcademicYear)
+		}
+		query = query.Where("users.class_id IN (?)", classes)
 	}
 
-	return nil
+	return query, nil
 }
 
 // GetRankUser implements entity.UserDataInterface.
-func (userRepo *userRepository) GetRankUser() ([]entity.UserCore, error) {
+func (userRepo *userRepository) GetRankUser(filter entity.RankFilter) ([]entity.UserCore, error) {
 	var dataUser []model.Users
 
-	errData := userRepo.db.Where("role = ?", "user").Order("point desc").Find(&dataUser).Error
+	query, errScope := userRepo.rankScope(userRepo.db.Where("users.role = ?", "user"), filter)
+	if errScope != nil {
+		return nil, errScope
+	}
+
+	errData := query.Order("point desc").Order("name").Find(&dataUser).Error
 	if errData != nil {
 		return nil, errData
 	}
@@ -321,6 +462,9 @@ func (userRepo *userRepository) GetRankUser() ([]entity.UserCore, error) {
 			ID:         value.ID,
 			Name:       value.Name,
 			Email:      value.Email,
+			Class:      value.Class,
+			SchoolId:   entity.IdValue(value.SchoolId),
+			ClassId:    entity.IdValue(value.ClassId),
 			Role:       value.Role,
 			Point:      value.Point,
 			TotalPoint: value.TotalPoint,
@@ -348,28 +492,319 @@ func (userRepo *userRepository) ChangePassword(id string, data entity.UserCore)
 	return nil
 }
 
-// AnnualResetPoint implements entity.UserDataInterface.
-func (userRepo *userRepository) AnnualResetPoint() error {
-	err := userRepo.db.Exec("UPDATE users SET total_point = 0").Error
-	if err != nil {
-		return err
+// createSnapshot archives the current standings of every student, ranked
+// by total point for an annual snapshot and by point otherwise.
+func (userRepo *userRepository) createSnapshot(tx *gorm.DB, kind string, period string) (model.LeaderboardSnapshot, []model.Users, error) {
+	score := func(data model.Users) int {
+		if kind == entity.ResetAnnual {
+			return data.TotalPoint
+		}
+		return data.Point
 	}
-	return nil
+
+	orderBy := "point"
+	if kind == entity.ResetAnnual {
+		orderBy = "total_point"
+	}
+
+	var users []model.Users
+	errData := tx.Where("role = ?", "user").Order(orderBy + " desc, name").Find(&users).Error
+	if errData != nil {
+		return model.LeaderboardSnapshot{}, nil, errData
+	}
+
+	snapshot := model.LeaderboardSnapshot{
+		Id:     uuid.NewString(),
+		Kind:   kind,
+		Period: period,
+	}
+	if err := tx.Create(&snapshot).Error; err != nil {
+		return model.LeaderboardSnapshot{}, nil, err
+	}
+
+	entries := []model.LeaderboardEntry{}
+	for i, v := range users {
+		//students with the same score share a rank
+		rank := i + 1
+		if i > 0 && score(users[i-1]) == score(v) {
+			rank = entries[i-1].Position
+		}
+
+		entries = append(entries, model.LeaderboardEntry{
+			Id:         uuid.NewString(),
+			SnapshotId: snapshot.Id,
+			UserId:     v.ID,
+			Name:       v.Name,
+			SchoolId:   entity.IdValue(v.SchoolId),
+			ClassId:    entity.IdValue(v.ClassId),
+			Class:      v.Class,
+			Position:   rank,
+			Point:      v.Point,
+			TotalPoint: v.TotalPoint,
+		})
+	}
+
+	if len(entries) > 0 {
+		if err := tx.CreateInBatches(&entries, 200).Error; err != nil {
+			return model.LeaderboardSnapshot{}, nil, err
+		}
+	}
+
+	return snapshot, users, nil
 }
 
-// MonthlyResetPoint implements entity.UserDataInterface.
-func (userRepo *userRepository) MonthlyResetPoint() error {
-	err := userRepo.db.Exec("UPDATE users SET point = 0").Error
-	if err != nil {
+// CreateSnapshot implements entity.UserDataInterface.
+func (userRepo *userRepository) CreateSnapshot(kind string, period string) (entity.LeaderboardSnapshotCore, error) {
+	var snapshot model.LeaderboardSnapshot
+	var users []model.Users
+
+	errTx := userRepo.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		snapshot, users, err = userRepo.createSnapshot(tx, kind, period)
 		return err
+	})
+	if errTx != nil {
+		return entity.LeaderboardSnapshotCore{}, errTx
 	}
-	return nil
+
+	result := entity.LeaderboardSnapshotModelToLeaderboardSnapshotCore(snapshot)
+	result.EntryCount = len(users)
+	return result, nil
+}
+
+// FindAllSnapshot implements entity.UserDataInterface.
+func (userRepo *userRepository) FindAllSnapshot(kind string, period string) ([]entity.LeaderboardSnapshotCore, error) {
+	var snapshots []entity.LeaderboardSnapshotCore
+
+	query := userRepo.db.Table("leaderboard_snapshots").
+		Select("leaderboard_snapshots.id, leaderboard_snapshots.kind, leaderboard_snapshots.period, leaderboard_snapshots.created_at, " +
+			"(SELECT COUNT(*) FROM leaderboard_entries WHERE leaderboard_entries.snapshot_id = leaderboard_snapshots.id) AS entry_count")
+	if kind != "" {
+		query = query.Where("leaderboard_snapshots.kind = ?", kind)
+	}
+	if period != "" {
+		query = query.Where("leaderboard_snapshots.period = ?", period)
+	}
+
+	errData := query.Order("leaderboard_snapshots.created_at desc").Scan(&snapshots).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	return snapshots, nil
+}
+
+// FindSnapshotById implements entity.UserDataInterface.
+func (userRepo *userRepository) FindSnapshotById(id string) (entity.LeaderboardSnapshotCore, error) {
+	snapshot := model.LeaderboardSnapshot{}
+
+	tx := userRepo.db.Where("id = ?", id).First(&snapshot)
+	if tx.Error != nil {
+		return entity.LeaderboardSnapshotCore{}, tx.Error
+	}
+
+	var count int64
+	errCount := userRepo.db.Model(&model.LeaderboardEntry{}).Where("snapshot_id = ?", id).Count(&count).Error
+	if errCount != nil {
+		return entity.LeaderboardSnapshotCore{}, errCount
+	}
+
+	result := entity.LeaderboardSnapshotModelToLeaderboardSnapshotCore(snapshot)
+	result.EntryCount = int(count)
+	return result, nil
+}
+
+// FindSnapshotEntries implements entity.UserDataInterface.
+func (userRepo *userRepository) FindSnapshotEntries(snapshotId string, classId string, schoolId string) ([]entity.LeaderboardEntryCore, error) {
+	var entries []model.LeaderboardEntry
+
+	query := userRepo.db.Where("snapshot_id = ?", snapshotId)
+	if classId != "" {
+		query = query.Where("class_id = ?", classId)
+	}
+	if schoolId != "" {
+		query = query.Where("school_id = ?", schoolId)
+	}
+
+	errData := query.Order("position").Order("name").Find(&entries).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataResponse := []entity.LeaderboardEntryCore{}
+	for _, v := range entries {
+		dataResponse = append(dataResponse, entity.LeaderboardEntryModelToLeaderboardEntryCore(v))
+	}
+	return dataResponse, nil
+}
+
+// GetPeriodRank implements entity.UserDataInterface.
+//
+// The ranking is rebuilt from the point ledger, summing every entry made
+// in [from, to) except resets.
+func (userRepo *userRepository) GetPeriodRank(filter entity.RankFilter, from time.Time, to time.Time) ([]entity.UserCore, error) {
+	var dataUser []entity.UserCore
+
+	query := userRepo.db.Table("users").
+		Select("users.id, users.name, users.class, users.school_id, users.class_id, COALESCE(SUM(user_points.point_delta), 0) AS point").
+		Joins("LEFT JOIN user_points ON user_points.user_id = users.id AND user_points.type <> ? AND user_points.created_at >= ? AND user_points.created_at < ?",
+			entity.PointTypeReset, from, to).
+		Where("users.role = ?", "user")
+
+	query, errScope := userRepo.rankScope(query, filter)
+	if errScope != nil {
+		return nil, errScope
+	}
+
+	errData := query.
+		Group("users.id, users.name, users.class, users.school_id, users.class_id").
+		Order("point desc").Order("users.name").
+		Scan(&dataUser).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	return dataUser, nil
+}
+
+// GetRankTrajectory implements entity.UserDataInterface.
+func (userRepo *userRepository) GetRankTrajectory(userId string, kind string) ([]entity.RankTrajectoryCore, error) {
+	var trajectory []entity.RankTrajectoryCore
+
+	query := userRepo.db.Table("leaderboard_entries").
+		Select("leaderboard_snapshots.id AS snapshot_id, leaderboard_snapshots.kind, leaderboard_snapshots.period, leaderboard_snapshots.created_at, " +
+			"leaderboard_entries.position, leaderboard_entries.point, leaderboard_entries.total_point, " +
+			"(SELECT COUNT(*) FROM leaderboard_entries e WHERE e.snapshot_id = leaderboard_snapshots.id) AS participants").
+		Joins("JOIN leaderboard_snapshots ON leaderboard_snapshots.id = leaderboard_entries.snapshot_id").
+		Where("leaderboard_entries.user_id = ?", userId)
+	if kind != "" {
+		query = query.Where("leaderboard_snapshots.kind = ?", kind)
+	}
+
+	errData := query.Order("leaderboard_snapshots.created_at").Scan(&trajectory).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	return trajectory, nil
+}
+
+// ResetPoint implements entity.UserDataInterface.
+func (userRepo *userRepository) ResetPoint(kind string, period string, trigger string) (entity.PointResetCore, error) {
+	reset := model.PointReset{
+		Id:      uuid.NewString(),
+		Kind:    kind,
+		Period:  period,
+		Trigger: trigger,
+	}
+
+	errTx := userRepo.db.Transaction(func(tx *gorm.DB) error {
+		//the unique period index makes a concurrent second reset fail here
+		if err := tx.Create(&reset).Error; err != nil {
+			return err
+		}
+
+		snapshot, _, err := userRepo.createSnapshot(tx, kind, period)
+		if err != nil {
+			return err
+		}
+
+		//every balance is cleared by the same statements, the ledger
+		//entries keep the balances after the reset
+		column, taskName := "point", "Monthly reset "+period
+		deltas := "-point, 0, 0, total_point"
+		if kind == entity.ResetAnnual {
+			column, taskName = "total_point", "Annual reset "+period
+			deltas = "0, -total_point, point, 0"
+		}
+
+		now := time.Now()
+		errEntry := tx.Exec("INSERT INTO user_points (id, user_id, type, task_name, source_id, point, point_delta, total_point_delta, point_balance, total_point_balance, created_at, updated_at) "+
+			"SELECT "+newIdSQL(tx)+", id, ?, ?, ?, "+column+", "+deltas+", ?, ? FROM users WHERE role = ? AND "+column+" <> 0",
+			entity.PointTypeReset, taskName, reset.Id, now, now, "user").Error
+		if errEntry != nil {
+			return errEntry
+		}
+
+		//the balances are changed by the posted deltas, like PostPointEntry
+		//does, so a concurrent entry isn't lost
+		errUpdate := tx.Exec("UPDATE users SET point = point + (SELECT p.point_delta FROM user_points p WHERE p.source_id = ? AND p.user_id = users.id), "+
+			"total_point = total_point + (SELECT p.total_point_delta FROM user_points p WHERE p.source_id = ? AND p.user_id = users.id) "+
+			"WHERE id IN (SELECT user_id FROM user_points WHERE source_id = ?)", reset.Id, reset.Id, reset.Id).Error
+		if errUpdate != nil {
+			return errUpdate
+		}
+
+		var cleared struct {
+			Count int
+			Total int
+		}
+		errCount := tx.Model(&model.UserPoint{}).Select("COUNT(*) AS count, COALESCE(SUM(point), 0) AS total").
+			Where("source_id = ?", reset.Id).Scan(&cleared).Error
+		if errCount != nil {
+			return errCount
+		}
+		reset.UserCount, reset.ClearedPoint = cleared.Count, cleared.Total
+
+		reset.SnapshotId = snapshot.Id
+		return tx.Model(&reset).Updates(map[string]any{
+			"snapshot_id":   reset.SnapshotId,
+			"user_count":    reset.UserCount,
+			"cleared_point": reset.ClearedPoint,
+		}).Error
+	})
+	if errTx != nil {
+		return entity.PointResetCore{}, errTx
+	}
+
+	return entity.PointResetModelToPointResetCore(reset), nil
+}
+
+// newIdSQL is the SQL expression of a new random id on the database of tx,
+// for rows inserted by INSERT ... SELECT.
+func newIdSQL(tx *gorm.DB) string {
+	switch tx.Dialector.Name() {
+	case "mysql":
+		return "UUID()"
+	case "postgres":
+		return "gen_random_uuid()::text"
+	}
+	return "lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || " +
+		"substr('89ab', 1 + abs(random()) % 4, 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))"
+}
+
+// FindPointReset implements entity.UserDataInterface.
+func (userRepo *userRepository) FindPointReset(kind string, period string) (entity.PointResetCore, error) {
+	reset := model.PointReset{}
+
+	tx := userRepo.db.Where("kind = ? AND period = ?", kind, period).First(&reset)
+	if tx.Error != nil {
+		return entity.PointResetCore{}, tx.Error
+	}
+
+	return entity.PointResetModelToPointResetCore(reset), nil
+}
+
+// FindAllPointReset implements entity.UserDataInterface.
+func (userRepo *userRepository) FindAllPointReset() ([]entity.PointResetCore, error) {
+	var resets []model.PointReset
+
+	errData := userRepo.db.Order("created_at desc").Find(&resets).Error
+	if errData != nil {
+		return nil, errData
+	}
+
+	dataResponse := []entity.PointResetCore{}
+	for _, v := range resets {
+		dataResponse = append(dataResponse, entity.PointResetModelToPointResetCore(v))
+	}
+	return dataResponse, nil
 }
 
 // GetAllUserPointHistory implements entity.UserDataInterface.
 func (userRepo *userRepository) GetAllUserPointHistory() ([]entity.UserPointCore, error) {
 	var userPoint []model.UserPoint
-	userRepo.db.Find(&userPoint)
+	userRepo.db.Order("created_at desc").Find(&userPoint)
 
 	dataUser := entity.ListUserPointModelToListUserPointCore(userPoint)
 	return dataUser, nil
@@ -391,7 +826,7 @@ func (userRepo *userRepository) GetSpecificUserPointHistory(id string) (entity.U
 // GetUserPointHistory implements entity.UserDataInterface.
 func (userRepo *userRepository) GetUserPointHistory(id string) ([]entity.UserPointCore, error) {
 	var userPoint []model.UserPoint
-	userRepo.db.Where("user_id=?", id).Find(&userPoint)
+	userRepo.db.Where("user_id=?", id).Order("created_at desc").Find(&userPoint)
 
 	datauserPoint := entity.ListUserPointModelToListUserPointCore(userPoint)
 	return datauserPoint, nil
@@ -399,19 +834,372 @@ func (userRepo *userRepository) GetUserPointHistory(id string) ([]entity.UserPoi
 
 // PostUserPointHistory implements entity.UserDataInterface.
 func (userRepo *userRepository) PostUserPointHistory(data entity.UserPointCore) error {
-	println("masuk kesini")
+	return userRepo.db.Transaction(func(tx *gorm.DB) error {
+		_, err := userRepo.PostPointEntry(tx, data)
+		return err
+	})
+}
+
+// PostPointEntry implements entity.UserDataInterface.
+//
+// The balances are changed with a relative UPDATE so concurrent entries for
+// the same user never overwrite each other. Callers pass their own
+// transaction so the entry commits or rolls back together with the status
+// change that caused it.
+func (userRepo *userRepository) PostPointEntry(tx *gorm.DB, data entity.UserPointCore) (entity.UserPointCore, error) {
 	newUUID, UUIDerr := uuid.NewRandom()
 	if UUIDerr != nil {
-		return UUIDerr
+		return entity.UserPointCore{}, UUIDerr
+	}
+
+	update := tx.Model(&model.Users{}).Where("id = ?", data.UserId)
+
+	// spending points must never overdraw the balance
+	spending := data.Type == entity.PointTypeReward && data.TotalPointDelta < 0
+	if spending {
+		update = update.Where("total_point >= ?", -data.TotalPointDelta)
+	}
+
+	result := update.Updates(map[string]interface{}{
+		"point":       gorm.Expr("point + ?", data.PointDelta),
+		"total_point": gorm.Expr("total_point + ?", data.TotalPointDelta),
+	})
+	if result.Error != nil {
+		return entity.UserPointCore{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if spending {
+			return entity.UserPointCore{}, errors.New("not enough point")
+		}
+		return entity.UserPointCore{}, errors.New("user not found")
+	}
+
+	var userData model.Users
+	errUser := tx.Where("id = ?", data.UserId).First(&userData).Error
+	if errUser != nil {
+		return entity.UserPointCore{}, errUser
 	}
 
 	data.Id = newUUID.String()
-	dataUser := entity.UserPointCoreToUserPointModel(data)
+	data.PointBalance = userData.Point
+	data.TotalPointBalance = userData.TotalPoint
+	dataPoint := entity.UserPointCoreToUserPointModel(data)
+
+	errCreate := tx.Create(&dataPoint).Error
+	if errCreate != nil {
+		return entity.UserPointCore{}, errCreate
+	}
+
+	return entity.UserPointModelToUserPointCore(dataPoint), nil
+}
+
+// CreateRefreshToken implements entity.UserDataInterface.
+func (userRepo *userRepository) CreateRefreshToken(userId string, familyId string) (string, error) {
+	token, tokenHash, err := utils.CreateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	id := uuid.NewString()
+	if familyId == "" {
+		familyId = id
+	}
+
+	data := model.RefreshToken{
+		Id:        id,
+		UserId:    userId,
+		FamilyId:  familyId,
+		TokenHash: tokenHash,
+		ExpiredAt: time.Now().Add(utils.RefreshTokenDuration),
+	}
+
+	errCreate := userRepo.db.Create(&data).Error
+	if errCreate != nil {
+		return "", errCreate
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken implements entity.UserDataInterface.
+func (userRepo *userRepository) RotateRefreshToken(token string) (entity.UserCore, string, error) {
+	var oldToken model.RefreshToken
+	errData := userRepo.db.Where("token_hash = ?", utils.HashRefreshToken(token)).First(&oldToken).Error
+	if errData != nil {
+		return entity.UserCore{}, "", errors.New("invalid refresh token")
+	}
+
+	if oldToken.RevokedAt != nil {
+		//a rotated token is used again, the chain may be stolen
+		now := time.Now()
+		userRepo.db.Model(&model.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", oldToken.FamilyId).
+			Update("revoked_at", &now)
+		return entity.UserCore{}, "", errors.New("refresh token already used")
+	}
+
+	if time.Now().After(oldToken.ExpiredAt) {
+		return entity.UserCore{}, "", errors.New("refresh token expired")
+	}
+
+	newToken, tokenHash, err := utils.CreateRefreshToken()
+	if err != nil {
+		return entity.UserCore{}, "", err
+	}
+
+	var user model.Users
+	errTx := userRepo.db.Transaction(func(tx *gorm.DB) error {
+		errUser := tx.Where("id = ?", oldToken.UserId).First(&user).Error
+		if errUser != nil {
+			return errors.New("user not found")
+		}
+
+		data := model.RefreshToken{
+			Id:        uuid.NewString(),
+			UserId:    oldToken.UserId,
+			FamilyId:  oldToken.FamilyId,
+			TokenHash: tokenHash,
+			ExpiredAt: time.Now().Add(utils.RefreshTokenDuration),
+		}
+
+		now := time.Now()
+		update := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldToken.Id).
+			Updates(map[string]any{"revoked_at": &now, "replaced_by": data.Id})
+		if update.Error != nil {
+			return update.Error
+		}
 
-	tx := userRepo.db.Create(&dataUser)
+		if update.RowsAffected == 0 {
+			return errors.New("refresh token already used")
+		}
+
+		return tx.Create(&data).Error
+	})
+	if errTx != nil {
+		return entity.UserCore{}, "", errTx
+	}
+
+	userCore := entity.UserCore{
+		ID:       user.ID,
+		Name:     user.Name,
+		Email:    user.Email,
+		Role:     user.Role,
+		Religion: user.Religion,
+	}
+
+	return userCore, newToken, nil
+}
+
+// RevokeRefreshToken implements entity.UserDataInterface.
+func (userRepo *userRepository) RevokeRefreshToken(token string) error {
+	var data model.RefreshToken
+	errData := userRepo.db.Where("token_hash = ?", utils.HashRefreshToken(token)).First(&data).Error
+	if errData != nil {
+		return errors.New("invalid refresh token")
+	}
+
+	now := time.Now()
+	return userRepo.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", data.FamilyId).
+		Update("revoked_at", &now).Error
+}
+
+// RevokeToken implements entity.UserDataInterface.
+func (userRepo *userRepository) RevokeToken(tokenId string, userId string, expiredAt time.Time) error {
+	//expired tokens are rejected by the signature check anyway
+	userRepo.db.Where("expired_at < ?", time.Now()).Delete(&model.RevokedToken{})
+
+	data := model.RevokedToken{
+		Id:        tokenId,
+		UserId:    userId,
+		ExpiredAt: expiredAt,
+	}
+
+	return userRepo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&data).Error
+}
+
+// RevokeUserSessions implements entity.UserDataInterface.
+func (userRepo *userRepository) RevokeUserSessions(userId string) error {
+	now := time.Now()
+
+	return userRepo.db.Transaction(func(tx *gorm.DB) error {
+		update := tx.Model(&model.Users{}).Where("id = ?", userId).Update("sessions_revoked_at", &now)
+		if update.Error != nil {
+			return update.Error
+		}
+
+		if update.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+
+		return tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userId).
+			Update("revoked_at", &now).Error
+	})
+}
+
+// IsTokenRevoked implements entity.UserDataInterface.
+func (userRepo *userRepository) IsTokenRevoked(tokenId string, userId string, issuedAt time.Time) (bool, error) {
+	if tokenId != "" {
+		var count int64
+		err := userRepo.db.Model(&model.RevokedToken{}).Where("id = ?", tokenId).Count(&count).Error
+		if err != nil {
+			return false, err
+		}
+
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	var user model.Users
+	err := userRepo.db.Select("id", "sessions_revoked_at").Where("id = ?", userId).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	if user.SessionsRevokedAt != nil && issuedAt.UnixMilli() <= user.SessionsRevokedAt.UnixMilli() {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// FindUserByEmail implements entity.UserDataInterface. Emails are
+// compared ignoring case.
+func (userRepo *userRepository) FindUserByEmail(email string) (entity.UserCore, error) {
+	var data model.Users
+	errData := userRepo.db.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).First(&data).Error
+	if errData != nil {
+		return entity.UserCore{}, errData
+	}
+
+	return entity.UserCore{
+		ID:    data.ID,
+		Name:  data.Name,
+		Email: data.Email,
+		Role:  data.Role,
+	}, nil
+}
+
+// CreatePasswordReset implements entity.UserDataInterface.
+func (userRepo *userRepository) CreatePasswordReset(data entity.PasswordResetCore) error {
+	now := time.Now()
+
+	return userRepo.db.Transaction(func(tx *gorm.DB) error {
+		//only the newest code can be used
+		errOld := tx.Model(&model.PasswordReset{}).
+			Where("user_id = ? AND used_at IS NULL", data.UserId).
+			Update("used_at", &now).Error
+		if errOld != nil {
+			return errOld
+		}
+
+		reset := model.PasswordReset{
+			Id:        uuid.NewString(),
+			UserId:    data.UserId,
+			CodeHash:  data.CodeHash,
+			ExpiredAt: data.ExpiredAt,
+		}
+
+		return tx.Create(&reset).Error
+	})
+}
+
+// FindActivePasswordReset implements entity.UserDataInterface.
+func (userRepo *userRepository) FindActivePasswordReset(userId string) (entity.PasswordResetCore, error) {
+	var data model.PasswordReset
+	errData := userRepo.db.
+		Where("user_id = ? AND used_at IS NULL AND expired_at > ?", userId, time.Now()).
+		Order("created_at desc").
+		First(&data).Error
+	if errData != nil {
+		return entity.PasswordResetCore{}, errData
+	}
+
+	return entity.PasswordResetModelToPasswordResetCore(data), nil
+}
+
+// AddPasswordResetAttempt implements entity.UserDataInterface. It is
+// false when the code already had maxAttempts, the count is checked and
+// raised in one statement so concurrent guesses can't exceed it.
+func (userRepo *userRepository) AddPasswordResetAttempt(id string, maxAttempts int) (bool, error) {
+	tx := userRepo.db.Model(&model.PasswordReset{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+// VerifyPasswordReset implements entity.UserDataInterface.
+func (userRepo *userRepository) VerifyPasswordReset(id string, resetTokenHash string) error {
+	now := time.Now()
+
+	tx := userRepo.db.Model(&model.PasswordReset{}).
+		Where("id = ? AND used_at IS NULL AND verified_at IS NULL", id).
+		Updates(map[string]any{"verified_at": &now, "reset_token_hash": resetTokenHash})
 	if tx.Error != nil {
 		return tx.Error
 	}
 
+	if tx.RowsAffected == 0 {
+		return errors.New("reset code already used")
+	}
+
 	return nil
 }
+
+// FindPasswordResetByToken implements entity.UserDataInterface.
+func (userRepo *userRepository) FindPasswordResetByToken(resetTokenHash string) (entity.PasswordResetCore, error) {
+	var data model.PasswordReset
+	errData := userRepo.db.Where("reset_token_hash = ?", resetTokenHash).First(&data).Error
+	if errData != nil {
+		return entity.PasswordResetCore{}, errData
+	}
+
+	return entity.PasswordResetModelToPasswordResetCore(data), nil
+}
+
+// ResetPassword implements entity.UserDataInterface.
+func (userRepo *userRepository) ResetPassword(resetId string, userId string, password string) error {
+	hashPassword, err := bcrypt.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	return userRepo.db.Transaction(func(tx *gorm.DB) error {
+		used := tx.Model(&model.PasswordReset{}).
+			Where("id = ? AND used_at IS NULL", resetId).
+			Update("used_at", &now)
+		if used.Error != nil {
+			return used.Error
+		}
+
+		if used.RowsAffected == 0 {
+			return errors.New("reset token already used")
+		}
+
+		//a new password logs out every session
+		errUser := tx.Model(&model.Users{}).Where("id = ?", userId).Updates(map[string]any{
+			"password":            hashPassword,
+			"sessions_revoked_at": &now,
+		}).Error
+		if errUser != nil {
+			return errUser
+		}
+
+		return tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userId).
+			Update("revoked_at", &now).Error
+	})
+}
@@ -1,23 +1,43 @@
 package service
 
 import (
+	"bytes"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"math/big"
 	"mime/multipart"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 	"tugaskita/features/user/entity"
 	crypt "tugaskita/utils/bcrypt"
+	"tugaskita/utils/event"
+	"tugaskita/utils/imageproc"
+	utils "tugaskita/utils/jwt"
+	"tugaskita/utils/mail"
+	"tugaskita/utils/spreadsheet"
 )
 
 type userUseCase struct {
 	userRepository entity.UserDataInterface
+	mailer         mail.Sender
 }
 
-func New(userUCase entity.UserDataInterface) entity.UserUseCaseInterface {
+func New(userUCase entity.UserDataInterface, mailer mail.Sender) entity.UserUseCaseInterface {
 	return &userUseCase{
 		userRepository: userUCase,
+		mailer:         mailer,
 	}
 }
 
+const (
+	resetCodeDuration    = 15 * time.Minute
+	resetCodeCooldown    = time.Minute
+	resetCodeMaxAttempts = 5
+)
+
 // DeleteUser implements entity.UserUseCaseInterface.
 func (userUC *userUseCase) DeleteUser(id string) (err error) {
 	if id == "" {
@@ -38,21 +58,186 @@ func (userUC *userUseCase) DeleteUser(id string) (err error) {
 }
 
 // Login implements entity.UserUseCaseInterface.
-func (userUC *userUseCase) Login(email string, password string) (entity.UserCore, string, error) {
+func (userUC *userUseCase) Login(email string, password string) (entity.UserCore, string, string, error) {
 	if email == "" || password == "" {
-		return entity.UserCore{}, "", errors.New("error, email or password can't be empty")
+		return entity.UserCore{}, "", "", errors.New("error, email or password can't be empty")
 	}
 
 	loginData, token, err := userUC.userRepository.Login(email, password)
 	if err != nil {
-		return entity.UserCore{}, "", err
+		return entity.UserCore{}, "", "", err
 	}
 
-	if crypt.CheckPasswordHash(loginData.Password, password) {
-		return loginData, token, nil
+	if !crypt.CheckPasswordHash(loginData.Password, password) {
+		return entity.UserCore{}, "", "", errors.New("Login Failed")
 	}
 
-	return entity.UserCore{}, "", errors.New("Login Failed")
+	refreshToken, errRefresh := userUC.userRepository.CreateRefreshToken(loginData.ID, "")
+	if errRefresh != nil {
+		return entity.UserCore{}, "", "", errors.New("failed create refresh token")
+	}
+
+	return loginData, token, refreshToken, nil
+}
+
+// RefreshToken implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) RefreshToken(token string) (string, string, error) {
+	if token == "" {
+		return "", "", errors.New("refresh token can't be empty")
+	}
+
+	userData, refreshToken, err := userUC.userRepository.RotateRefreshToken(token)
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken, errToken := utils.CreateToken(userData.ID, userData.Role, userData.Religion)
+	if errToken != nil {
+		return "", "", errToken
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// Logout implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) Logout(userId string, tokenId string, expiredAt time.Time, refreshToken string) error {
+	if tokenId != "" {
+		err := userUC.userRepository.RevokeToken(tokenId, userId, expiredAt)
+		if err != nil {
+			return errors.New("failed revoke token")
+		}
+	}
+
+	if refreshToken != "" {
+		err := userUC.userRepository.RevokeRefreshToken(refreshToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RevokeUserSessions implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) RevokeUserSessions(userId string) error {
+	if userId == "" {
+		return errors.New("insert user id")
+	}
+
+	return userUC.userRepository.RevokeUserSessions(userId)
+}
+
+// ForgotPassword implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) ForgotPassword(email string) error {
+	if email == "" {
+		return errors.New("email can't be empty")
+	}
+
+	//unknown emails are not reported so accounts can't be guessed
+	userData, errUser := userUC.userRepository.FindUserByEmail(email)
+	if errUser != nil {
+		return nil
+	}
+
+	//a code sent moments ago is kept, reported like a sent one for the
+	//same reason
+	active, errActive := userUC.userRepository.FindActivePasswordReset(userData.ID)
+	if errActive == nil && time.Since(active.CreatedAt) < resetCodeCooldown {
+		return nil
+	}
+
+	number, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return err
+	}
+	code := fmt.Sprintf("%06d", number.Int64())
+
+	codeHash, err := crypt.HashPassword(code)
+	if err != nil {
+		return err
+	}
+
+	errReset := userUC.userRepository.CreatePasswordReset(entity.PasswordResetCore{
+		UserId:    userData.ID,
+		CodeHash:  codeHash,
+		ExpiredAt: time.Now().Add(resetCodeDuration),
+	})
+	if errReset != nil {
+		return errors.New("failed create reset code")
+	}
+
+	errMail := userUC.mailer.Send(mail.Message{
+		To:      userData.Email,
+		Subject: "TugasKita password reset code",
+		Body: fmt.Sprintf("Hi %s,\n\nYour password reset code is %s.\nThe code expires in %d minutes.\n\nIgnore this email if you did not ask to reset your password.",
+			userData.Name, code, int(resetCodeDuration.Minutes())),
+	})
+	if errMail != nil {
+		return errors.New("failed send reset code")
+	}
+
+	return nil
+}
+
+// VerifyResetCode implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) VerifyResetCode(email string, code string) (string, error) {
+	if email == "" || code == "" {
+		return "", errors.New("email or code can't be empty")
+	}
+
+	userData, errUser := userUC.userRepository.FindUserByEmail(email)
+	if errUser != nil {
+		return "", errors.New("invalid reset code")
+	}
+
+	reset, errReset := userUC.userRepository.FindActivePasswordReset(userData.ID)
+	if errReset != nil || reset.VerifiedAt != nil {
+		return "", errors.New("invalid reset code")
+	}
+
+	//the attempt is counted before the code is compared
+	allowed, errAttempt := userUC.userRepository.AddPasswordResetAttempt(reset.Id, resetCodeMaxAttempts)
+	if errAttempt != nil {
+		return "", errors.New("failed verify reset code")
+	}
+
+	if !allowed {
+		return "", errors.New("too many attempts, request a new code")
+	}
+
+	if !crypt.CheckPasswordHash(reset.CodeHash, code) {
+		return "", errors.New("invalid reset code")
+	}
+
+	resetToken, resetTokenHash, err := utils.CreateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	errVerify := userUC.userRepository.VerifyPasswordReset(reset.Id, resetTokenHash)
+	if errVerify != nil {
+		return "", errVerify
+	}
+
+	return resetToken, nil
+}
+
+// ResetPassword implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) ResetPassword(resetToken string, password string) error {
+	if resetToken == "" || password == "" {
+		return errors.New("reset token or password can't be empty")
+	}
+
+	reset, err := userUC.userRepository.FindPasswordResetByToken(utils.HashRefreshToken(resetToken))
+	if err != nil || reset.VerifiedAt == nil || reset.UsedAt != nil {
+		return errors.New("invalid reset token")
+	}
+
+	if time.Now().After(reset.ExpiredAt) {
+		return errors.New("reset token expired")
+	}
+
+	return userUC.userRepository.ResetPassword(reset.Id, reset.UserId, password)
 }
 
 // ReadSpecificUser implements entity.UserUseCaseInterface.
@@ -69,19 +254,37 @@ func (userUC *userUseCase) ReadSpecificUser(id string) (user entity.UserCore, er
 	return user, nil
 }
 
-// Register implements entity.UserUseCaseInterface.
-func (userUC *userUseCase) Register(data entity.UserCore, image *multipart.FileHeader) (row int, err error) {
+// validateRegister holds the rules every new account must pass, it is
+// shared by Register and ImportUser.
+func (userUC *userUseCase) validateRegister(data entity.UserCore) error {
 	if data.Email == "" || data.Password == "" {
-		return 0, errors.New("error, email or password can't be empty")
+		return errors.New("error, email or password can't be empty")
 	}
 	emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	match, _ := regexp.MatchString(emailRegex, data.Email)
 	if !match {
-		return 0, errors.New("error. email format not valid")
+		return errors.New("error. email format not valid")
+	}
+
+	_, errEmail := userUC.userRepository.FindUserByEmail(data.Email)
+	if errEmail == nil {
+		return errors.New("email already registered")
+	}
+
+	return userUC.userRepository.CheckSchoolClass(data.School, data.Class)
+}
+
+// Register implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) Register(data entity.UserCore, image *multipart.FileHeader) (row int, err error) {
+	data.Email = strings.ToLower(strings.TrimSpace(data.Email))
+
+	errValidate := userUC.validateRegister(data)
+	if errValidate != nil {
+		return 0, errValidate
 	}
 
-	if image != nil && image.Size > 10*1024*1024 {
-		return 0, errors.New("image file size should be less than 10 MB")
+	if err := imageproc.Check(image); err != nil {
+		return 0, err
 	}
 
 	errRegister, err := userUC.userRepository.Register(data, image)
@@ -92,6 +295,192 @@ func (userUC *userUseCase) Register(data entity.UserCore, image *multipart.FileH
 	return errRegister, nil
 }
 
+const (
+	importMaxRows      = 5000
+	passwordCharacters = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+var importColumns = []string{"name", "email", "class", "school", "religion", "address"}
+
+func generatePassword(length int) (string, error) {
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(passwordCharacters))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordCharacters[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// ImportUser implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) ImportUser(file *multipart.FileHeader) (entity.UserImportResult, error) {
+	result := entity.UserImportResult{
+		Created: []entity.UserImportAccount{},
+		Errors:  []entity.UserImportRowError{},
+	}
+
+	if file == nil {
+		return result, errors.New("file can't be empty")
+	}
+
+	if file.Size > 10*1024*1024 {
+		return result, errors.New("file size should be less than 10 MB")
+	}
+
+	rows, err := spreadsheet.ReadFile(file)
+	if err != nil {
+		return result, err
+	}
+
+	if len(rows) < 2 {
+		return result, errors.New("file has no student rows")
+	}
+
+	if len(rows)-1 > importMaxRows {
+		return result, fmt.Errorf("file can't have more than %d rows", importMaxRows)
+	}
+
+	//the first row is the header, columns can be in any order
+	columns := map[string]int{}
+	for i, v := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(v))] = i
+	}
+
+	for _, v := range []string{"name", "email"} {
+		if _, found := columns[v]; !found {
+			return result, errors.New("column " + v + " is required")
+		}
+	}
+
+	cell := func(row []string, column string) string {
+		i, found := columns[column]
+		if !found || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	users := []entity.UserCore{}
+	rowNumbers := []int{}
+	emails := map[string]int{}
+	for i, row := range rows[1:] {
+		rowNumber := i + 2
+
+		values := map[string]string{}
+		empty := true
+		for _, column := range importColumns {
+			values[column] = cell(row, column)
+			if values[column] != "" {
+				empty = false
+			}
+		}
+		if empty {
+			continue
+		}
+
+		password, errPassword := generatePassword(10)
+		if errPassword != nil {
+			return result, errPassword
+		}
+
+		data := entity.UserCore{
+			Name:     values["name"],
+			Email:    strings.ToLower(values["email"]),
+			Class:    values["class"],
+			School:   values["school"],
+			Religion: values["religion"],
+			Address:  values["address"],
+			Password: password,
+		}
+
+		if data.Name == "" {
+			result.Errors = append(result.Errors, entity.UserImportRowError{Row: rowNumber, Email: data.Email, Message: "name can't be empty"})
+			continue
+		}
+
+		if errValidate := userUC.validateRegister(data); errValidate != nil {
+			result.Errors = append(result.Errors, entity.UserImportRowError{Row: rowNumber, Email: data.Email, Message: errValidate.Error()})
+			continue
+		}
+
+		if firstRow, found := emails[data.Email]; found {
+			result.Errors = append(result.Errors, entity.UserImportRowError{Row: rowNumber, Email: data.Email, Message: fmt.Sprintf("email already used in row %d", firstRow)})
+			continue
+		}
+		emails[data.Email] = rowNumber
+
+		users = append(users, data)
+		rowNumbers = append(rowNumbers, rowNumber)
+	}
+
+	//nothing is created unless every row is valid
+	if len(result.Errors) > 0 {
+		return result, errors.New("some rows are not valid")
+	}
+
+	if len(users) == 0 {
+		return result, errors.New("file has no student rows")
+	}
+
+	created, err := userUC.userRepository.ImportUsers(users)
+	if err != nil {
+		return result, errors.New("failed import users: " + err.Error())
+	}
+
+	for i, v := range created {
+		result.Created = append(result.Created, entity.UserImportAccount{
+			Row:      rowNumbers[i],
+			ID:       v.ID,
+			Name:     v.Name,
+			Email:    v.Email,
+			Password: v.Password,
+		})
+	}
+
+	return result, nil
+}
+
+// ExportUser implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) ExportUser(format string) ([]byte, error) {
+	if format == "" {
+		format = spreadsheet.CSV
+	}
+
+	if format != spreadsheet.CSV && format != spreadsheet.XLSX {
+		return nil, errors.New("format must be csv or xlsx")
+	}
+
+	users, err := userUC.userRepository.ReadAllUser()
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	rows := [][]string{{"id", "name", "email", "class", "school", "religion", "address", "point", "total_point"}}
+	for _, v := range users {
+		rows = append(rows, []string{
+			v.ID,
+			v.Name,
+			v.Email,
+			v.Class,
+			v.School,
+			v.Religion,
+			v.Address,
+			strconv.Itoa(v.Point),
+			strconv.Itoa(v.TotalPoint),
+		})
+	}
+
+	var buf bytes.Buffer
+	errWrite := spreadsheet.Write(&buf, format, rows)
+	if errWrite != nil {
+		return nil, errWrite
+	}
+
+	return buf.Bytes(), nil
+}
+
 // ReadAllUser implements entity.UserUseCaseInterface.
 func (userUC *userUseCase) ReadAllUser() ([]entity.UserCore, error) {
 	users, err := userUC.userRepository.ReadAllUser()
@@ -103,15 +492,149 @@ func (userUC *userUseCase) ReadAllUser() ([]entity.UserCore, error) {
 }
 
 // GetRankUser implements entity.UserUseCaseInterface.
-func (userUC *userUseCase) GetRankUser() ([]entity.UserCore, error) {
-	users, err := userUC.userRepository.GetRankUser()
+func (userUC *userUseCase) GetRankUser(userId string, filter entity.RankFilter) ([]entity.UserCore, error) {
+	filter, errFilter := userUC.resolveRankFilter(userId, filter)
+	if errFilter != nil {
+		return nil, errFilter
+	}
+
+	users, err := userUC.userRepository.GetRankUser(filter)
 	if err != nil {
-		return nil, errors.New("error get data")
+		return nil, errors.New("error get data: " + err.Error())
+	}
+
+	return users, nil
+}
+
+// resolveRankFilter validates the leaderboard scope, without an explicit
+// target it ranks within the user's own class or school.
+func (userUC *userUseCase) resolveRankFilter(userId string, filter entity.RankFilter) (entity.RankFilter, error) {
+	switch filter.Scope {
+	case "":
+	case entity.RankScopeClass, entity.RankScopeGrade, entity.RankScopeSchool:
+		if filter.ClassId == "" && filter.SchoolId == "" {
+			userData, errUser := userUC.userRepository.ReadSpecificUser(userId)
+			if errUser != nil {
+				return filter, errors.New("user not found")
+			}
+			filter.ClassId = userData.ClassId
+			if filter.Scope == entity.RankScopeSchool {
+				filter.SchoolId = userData.SchoolId
+			}
+		}
+
+		if filter.Scope == entity.RankScopeClass && filter.ClassId == "" {
+			return filter, errors.New("class_id is required")
+		}
+		if filter.Scope == entity.RankScopeSchool && filter.SchoolId == "" {
+			return filter, errors.New("school_id is required")
+		}
+		if filter.Scope == entity.RankScopeGrade && filter.ClassId == "" && (filter.SchoolId == "" || filter.Grade == 0) {
+			return filter, errors.New("class_id or school_id and grade are required")
+		}
+	default:
+		return filter, errors.New("scope must be class, grade or school")
+	}
+
+	return filter, nil
+}
+
+// parsePeriod returns the bounds of a "2006-01" month or a "2006" year.
+func parsePeriod(period string) (time.Time, time.Time, error) {
+	if from, err := time.ParseInLocation("2006-01", period, time.Local); err == nil {
+		return from, from.AddDate(0, 1, 0), nil
+	}
+
+	if from, err := time.ParseInLocation("2006", period, time.Local); err == nil {
+		return from, from.AddDate(1, 0, 0), nil
+	}
+
+	return time.Time{}, time.Time{}, errors.New("period must use YYYY-MM or YYYY format")
+}
+
+// GetPeriodRank implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) GetPeriodRank(userId string, period string, filter entity.RankFilter) ([]entity.UserCore, error) {
+	from, to, errPeriod := parsePeriod(period)
+	if errPeriod != nil {
+		return nil, errPeriod
+	}
+
+	if from.After(time.Now()) {
+		return nil, errors.New("period has not started yet")
+	}
+
+	filter, errFilter := userUC.resolveRankFilter(userId, filter)
+	if errFilter != nil {
+		return nil, errFilter
+	}
+
+	users, err := userUC.userRepository.GetPeriodRank(filter, from, to)
+	if err != nil {
+		return nil, errors.New("error get data: " + err.Error())
 	}
 
 	return users, nil
 }
 
+// CreateSnapshot implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) CreateSnapshot() (entity.LeaderboardSnapshotCore, error) {
+	data, err := userUC.userRepository.CreateSnapshot(entity.SnapshotManual, entity.ResetPeriod(entity.ResetMonthly, time.Now()))
+	if err != nil {
+		return entity.LeaderboardSnapshotCore{}, errors.New("error create snapshot")
+	}
+
+	return data, nil
+}
+
+// FindAllSnapshot implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) FindAllSnapshot(kind string, period string) ([]entity.LeaderboardSnapshotCore, error) {
+	data, err := userUC.userRepository.FindAllSnapshot(kind, period)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
+// FindSnapshot implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) FindSnapshot(id string, classId string, schoolId string) (entity.LeaderboardSnapshotCore, []entity.LeaderboardEntryCore, error) {
+	snapshot, errSnapshot := userUC.userRepository.FindSnapshotById(id)
+	if errSnapshot != nil {
+		return entity.LeaderboardSnapshotCore{}, nil, errors.New("snapshot not found")
+	}
+
+	entries, err := userUC.userRepository.FindSnapshotEntries(id, classId, schoolId)
+	if err != nil {
+		return entity.LeaderboardSnapshotCore{}, nil, errors.New("error get data")
+	}
+
+	//rank again within the class or school, keeping ties from the snapshot
+	if classId != "" || schoolId != "" {
+		previous := 0
+		for i := range entries {
+			original := entries[i].Rank
+			if i == 0 || original != previous {
+				entries[i].Rank = i + 1
+			} else {
+				entries[i].Rank = entries[i-1].Rank
+			}
+			previous = original
+		}
+	}
+
+	return snapshot, entries, nil
+}
+
+// GetRankTrajectory implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) GetRankTrajectory(userId string, kind string) ([]entity.RankTrajectoryCore, error) {
+	data, err := userUC.userRepository.GetRankTrajectory(userId, kind)
+	if err != nil {
+		return nil, errors.New("error get data")
+	}
+
+	return data, nil
+}
+
 // UpdateSiswa implements entity.UserUseCaseInterface.
 func (userUC *userUseCase) UpdateSiswa(id string, data entity.UserCore, image *multipart.FileHeader) error {
 
@@ -124,10 +647,15 @@ func (userUC *userUseCase) UpdateSiswa(id string, data entity.UserCore, image *m
 		}
 	}
 
-	// Validasi ukuran file gambar jika gambar diunggah
-	if image != nil {
-		if image.Size > 10*1024*1024 {
-			return errors.New("image file size should be less than 10 MB")
+	// Validasi file gambar jika gambar diunggah
+	if err := imageproc.Check(image); err != nil {
+		return err
+	}
+
+	// Sekolah dan kelas harus sudah ada
+	if data.School != "" {
+		if err := userUC.userRepository.CheckSchoolClass(data.School, data.Class); err != nil {
+			return err
 		}
 	}
 
@@ -154,24 +682,51 @@ func (userUC *userUseCase) ChangePassword(id string, data entity.UserCore) error
 	return nil
 }
 
-// AnnualResetPoint implements entity.UserUseCaseInterface.
-func (userUC *userUseCase) AnnualResetPoint() error {
-	err := userUC.userRepository.AnnualResetPoint()
+// ResetPoint implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) ResetPoint(kind string, at time.Time, trigger string) (entity.PointResetCore, error) {
+	if kind != entity.ResetMonthly && kind != entity.ResetAnnual {
+		return entity.PointResetCore{}, errors.New("unknown reset kind")
+	}
+
+	period := entity.ResetPeriod(kind, at)
+	_, errReset := userUC.userRepository.FindPointReset(kind, period)
+	if errReset == nil {
+		return entity.PointResetCore{}, errors.New("point already reset for period " + period)
+	}
+
+	data, err := userUC.userRepository.ResetPoint(kind, period, trigger)
 	if err != nil {
-		return errors.New("error reset point")
+		return entity.PointResetCore{}, errors.New("error reset point")
 	}
 
-	return nil
+	event.Publish(event.Event{
+		Type: event.PointsReset,
+		Data: event.PointReset{Kind: kind, Period: period},
+	})
+
+	return data, nil
+}
+
+// RunScheduledReset implements entity.UserUseCaseInterface. A period that
+// was already reset, e.g. manually or before a restart, is not an error.
+func (userUC *userUseCase) RunScheduledReset(kind string, at time.Time) error {
+	_, errReset := userUC.userRepository.FindPointReset(kind, entity.ResetPeriod(kind, at))
+	if errReset == nil {
+		return nil
+	}
+
+	_, err := userUC.ResetPoint(kind, at, entity.ResetTriggerSchedule)
+	return err
 }
 
-// MonthlyResetPoint implements entity.UserUseCaseInterface.
-func (userUC *userUseCase) MonthlyResetPoint() error {
-	err := userUC.userRepository.MonthlyResetPoint()
+// FindAllPointReset implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) FindAllPointReset() ([]entity.PointResetCore, error) {
+	data, err := userUC.userRepository.FindAllPointReset()
 	if err != nil {
-		return errors.New("error reset point")
+		return nil, errors.New("error get data")
 	}
 
-	return nil
+	return data, nil
 }
 
 // GetAllUserPointHistory implements entity.UserUseCaseInterface.
@@ -208,11 +763,59 @@ func (userUC *userUseCase) GetUserPointHistory(id string) ([]entity.UserPointCor
 	return data, nil
 }
 
+// AdjustPoint implements entity.UserUseCaseInterface.
+func (userUC *userUseCase) AdjustPoint(id string, data entity.UserPointCore) error {
+	if data.PointDelta == 0 && data.TotalPointDelta == 0 {
+		return errors.New("point adjustment can't be 0")
+	}
+
+	if data.TaskName == "" {
+		return errors.New("description can't be empty")
+	}
+
+	_, errFind := userUC.userRepository.ReadSpecificUser(id)
+	if errFind != nil {
+		return errors.New("user not found")
+	}
+
+	point := data.TotalPointDelta
+	if point == 0 {
+		point = data.PointDelta
+	}
+	if point < 0 {
+		point = -point
+	}
+
+	data.UserId = id
+	data.Type = entity.PointTypeAdjustment
+	data.Point = point
+
+	err := userUC.userRepository.PostUserPointHistory(data)
+	if err != nil {
+		return err
+	}
+
+	event.Publish(event.Event{
+		Type:   event.PointsChanged,
+		UserId: data.UserId,
+		Data:   event.Points{Source: data.Type},
+	})
+
+	return nil
+}
+
 // PostUserPointHistory implements entity.UserUseCaseInterface.
 func (userUC *userUseCase) PostUserPointHistory(data entity.UserPointCore) error {
 	err := userUC.userRepository.PostUserPointHistory(data)
 	if err != nil {
 		return err
 	}
+
+	event.Publish(event.Event{
+		Type:   event.PointsChanged,
+		UserId: data.UserId,
+		Data:   event.Points{Source: data.Type},
+	})
+
 	return nil
 }
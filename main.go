@@ -1,28 +1,85 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"syscall"
+	"time"
+	"tugaskita/app/config"
 	"tugaskita/app/database"
 	"tugaskita/app/migration"
 	"tugaskita/app/route"
+	"tugaskita/app/scheduler"
 
-	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
 func main() {
-	godotenv.Load(".env")
-	port, _ := strconv.Atoi(os.Getenv("SERVERPORT"))
-	db := database.Init()
-	migration.InitMigration(db)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	db := database.Init(cfg.Database)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migration.Command(db, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	if cfg.Server.Environment == config.Development {
+		if err := migration.AutoMigrate(db); err != nil {
+			log.Fatalln(err)
+		}
+	} else {
+		pending, err := migration.Pending(db)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("database has %d pending migrations, run \"%s migrate up\"", len(pending), os.Args[0])
+		}
+	}
+
+	if err := migration.Seed(db); err != nil {
+		log.Fatalln(err)
+	}
+
+	store, err := cfg.Storage.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	route.NotificationListener(db)
+	jobs := scheduler.Start(db, cfg, store)
 
 	e := echo.New()
 	e.Use(middleware.CORS())
 
-	route.New(e, db)
+	route.New(e, db, cfg, store)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		if err := e.Start(fmt.Sprintf(":%d", cfg.Server.Port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			e.Logger.Fatal(err)
+		}
+	}()
+	<-ctx.Done()
 
-	e.Logger.Fatal(e.Start(fmt.Sprintf(":%d", port)))
+	//stop taking requests first, then wait for running jobs
+	shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdown); err != nil {
+		log.Println(err)
+	}
+	jobs.Stop()
 }
@@ -0,0 +1,154 @@
+package authz
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	middleware "tugaskita/utils/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Checker loads the permissions granted to a role.
+type Checker interface {
+	RolePermissions(role string) ([]string, error)
+}
+
+type cachedRole struct {
+	permissions map[string]bool
+	expiredAt   time.Time
+}
+
+var (
+	checker  Checker
+	cacheTTL = time.Minute
+
+	mu    sync.RWMutex
+	cache = map[string]cachedRole{}
+)
+
+// SetChecker sets where role permissions are loaded from, it must be
+// called before any route using Require is served.
+func SetChecker(c Checker) {
+	checker = c
+	Invalidate()
+}
+
+// Invalidate drops the cached role permissions, call it after roles or
+// permissions are changed.
+func Invalidate() {
+	mu.Lock()
+	cache = map[string]cachedRole{}
+	mu.Unlock()
+}
+
+func rolePermissions(role string) (map[string]bool, error) {
+	mu.RLock()
+	cached, found := cache[role]
+	mu.RUnlock()
+	if found && time.Now().Before(cached.expiredAt) {
+		return cached.permissions, nil
+	}
+
+	permissions := map[string]bool{}
+	if checker != nil {
+		data, err := checker.RolePermissions(role)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range data {
+			permissions[v] = true
+		}
+	}
+
+	mu.Lock()
+	cache[role] = cachedRole{permissions: permissions, expiredAt: time.Now().Add(cacheTTL)}
+	mu.Unlock()
+
+	return permissions, nil
+}
+
+func tokenRole(c echo.Context) (string, bool) {
+	if c.Get("user") == nil {
+		return "", false
+	}
+
+	_, role, _, err := middleware.ExtractTokenUserId(c)
+	if err != nil {
+		return "", false
+	}
+
+	return role, true
+}
+
+// Can reports whether the role of the logged in user holds the permission.
+func Can(c echo.Context, permission string) bool {
+	role, ok := tokenRole(c)
+	if !ok {
+		return false
+	}
+
+	permissions, err := rolePermissions(role)
+	if err != nil {
+		return false
+	}
+
+	return permissions[permission]
+}
+
+func denied(c echo.Context) error {
+	return c.JSON(http.StatusForbidden, map[string]any{
+		"message": "access denied",
+	})
+}
+
+// Require only lets the request through when the role of the logged in
+// user holds every given permission. It must run after JWTMiddleware.
+func Require(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, ok := tokenRole(c)
+			if !ok {
+				return denied(c)
+			}
+
+			granted, err := rolePermissions(role)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]any{
+					"message": "error check permission",
+					"error":   err.Error(),
+				})
+			}
+
+			for _, v := range permissions {
+				if !granted[v] {
+					return denied(c)
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequireRole only lets the request through when the logged in user has
+// one of the given roles. It must run after JWTMiddleware.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, ok := tokenRole(c)
+			if !ok {
+				return denied(c)
+			}
+
+			for _, v := range roles {
+				if v == role {
+					return next(c)
+				}
+			}
+
+			return denied(c)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package authz
+
+// Permissions known by the application. Roles are granted permissions
+// through the role_permissions table, these are only the names routes
+// ask for.
+const (
+	UserRead   = "user:read"
+	UserManage = "user:manage"
+
+	PointRead   = "point:read"
+	PointAdjust = "point:adjust"
+	PointReset  = "point:reset"
+
+	TaskManage = "task:manage"
+	TaskReview = "task:review"
+	TaskSubmit = "task:submit"
+
+	RewardManage   = "reward:manage"
+	RewardReview   = "reward:review"
+	RewardExchange = "reward:exchange"
+
+	PenaltyManage = "penalty:manage"
+	PenaltyRead   = "penalty:read"
+
+	RoleManage = "role:manage"
+
+	SchoolManage = "school:manage"
+
+	ParentMonitor = "parent:monitor"
+
+	JobRead = "job:read"
+)
+
+// Permission describes a permission for seeding and listing.
+type Permission struct {
+	Name        string
+	Description string
+}
+
+// Permissions returns every permission the application checks.
+func Permissions() []Permission {
+	return []Permission{
+		{UserRead, "Read user data"},
+		{UserManage, "Update and delete users"},
+		{PointRead, "Read point history of every user"},
+		{PointAdjust, "Manually adjust user point"},
+		{PointReset, "Reset monthly and annual point"},
+		{TaskManage, "Create, update and delete tasks"},
+		{TaskReview, "Review task submissions and requests"},
+		{TaskSubmit, "Submit tasks and task requests"},
+		{RewardManage, "Create, update and delete rewards"},
+		{RewardReview, "Review reward exchange requests"},
+		{RewardExchange, "Exchange point for rewards"},
+		{PenaltyManage, "Create, update and delete penalties"},
+		{PenaltyRead, "Read penalties of every user"},
+		{RoleManage, "Manage roles, permissions and user roles"},
+		{SchoolManage, "Manage schools, classes and class members"},
+		{ParentMonitor, "Monitor linked children"},
+		{JobRead, "Read background job history"},
+	}
+}
+
+// Built in roles.
+const (
+	RoleAdmin     = "admin"
+	RoleUser      = "user"
+	RoleTeacher   = "teacher"
+	RoleHomeroom  = "homeroom"
+	RoleCounselor = "counselor"
+	RoleParent    = "parent"
+)
+
+// DefaultRoles returns the roles seeded on a fresh database together with
+// their initial permissions. The admin role always holds every permission.
+func DefaultRoles() map[string][]string {
+	all := []string{}
+	for _, v := range Permissions() {
+		all = append(all, v.Name)
+	}
+
+	return map[string][]string{
+		RoleAdmin:     all,
+		RoleUser:      {TaskSubmit, RewardExchange},
+		RoleTeacher:   {UserRead, PointRead, TaskManage, TaskReview, PenaltyRead},
+		RoleHomeroom:  {UserRead, PointRead, TaskReview, PenaltyManage, PenaltyRead},
+		RoleCounselor: {UserRead, PointRead, PenaltyManage, PenaltyRead},
+		RoleParent:    {ParentMonitor},
+	}
+}
@@ -1,28 +0,0 @@
-package cloudinary
-
-import (
-	"context"
-	"mime/multipart"
-	"tugaskita/app/config/cloudinary"
-
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
-)
-
-func UploadToCloudinary(file multipart.File, filePath string) (string, error) {
-	ctx := context.Background()
-	cld, err := cloudinary.SetupCloudinary()
-	if err != nil {
-		return "", err
-	}
-
-	uploadParams := uploader.UploadParams{
-		PublicID: filePath,
-	}
-
-	uploadResult, err := cld.Upload.Upload(ctx, file, uploadParams)
-	if err != nil {
-		return "", err
-	}
-
-	return uploadResult.URL, nil
-}
@@ -0,0 +1,166 @@
+// Package event is an in-process publish/subscribe bus. Features publish
+// what happened to students, e.g. a reviewed task, and other features such
+// as notifications react to it without the publisher knowing them.
+//
+// Handlers run synchronously in the goroutine of Publish, in the order they
+// subscribed, so they must be quick and must not publish themselves.
+package event
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Types of events, the comment names the type of Data.
+const (
+	// SubmissionCreated is published when a student sent a submission,
+	// Data is a Submission.
+	SubmissionCreated = "submission.created"
+	// SubmissionResubmitted is published when a student sent a submission
+	// again after a revision was asked, Data is a Submission.
+	SubmissionResubmitted = "submission.resubmitted"
+	// SubmissionReviewed is published when a reviewer changed the status
+	// of a submission, Data is a Submission.
+	SubmissionReviewed = "submission.reviewed"
+	// RewardReviewed is published when a reward exchange was accepted or
+	// rejected, Data is a Reward.
+	RewardReviewed = "reward.reviewed"
+	// PenaltyCreated is published when a student was given a penalty,
+	// Data is a Penalty.
+	PenaltyCreated = "penalty.created"
+	// ReligionTasksGenerated is published when the religion task job
+	// created tasks of a religion, Data is ReligionTasks.
+	ReligionTasksGenerated = "religion_task.generated"
+	// PointsReset is published when the points of every student were
+	// reset, Data is a PointReset.
+	PointsReset = "point.reset"
+	// PointsChanged is published when the points of a student changed,
+	// Data is Points.
+	PointsChanged = "point.changed"
+	// LeaderboardChanged isn't published, streams send it to everyone
+	// after PointsChanged and PointsReset. Data is nil.
+	LeaderboardChanged = "leaderboard.changed"
+)
+
+// Event is something that happened, UserId is the student it happened to
+// and is empty when it concerns many students.
+type Event struct {
+	Type   string    `json:"type"`
+	UserId string    `json:"user_id,omitempty"`
+	Data   any       `json:"data"`
+	At     time.Time `json:"at"`
+}
+
+// Submission is a task upload, task submission, religion upload or
+// religion request, Kind is its entity kind.
+type Submission struct {
+	Kind    string `json:"kind"`
+	Id      string `json:"id"`
+	Title   string `json:"title"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Point   int    `json:"point"`
+}
+
+// Reward is a reward exchange request.
+type Reward struct {
+	Id         string `json:"id"`
+	RewardName string `json:"reward_name"`
+	Amount     int    `json:"amount"`
+	Status     string `json:"status"`
+}
+
+// Penalty is a penalty given to a student.
+type Penalty struct {
+	Description string `json:"description"`
+	Point       int    `json:"point"`
+	Date        string `json:"date"`
+}
+
+// ReligionTasks are the tasks generated for the students of a religion.
+type ReligionTasks struct {
+	Religion string `json:"religion"`
+	Count    int    `json:"count"`
+}
+
+// PointReset is a reset of the points of every student.
+type PointReset struct {
+	Kind   string `json:"kind"`
+	Period string `json:"period"`
+}
+
+// Points is a change of the points of a student, Source is the type of
+// its point history entry, e.g. Penalty.
+type Points struct {
+	Source string `json:"source"`
+}
+
+// Handler reacts to an event.
+type Handler func(Event)
+
+type subscription struct {
+	handler Handler
+	types   map[string]bool
+}
+
+var (
+	mu            sync.RWMutex
+	subscriptions []*subscription
+)
+
+// Subscribe calls handler with every published event of types, or with
+// every event when no type is given. It returns the function cancelling
+// the subscription.
+func Subscribe(handler Handler, types ...string) func() {
+	s := &subscription{handler: handler, types: map[string]bool{}}
+	for _, v := range types {
+		s.types[v] = true
+	}
+
+	mu.Lock()
+	subscriptions = append(subscriptions, s)
+	mu.Unlock()
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for i, v := range subscriptions {
+			if v == s {
+				subscriptions = append(subscriptions[:i:i], subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish calls the handlers subscribed to the type of e. At is set to
+// now when it is zero. A panicking handler is logged and doesn't keep the
+// others from running.
+func Publish(e Event) {
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+
+	mu.RLock()
+	handlers := []Handler{}
+	for _, v := range subscriptions {
+		if len(v.types) == 0 || v.types[e.Type] {
+			handlers = append(handlers, v.handler)
+		}
+	}
+	mu.RUnlock()
+
+	for _, handler := range handlers {
+		call(handler, e)
+	}
+}
+
+func call(handler Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("event: handler of %s panicked: %v", e.Type, r)
+		}
+	}()
+	handler(e)
+}
@@ -0,0 +1,78 @@
+// Package hijri converts between Gregorian and Hijri dates using the
+// tabular Islamic calendar, so it works offline. The tabular calendar can
+// differ by a day from dates set by rukyat or hisab, callers that need the
+// official date should let it be corrected.
+package hijri
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// epoch is the Julian day of 1 Muharram 1 AH in the civil calendar.
+const epoch = 1948439.5
+
+var monthNames = []string{
+	"Muharram", "Safar", "Rabiul Awal", "Rabiul Akhir", "Jumadil Awal", "Jumadil Akhir",
+	"Rajab", "Sya'ban", "Ramadhan", "Syawal", "Dzulqa'dah", "Dzulhijjah",
+}
+
+// Date is a day of the Hijri calendar, Month is 1 based.
+type Date struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// MonthName returns the Indonesian name of the month.
+func (d Date) MonthName() string {
+	if d.Month < 1 || d.Month > 12 {
+		return ""
+	}
+	return monthNames[d.Month-1]
+}
+
+// String formats the date as "9 Ramadhan 1447 H".
+func (d Date) String() string {
+	return fmt.Sprintf("%d %s %d H", d.Day, d.MonthName(), d.Year)
+}
+
+func toJulian(year int, month int, day int) float64 {
+	return float64(day) + math.Ceil(29.5*float64(month-1)) + float64(year-1)*354 +
+		math.Floor(float64(3+11*year)/30) + epoch - 1
+}
+
+func gregorianJulian(t time.Time) float64 {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return float64(day.Unix())/86400 + 2440587.5
+}
+
+// FromGregorian returns the Hijri date of the day of t.
+func FromGregorian(t time.Time) Date {
+	jd := math.Floor(gregorianJulian(t)) + 0.5
+
+	year := int(math.Floor((30*(jd-epoch) + 10646) / 10631))
+	month := int(math.Ceil((jd-(29+toJulian(year, 1, 1)))/29.5)) + 1
+	if month > 12 {
+		month = 12
+	}
+	day := int(jd-toJulian(year, month, 1)) + 1
+
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ToGregorian returns the Gregorian day, at midnight UTC, of a Hijri date.
+func ToGregorian(year int, month int, day int) time.Time {
+	jd := toJulian(year, month, day)
+	return time.Unix(int64(math.Round((jd-2440587.5)*86400)), 0).UTC()
+}
+
+// MonthLength returns the number of days, 29 or 30, of a Hijri month.
+func MonthLength(year int, month int) int {
+	next := toJulian(year, month+1, 1)
+	if month == 12 {
+		next = toJulian(year+1, 1, 1)
+	}
+	return int(next - toJulian(year, month, 1))
+}
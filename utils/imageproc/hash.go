@@ -0,0 +1,53 @@
+package imageproc
+
+import (
+	"image"
+	"math/bits"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// dHash returns the difference hash of img: it is reduced to 9x8 gray
+// pixels and every bit tells whether a pixel is brighter than its right
+// neighbour. Scaling, recompression and small edits change few bits.
+func dHash(img image.Image) uint64 {
+	gray := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.BiLinear.Scale(gray, gray.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if gray.GrayAt(x, y).Y > gray.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// Match tells whether two uploads show the same image from their Hash and
+// DHash, see Result. The distance is 0 for the same file, otherwise the
+// number of differing bits of their difference hashes.
+func Match(hash, dhash, otherHash, otherDHash string) (int, bool) {
+	if hash != "" && hash == otherHash {
+		return 0, true
+	}
+
+	a, errA := strconv.ParseUint(dhash, 16, 64)
+	b, errB := strconv.ParseUint(otherDHash, 16, 64)
+	if dhash == "" || otherDHash == "" || errA != nil || errB != nil {
+		return 0, false
+	}
+
+	distance := bits.OnesCount64(a ^ b)
+	return distance, distance <= options.DuplicateDistance
+}
+
+// MatchSince returns the creation time of the oldest upload whose
+// difference hash is compared by Match, see Options.DuplicateWindow.
+func MatchSince() time.Time {
+	return time.Now().Add(-options.DuplicateWindow)
+}
@@ -0,0 +1,262 @@
+// Package imageproc checks that uploads are images and prepares them for
+// serving. Photos are decoded and encoded again, which drops their EXIF
+// metadata such as the GPS location, scaled down to a maximum dimension
+// and a small thumbnail is made from them.
+//
+// HEIC can't be decoded without cgo, it is recognized only to tell users
+// to upload another format.
+package imageproc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxUploadSize is the largest accepted upload in bytes.
+const MaxUploadSize = 10 * 1024 * 1024
+
+// Content types of the accepted formats, and of HEIC that is rejected
+// with ErrHEIC.
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+	WebP = "image/webp"
+	HEIC = "image/heic"
+)
+
+var (
+	ErrTooLarge    = errors.New("image file size should be less than 10 MB")
+	ErrUnsupported = errors.New("image must be a JPEG, PNG or WebP file")
+	ErrHEIC        = errors.New("HEIC images are not supported, upload a JPEG, PNG or WebP file")
+	ErrInvalid     = errors.New("image file is damaged or its dimensions are too large")
+)
+
+// Options control the processing of images.
+type Options struct {
+	// MaxDimension is the longest side of a stored image, larger images
+	// are scaled down.
+	MaxDimension int
+	// ThumbnailSize is the longest side of a thumbnail.
+	ThumbnailSize int
+	// Quality is the JPEG quality from 1 to 100.
+	Quality int
+	// MaxPixels is the largest width times height decoded, it keeps small
+	// files that expand to huge images from exhausting the memory.
+	MaxPixels int
+	// DuplicateDistance is the largest number of differing bits between
+	// the difference hashes of near identical images.
+	DuplicateDistance int
+	// DuplicateWindow is how far back uploads are compared by their
+	// difference hash, the same file is found among every upload.
+	DuplicateWindow time.Duration
+}
+
+// DefaultOptions are used until SetOptions is called.
+var DefaultOptions = Options{
+	MaxDimension:      2048,
+	ThumbnailSize:     320,
+	Quality:           85,
+	MaxPixels:         50_000_000,
+	DuplicateDistance: 6,
+	DuplicateWindow:   30 * 24 * time.Hour,
+}
+
+var options = DefaultOptions
+
+// SetOptions sets the options used by Process and Match.
+func SetOptions(o Options) {
+	options = o
+}
+
+// Image is an encoded image ready to be stored.
+type Image struct {
+	Data        []byte
+	ContentType string
+	// Ext is the file extension of the format, with the dot.
+	Ext string
+}
+
+var heicBrands = map[string]bool{
+	"heic": true, "heix": true, "heim": true, "heis": true, "hevc": true, "hevx": true,
+}
+
+// Detect returns the content type of an image from its first bytes, 512
+// are enough, or an empty string when the format isn't known.
+func Detect(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF}):
+		return JPEG
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return PNG
+	case len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == "WEBP":
+		return WebP
+	case len(head) >= 16 && string(head[4:8]) == "ftyp":
+		// the major brand and the compatible brands, AVIF shares mif1
+		// with HEIC so only the HEVC brands count
+		size := int(uint32(head[0])<<24 | uint32(head[1])<<16 | uint32(head[2])<<8 | uint32(head[3]))
+		if size > len(head) {
+			size = len(head)
+		}
+		for i := 8; i+4 <= size; i += 4 {
+			if i != 12 && heicBrands[string(head[i:i+4])] {
+				return HEIC
+			}
+		}
+	}
+	return ""
+}
+
+// Check returns ErrTooLarge, ErrHEIC or ErrUnsupported when an upload is
+// too large or not in an accepted format. A nil header passes, the image
+// is optional in some forms.
+func Check(header *multipart.FileHeader) error {
+	if header == nil {
+		return nil
+	}
+	if header.Size > MaxUploadSize {
+		return ErrTooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	switch Detect(head[:n]) {
+	case "":
+		return ErrUnsupported
+	case HEIC:
+		return ErrHEIC
+	}
+	return nil
+}
+
+// Result is a processed upload.
+type Result struct {
+	Image     Image
+	Thumbnail Image
+	// Hash is the hex SHA-256 of the uploaded file.
+	Hash string
+	// DHash is the hex difference hash of the picture.
+	DHash string
+}
+
+// Process returns the image to store, its thumbnail and fingerprints. PNG
+// stays PNG to keep its transparency, the other formats and every
+// thumbnail are encoded as JPEG.
+func Process(data []byte) (Result, error) {
+	sum := sha256.Sum256(data)
+	result := Result{Hash: hex.EncodeToString(sum[:])}
+
+	format := Detect(data)
+	switch format {
+	case "":
+		return Result{}, ErrUnsupported
+	case HEIC:
+		return Result{}, ErrHEIC
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > options.MaxPixels {
+		return Result{}, ErrInvalid
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Result{}, ErrInvalid
+	}
+
+	orientation := 1
+	if format == JPEG {
+		orientation = jpegOrientation(data)
+	}
+
+	full := orient(fit(src, options.MaxDimension, format != PNG), orientation)
+	thumbnail := fit(full, options.ThumbnailSize, true)
+	result.DHash = fmt.Sprintf("%016x", dHash(thumbnail))
+
+	if format == PNG {
+		result.Image, err = encodePNG(full)
+	} else {
+		result.Image, err = encodeJPEG(full)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Thumbnail, err = encodeJPEG(thumbnail)
+	if err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// fit scales src down so its longest side is at most size. flatten draws
+// it over white for formats without transparency.
+func fit(src image.Image, size int, flatten bool) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	} else if !flatten {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	op := draw.Src
+	if flatten {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		op = draw.Over
+	}
+	if width == bounds.Dx() && height == bounds.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, op)
+	} else {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, op, nil)
+	}
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func encodeJPEG(img image.Image) (Image, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: options.Quality}); err != nil {
+		return Image{}, err
+	}
+	return Image{Data: buf.Bytes(), ContentType: JPEG, Ext: ".jpg"}, nil
+}
+
+func encodePNG(img image.Image) (Image, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return Image{}, err
+	}
+	return Image{Data: buf.Bytes(), ContentType: PNG, Ext: ".png"}, nil
+}
@@ -1,54 +1,172 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"os"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/joho/godotenv"
+	"github.com/google/uuid"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 )
 
-type AppConfig struct {
-	JWT_SECRET_KEY string
+var signingKey []byte
+
+func init() {
+	//issued times are kept in milliseconds, a token created right after
+	//the sessions of a user were revoked must not count as revoked
+	jwt.TimePrecision = time.Millisecond
 }
 
-func loadEnv() *AppConfig {
-	var res = new(AppConfig)
-	godotenv.Load(".env")
+// SetSigningKey sets the key used to sign and verify access tokens.
+func SetSigningKey(key string) {
+	signingKey = []byte(key)
+}
 
-	// if err != nil {
-	// 	logrus.Error("Config : Cannot load config file,", err.Error())
-	// 	return nil
-	// }
+// RefreshTokenDuration is how long a refresh token can be used.
+const RefreshTokenDuration = time.Hour * 24 * 30
 
-	if secretKey, found := os.LookupEnv("JWT_SECRET_KEY"); found {
-		res.JWT_SECRET_KEY = secretKey
-	}
-	return res
+// Revoker tells the JWT middleware whether a valid token has been revoked.
+type Revoker interface {
+	IsTokenRevoked(tokenId string, userId string, issuedAt time.Time) (bool, error)
+}
+
+var revoker Revoker
+
+// SetRevoker sets the store used to reject revoked tokens.
+func SetRevoker(r Revoker) {
+	revoker = r
 }
 
 func JWTMiddleware() echo.MiddlewareFunc {
-	key := loadEnv()
-	return echojwt.WithConfig(echojwt.Config{
-		SigningKey:    []byte(key.JWT_SECRET_KEY),
+	return jwtMiddleware("")
+}
+
+// JWTStreamMiddleware is JWTMiddleware also accepting the token in the
+// token query parameter of event stream requests, for EventSource that
+// can't set headers. Query strings end up in logs, so other requests must
+// still send the header.
+func JWTStreamMiddleware() echo.MiddlewareFunc {
+	header := jwtMiddleware("")
+	query := jwtMiddleware("header:Authorization:Bearer ,query:token")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withHeader, withQuery := header(next), query(next)
+
+		return func(c echo.Context) error {
+			request := c.Request()
+			if request.Method == http.MethodGet && strings.Contains(request.Header.Get(echo.HeaderAccept), "text/event-stream") {
+				return withQuery(c)
+			}
+			return withHeader(c)
+		}
+	}
+}
+
+func jwtMiddleware(tokenLookup string) echo.MiddlewareFunc {
+	jwtMiddleware := echojwt.WithConfig(echojwt.Config{
+		SigningKey:    signingKey,
 		SigningMethod: "HS256",
+		TokenLookup:   tokenLookup,
 	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return jwtMiddleware(func(c echo.Context) error {
+			if revoker == nil {
+				return next(c)
+			}
+
+			userId, _, _, err := ExtractTokenUserId(c)
+			if err != nil {
+				return echo.ErrUnauthorized
+			}
+
+			tokenId, issuedAt, _, err := ExtractTokenId(c)
+			if err != nil {
+				return echo.ErrUnauthorized
+			}
+
+			revoked, err := revoker.IsTokenRevoked(tokenId, userId, issuedAt)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]any{
+					"message": "error check token",
+				})
+			}
+
+			if revoked {
+				return c.JSON(http.StatusUnauthorized, map[string]any{
+					"message": "token has been revoked",
+				})
+			}
+
+			return next(c)
+		})
+	}
 }
 
-func CreateToken(userId string, role string, religion string) (string,error) {
-	key := loadEnv()
+func CreateToken(userId string, role string, religion string) (string, error) {
+	if len(signingKey) == 0 {
+		return "", errors.New("jwt signing key is not set")
+	}
+
+	now := time.Now()
 	claims := jwt.MapClaims{}
 	claims["authorized"] = true
+	claims["jti"] = uuid.NewString()
 	claims["userId"] = userId
 	claims["role"] = role
 	claims["religion"] = religion
-	claims["exp"] = time.Now().Add(time.Hour * 1).Unix() //Token expires after 1 hour
+	claims["iat"] = jwt.NewNumericDate(now)
+	claims["exp"] = now.Add(time.Hour * 1).Unix() //Token expires after 1 hour
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(key.JWT_SECRET_KEY))
+	return token.SignedString(signingKey)
+
+}
+
+// CreateRefreshToken returns a random refresh token and the hash that is
+// stored in the database, the token itself is never stored.
+func CreateRefreshToken() (string, string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(data)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the stored form of a refresh token.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ExtractTokenId returns the id, issued time and expired time of the
+// token. Tokens created before ids were added have an empty id.
+func ExtractTokenId(c echo.Context) (string, time.Time, time.Time, error) {
+	user, ok := c.Get("user").(*jwt.Token)
+	if !ok || !user.Valid {
+		return "", time.Time{}, time.Time{}, errors.New("invalid token")
+	}
+
+	claims := user.Claims.(jwt.MapClaims)
+	tokenId, _ := claims["jti"].(string)
+
+	var issuedAt, expiredAt time.Time
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		issuedAt = iat.Time
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		expiredAt = exp.Time
+	}
 
+	return tokenId, issuedAt, expiredAt, nil
 }
 
 func ExtractTokenUserId(c echo.Context) (string, string, string, error) {
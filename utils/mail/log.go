@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogSender writes messages to Dir as .eml files, or to the standard
+// logger when Dir is empty. It never reaches a real mailbox.
+type LogSender struct {
+	Dir  string
+	From string
+}
+
+func (s *LogSender) Send(msg Message) error {
+	data := format(s.From, msg)
+
+	if s.Dir == "" {
+		log.Printf("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(s.Dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), filepath.Base(msg.To))
+	return os.WriteFile(filepath.Join(s.Dir, name), data, 0o644)
+}
@@ -0,0 +1,44 @@
+package mail
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email messages.
+type Sender interface {
+	Send(msg Message) error
+}
+
+// Config selects and configures the sender.
+type Config struct {
+	Driver   string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	Dir      string
+}
+
+// New returns the sender selected by Driver. "smtp" sends through Host,
+// any other value writes messages to Dir or the log so the flow can be
+// tested locally.
+func New(cfg Config) Sender {
+	if cfg.Driver == "smtp" {
+		return &SMTPSender{
+			Host:     cfg.Host,
+			Port:     cfg.Port,
+			Username: cfg.Username,
+			Password: cfg.Password,
+			From:     cfg.From,
+		}
+	}
+
+	return &LogSender{
+		Dir:  cfg.Dir,
+		From: cfg.From,
+	}
+}
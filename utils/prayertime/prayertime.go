@@ -0,0 +1,217 @@
+// Package prayertime calculates Islamic prayer times from the position of
+// the sun, without any network access. The algorithm follows the one
+// published by praytimes.org.
+package prayertime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Prayer names accepted by Window.
+const (
+	Fajr    = "fajr"
+	Dhuhr   = "dhuhr"
+	Asr     = "asr"
+	Maghrib = "maghrib"
+	Isha    = "isha"
+)
+
+// Prayers lists the prayer names in the order of the day.
+var Prayers = []string{Fajr, Dhuhr, Asr, Maghrib, Isha}
+
+// Method holds the conventions of a calculation authority. Angles are the
+// depression of the sun below the horizon, IshaMinutes replaces IshaAngle
+// when set. Ihtiyat is a safety margin added to every time and taken off
+// sunrise.
+type Method struct {
+	Name        string
+	FajrAngle   float64
+	IshaAngle   float64
+	IshaMinutes int
+	Ihtiyat     time.Duration
+}
+
+// Methods are the supported calculation methods by key.
+var Methods = map[string]Method{
+	"kemenag": {Name: "Kementerian Agama Republik Indonesia", FajrAngle: 20, IshaAngle: 18, Ihtiyat: 2 * time.Minute},
+	"mwl":     {Name: "Muslim World League", FajrAngle: 18, IshaAngle: 17},
+	"isna":    {Name: "Islamic Society of North America", FajrAngle: 15, IshaAngle: 15},
+	"egypt":   {Name: "Egyptian General Authority of Survey", FajrAngle: 19.5, IshaAngle: 17.5},
+	"makkah":  {Name: "Umm Al-Qura University, Makkah", FajrAngle: 18.5, IshaMinutes: 90},
+	"karachi": {Name: "University of Islamic Sciences, Karachi", FajrAngle: 18, IshaAngle: 18},
+	"jakim":   {Name: "Jabatan Kemajuan Islam Malaysia", FajrAngle: 20, IshaAngle: 18},
+}
+
+// Times are the prayer times of one day.
+type Times struct {
+	Fajr    time.Time
+	Sunrise time.Time
+	Dhuhr   time.Time
+	Asr     time.Time
+	Maghrib time.Time
+	Isha    time.Time
+}
+
+// Calculator calculates prayer times for a location. AsrFactor is the
+// shadow length used for Asr, 1 (Shafi'i) or 2 (Hanafi).
+type Calculator struct {
+	Method    Method
+	Latitude  float64
+	Longitude float64
+	Location  *time.Location
+	AsrFactor float64
+}
+
+// NewCalculator returns a calculator using the method with the given key.
+func NewCalculator(method string, latitude float64, longitude float64, loc *time.Location) (*Calculator, error) {
+	m, ok := Methods[strings.ToLower(method)]
+	if !ok {
+		return nil, fmt.Errorf("unknown calculation method %q", method)
+	}
+
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, errors.New("latitude or longitude out of range")
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return &Calculator{Method: m, Latitude: latitude, Longitude: longitude, Location: loc, AsrFactor: 1}, nil
+}
+
+func dsin(d float64) float64 { return math.Sin(d * math.Pi / 180) }
+func dcos(d float64) float64 { return math.Cos(d * math.Pi / 180) }
+func dtan(d float64) float64 { return math.Tan(d * math.Pi / 180) }
+
+func darcsin(x float64) float64 { return math.Asin(x) * 180 / math.Pi }
+func darccos(x float64) float64 { return math.Acos(x) * 180 / math.Pi }
+func darctan2(y, x float64) float64 {
+	return math.Atan2(y, x) * 180 / math.Pi
+}
+func darccot(x float64) float64 { return math.Atan(1/x) * 180 / math.Pi }
+
+func fix(a float64, b float64) float64 {
+	a = a - b*math.Floor(a/b)
+	if a < 0 {
+		return a + b
+	}
+	return a
+}
+
+func julian(year int, month int, day int) float64 {
+	if month <= 2 {
+		year--
+		month += 12
+	}
+
+	a := math.Floor(float64(year) / 100)
+	b := 2 - a + math.Floor(a/4)
+	return math.Floor(365.25*float64(year+4716)) + math.Floor(30.6001*float64(month+1)) + float64(day) + b - 1524.5
+}
+
+// sunPosition returns the declination of the sun and the equation of time
+// in hours.
+func sunPosition(jd float64) (float64, float64) {
+	d := jd - 2451545.0
+	g := fix(357.529+0.98560028*d, 360)
+	q := fix(280.459+0.98564736*d, 360)
+	l := fix(q+1.915*dsin(g)+0.020*dsin(2*g), 360)
+
+	e := 23.439 - 0.00000036*d
+	ra := fix(darctan2(dcos(e)*dsin(l), dcos(l))/15, 24)
+
+	return darcsin(dsin(e) * dsin(l)), q/15 - ra
+}
+
+// Times returns the prayer times of the day of date in the calculator's
+// location.
+func (c *Calculator) Times(date time.Time) (Times, error) {
+	date = date.In(c.Location)
+	y, m, d := date.Date()
+	jd := julian(y, int(m), d) - c.Longitude/(15*24)
+
+	midDay := func(t float64) float64 {
+		_, eqt := sunPosition(jd + t)
+		return fix(12-eqt, 24)
+	}
+	//hours from noon for the sun to reach angle below the horizon
+	sunAngle := func(angle float64, t float64, ccw bool) float64 {
+		decl, _ := sunPosition(jd + t)
+		v := darccos((-dsin(angle)-dsin(decl)*dsin(c.Latitude))/(dcos(decl)*dcos(c.Latitude))) / 15
+		if ccw {
+			return midDay(t) - v
+		}
+		return midDay(t) + v
+	}
+
+	decl, _ := sunPosition(jd + 13.0/24)
+	asrAngle := -darccot(c.AsrFactor + dtan(math.Abs(c.Latitude-decl)))
+
+	hours := map[string]float64{
+		Fajr:      sunAngle(c.Method.FajrAngle, 5.0/24, true),
+		"sunrise": sunAngle(0.833, 6.0/24, true),
+		Dhuhr:     midDay(12.0 / 24),
+		Asr:       sunAngle(asrAngle, 13.0/24, false),
+		Maghrib:   sunAngle(0.833, 18.0/24, false),
+		Isha:      sunAngle(c.Method.IshaAngle, 18.0/24, false),
+	}
+	if c.Method.IshaMinutes > 0 {
+		hours[Isha] = hours[Maghrib] + float64(c.Method.IshaMinutes)/60
+	}
+
+	for name, v := range hours {
+		if math.IsNaN(v) {
+			return Times{}, fmt.Errorf("%s can't be calculated at latitude %v", name, c.Latitude)
+		}
+	}
+
+	base := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	at := func(name string, margin time.Duration) time.Time {
+		utc := hours[name] - c.Longitude/15
+		return base.Add(time.Duration(utc * float64(time.Hour))).Add(margin).Truncate(time.Minute).In(c.Location)
+	}
+
+	ihtiyat := c.Method.Ihtiyat
+	return Times{
+		Fajr:    at(Fajr, ihtiyat),
+		Sunrise: at("sunrise", -ihtiyat),
+		Dhuhr:   at(Dhuhr, ihtiyat),
+		Asr:     at(Asr, ihtiyat),
+		Maghrib: at(Maghrib, ihtiyat),
+		Isha:    at(Isha, ihtiyat),
+	}, nil
+}
+
+// Window returns when prayer can be performed on the day of date: from its
+// time until the next prayer, Fajr ends at sunrise and Isha at Fajr of the
+// next day.
+func (c *Calculator) Window(prayer string, date time.Time) (time.Time, time.Time, error) {
+	times, err := c.Times(date)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	switch prayer {
+	case Fajr:
+		return times.Fajr, times.Sunrise, nil
+	case Dhuhr:
+		return times.Dhuhr, times.Asr, nil
+	case Asr:
+		return times.Asr, times.Maghrib, nil
+	case Maghrib:
+		return times.Maghrib, times.Isha, nil
+	case Isha:
+		next, errNext := c.Times(date.In(c.Location).AddDate(0, 0, 1))
+		if errNext != nil {
+			return time.Time{}, time.Time{}, errNext
+		}
+		return times.Isha, next.Fajr, nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("unknown prayer %q", prayer)
+}
@@ -0,0 +1,282 @@
+// Package recurrence implements the subset of RFC 5545 RRULE used for
+// recurring tasks: FREQ=DAILY|WEEKLY|MONTHLY|YEARLY with INTERVAL, BYDAY
+// (weekly), BYMONTH (yearly), BYMONTHDAY (monthly and yearly, negative counts
+// from the end), COUNT and UNTIL. Occurrences are whole days.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule is a parsed recurrence rule.
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonth    []time.Month
+	ByMonthDay []int
+	Count      int
+	Until      time.Time
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH". An
+// optional "RRULE:" prefix is accepted.
+func Parse(rule string) (Rule, error) {
+	r := Rule{Interval: 1}
+
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return Rule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			if value != Daily && value != Weekly && value != Monthly && value != Yearly {
+				return Rule{}, fmt.Errorf("unsupported FREQ %q", value)
+			}
+			r.Freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, errors.New("INTERVAL must be a positive number")
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				day, ok := weekdays[v]
+				if !ok {
+					return Rule{}, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "BYMONTH":
+			for _, v := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 1 || n > 12 {
+					return Rule{}, fmt.Errorf("invalid BYMONTH %q", v)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(n))
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return Rule{}, fmt.Errorf("invalid BYMONTHDAY %q", v)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, errors.New("COUNT must be a positive number")
+			}
+			r.Count = n
+		case "UNTIL":
+			//a time part such as "T235959Z" is ignored
+			if len(value) > 8 {
+				value = value[:8]
+			}
+			until, err := time.Parse("20060102", value)
+			if err != nil {
+				return Rule{}, errors.New("UNTIL must use YYYYMMDD format")
+			}
+			r.Until = until
+		default:
+			return Rule{}, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return Rule{}, errors.New("FREQ is required")
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return Rule{}, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if len(r.ByMonth) > 0 && r.Freq != Yearly {
+		return Rule{}, errors.New("BYMONTH is only supported with FREQ=YEARLY")
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != Monthly && r.Freq != Yearly {
+		return Rule{}, errors.New("BYMONTHDAY is only supported with FREQ=MONTHLY or FREQ=YEARLY")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return Rule{}, errors.New("COUNT and UNTIL can't be combined")
+	}
+
+	return r, nil
+}
+
+// String formats the rule back to RRULE syntax.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		names := []string{}
+		for _, day := range r.ByDay {
+			for name, v := range weekdays {
+				if v == day {
+					names = append(names, name)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(names, ","))
+	}
+
+	if len(r.ByMonth) > 0 {
+		months := []string{}
+		for _, v := range r.ByMonth {
+			months = append(months, strconv.Itoa(int(v)))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		days := []string{}
+		for _, v := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(v))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (r Rule) matches(start time.Time, d time.Time) bool {
+	switch r.Freq {
+	case Daily:
+		return int(d.Sub(start).Hours()/24)%r.Interval == 0
+
+	case Weekly:
+		weekStart := func(t time.Time) time.Time {
+			return t.AddDate(0, 0, -int(t.Weekday()))
+		}
+		weeks := int(weekStart(d).Sub(weekStart(start)).Hours() / (24 * 7))
+		if weeks%r.Interval != 0 {
+			return false
+		}
+
+		if len(r.ByDay) == 0 {
+			return d.Weekday() == start.Weekday()
+		}
+		for _, v := range r.ByDay {
+			if d.Weekday() == v {
+				return true
+			}
+		}
+		return false
+
+	case Monthly:
+		months := (d.Year()-start.Year())*12 + int(d.Month()) - int(start.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+
+		return r.matchesMonthDay(start, d)
+
+	case Yearly:
+		if (d.Year()-start.Year())%r.Interval != 0 {
+			return false
+		}
+
+		if len(r.ByMonth) == 0 {
+			if d.Month() != start.Month() {
+				return false
+			}
+		} else {
+			found := false
+			for _, v := range r.ByMonth {
+				if d.Month() == v {
+					found = true
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return r.matchesMonthDay(start, d)
+	}
+
+	return false
+}
+
+func (r Rule) matchesMonthDay(start time.Time, d time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return d.Day() == start.Day()
+	}
+
+	lastDay := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, v := range r.ByMonthDay {
+		if v > 0 && d.Day() == v || v < 0 && d.Day() == lastDay+v+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Between returns the occurrences of a series starting on start that fall
+// within [from, to], both inclusive. COUNT is counted from start.
+func (r Rule) Between(start time.Time, from time.Time, to time.Time) []time.Time {
+	start, from, to = day(start), day(from), day(to)
+	if !r.Until.IsZero() && r.Until.Before(to) {
+		to = day(r.Until)
+	}
+
+	result := []time.Time{}
+	count := 0
+	for d := start; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !r.matches(start, d) {
+			continue
+		}
+
+		count++
+		if r.Count > 0 && count > r.Count {
+			break
+		}
+		if !d.Before(from) {
+			result = append(result, d)
+		}
+	}
+
+	return result
+}
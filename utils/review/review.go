@@ -0,0 +1,81 @@
+// Package review is the workflow shared by everything students send for
+// review: task uploads, task submissions, religion uploads and religion
+// requests.
+//
+//	Perlu Review ─┬─> Perlu Revisi ─> Direvisi ─┬─> Perlu Revisi
+//	              ├─> Diterima                  ├─> Diterima
+//	              └─> Ditolak                   └─> Ditolak
+//
+// Reviewers move a submission out of Perlu Review and Direvisi, students
+// move it from Perlu Revisi to Direvisi by sending it again. Diterima and
+// Ditolak are final.
+package review
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Statuses of a submission.
+const (
+	Pending       = "Perlu Review"
+	NeedsRevision = "Perlu Revisi"
+	Resubmitted   = "Direvisi"
+	Approved      = "Diterima"
+	Rejected      = "Ditolak"
+)
+
+var (
+	ErrStatus       = errors.New("status must be Perlu Revisi, Diterima or Ditolak")
+	ErrNoteRequired = errors.New("a note is required when asking for a revision or rejecting")
+	ErrNotRevisable = errors.New("only a submission that needs a revision can be sent again")
+	// ErrConflict is returned when the status of a submission changed
+	// since it was checked.
+	ErrConflict = errors.New("submission was changed in the meantime, reload it and try again")
+	// ErrClaimed is returned when another reviewer holds the claim of a
+	// submission.
+	ErrClaimed = errors.New("submission is claimed by another reviewer")
+)
+
+// reviewed are the statuses a reviewer can pick from the statuses a
+// submission waits for review in.
+var reviewed = map[string]map[string]bool{
+	Pending:     {NeedsRevision: true, Approved: true, Rejected: true},
+	Resubmitted: {NeedsRevision: true, Approved: true, Rejected: true},
+}
+
+// Final reports whether a submission can't change anymore.
+func Final(status string) bool {
+	return status == Approved || status == Rejected
+}
+
+// Review returns an error when a reviewer can't move a submission from
+// status from to to. The reviewer has to explain what is wrong when the
+// submission isn't approved.
+func Review(from string, to string, note string) error {
+	if to != NeedsRevision && to != Approved && to != Rejected {
+		return ErrStatus
+	}
+	if from == to {
+		return fmt.Errorf("submission is already %s", to)
+	}
+	if Final(from) {
+		return fmt.Errorf("submission is already %s and can't be changed", from)
+	}
+	if !reviewed[from][to] {
+		return fmt.Errorf("submission is %s, it can't be reviewed before it is sent again", from)
+	}
+	if to != Approved && note == "" {
+		return ErrNoteRequired
+	}
+	return nil
+}
+
+// Resubmit returns an error when a student can't send a submission in
+// status from again.
+func Resubmit(from string) error {
+	if from != NeedsRevision {
+		return ErrNotRevisable
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed five field cron expression
+// "minute hour day-of-month month day-of-week". Every field accepts "*",
+// numbers, ranges "a-b", lists "a,b" and steps "*/n", "a-b/n" or "a/n",
+// the last one running from a to the end of the field.
+type Schedule struct {
+	minute [60]bool
+	hour   [24]bool
+	dom    [32]bool
+	month  [13]bool
+	dow    [7]bool
+
+	anyDom bool
+	anyDow bool
+}
+
+// Parse parses a cron expression, see Schedule.
+func Parse(spec string) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Schedule{}, errors.New("schedule must have 5 fields")
+	}
+
+	var s Schedule
+	if err := parseField(fields[0], 0, 59, s.minute[:]); err != nil {
+		return Schedule{}, fmt.Errorf("minute: %w", err)
+	}
+	if err := parseField(fields[1], 0, 23, s.hour[:]); err != nil {
+		return Schedule{}, fmt.Errorf("hour: %w", err)
+	}
+	if err := parseField(fields[2], 1, 31, s.dom[:]); err != nil {
+		return Schedule{}, fmt.Errorf("day of month: %w", err)
+	}
+	if err := parseField(fields[3], 1, 12, s.month[:]); err != nil {
+		return Schedule{}, fmt.Errorf("month: %w", err)
+	}
+	if err := parseField(fields[4], 0, 6, s.dow[:]); err != nil {
+		return Schedule{}, fmt.Errorf("day of week: %w", err)
+	}
+
+	//a day field listing every day, e.g. "*/1", is as unrestricted as "*"
+	s.anyDom = every(s.dom[1:])
+	s.anyDow = every(s.dow[:])
+	return s, nil
+}
+
+func parseField(field string, min int, max int, set []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		step, stepped := 1, false
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid step %q", part)
+			}
+			step, stepped = n, true
+			part = part[:i]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil {
+				return fmt.Errorf("invalid range %q", part)
+			}
+			start, end = a, b
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return fmt.Errorf("invalid value %q", part)
+			}
+			start, end = n, n
+			if stepped {
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+
+		for v := start; v <= end; v += step {
+			set[v] = true
+		}
+	}
+
+	return nil
+}
+
+func every(set []bool) bool {
+	for _, v := range set {
+		if !v {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom[t.Day()]
+	dow := s.dow[int(t.Weekday())]
+
+	//like cron, a restricted day of month and day of week match either one
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dow
+	case s.anyDow:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Next returns the first activation strictly after t, in t's location.
+// It returns the zero time when nothing matches within five years.
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// Prev returns the last activation at or before t that is not older than
+// window, or the zero time when there is none.
+func (s Schedule) Prev(t time.Time, window time.Duration) time.Time {
+	var last time.Time
+	for next := s.Next(t.Add(-window - time.Minute)); !next.IsZero() && !next.After(t); next = s.Next(next) {
+		last = next
+	}
+	return last
+}
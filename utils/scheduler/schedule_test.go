@@ -0,0 +1,225 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		from string
+		want []string
+	}{
+		{
+			name: "every minute",
+			spec: "* * * * *",
+			from: "2026-10-18 10:00",
+			want: []string{"2026-10-18 10:01", "2026-10-18 10:02"},
+		},
+		{
+			name: "step of every value",
+			spec: "*/20 * * * *",
+			from: "2026-10-18 10:05",
+			want: []string{"2026-10-18 10:20", "2026-10-18 10:40", "2026-10-18 11:00"},
+		},
+		{
+			name: "step from a value runs to the end of the field",
+			spec: "5/20 * * * *",
+			from: "2026-10-18 10:00",
+			want: []string{"2026-10-18 10:05", "2026-10-18 10:25", "2026-10-18 10:45", "2026-10-18 11:05"},
+		},
+		{
+			name: "step of a range",
+			spec: "0 8-14/3 * * *",
+			from: "2026-10-18 09:00",
+			want: []string{"2026-10-18 11:00", "2026-10-18 14:00", "2026-10-19 08:00"},
+		},
+		{
+			name: "range",
+			spec: "30 22-23 * * *",
+			from: "2026-10-18 22:30",
+			want: []string{"2026-10-18 23:30", "2026-10-19 22:30"},
+		},
+		{
+			name: "list",
+			spec: "0 0 1,15 * *",
+			from: "2026-10-18 00:00",
+			want: []string{"2026-11-01 00:00", "2026-11-15 00:00", "2026-12-01 00:00"},
+		},
+		{
+			name: "day of month missing from short months",
+			spec: "0 0 31 * *",
+			from: "2026-10-31 00:00",
+			want: []string{"2026-12-31 00:00", "2027-01-31 00:00"},
+		},
+		{
+			name: "day of week only",
+			spec: "0 7 * * 1",
+			from: "2026-10-18 00:00",
+			want: []string{"2026-10-19 07:00", "2026-10-26 07:00"},
+		},
+		{
+			name: "day of month or day of week",
+			spec: "0 0 1 * 1",
+			from: "2026-10-25 00:00",
+			want: []string{"2026-10-26 00:00", "2026-11-01 00:00", "2026-11-02 00:00"},
+		},
+		{
+			name: "every day of month as a step is unrestricted",
+			spec: "0 0 */1 * 1",
+			from: "2026-10-18 00:00",
+			want: []string{"2026-10-19 00:00", "2026-10-26 00:00"},
+		},
+		{
+			name: "every day of week as a range is unrestricted",
+			spec: "0 0 1 * 0-6",
+			from: "2026-10-18 00:00",
+			want: []string{"2026-11-01 00:00", "2026-12-01 00:00"},
+		},
+		{
+			name: "annual reset",
+			spec: "0 0 1 7 *",
+			from: "2026-07-01 00:00",
+			want: []string{"2027-07-01 00:00", "2028-07-01 00:00"},
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			from: "2026-01-01 00:00",
+			want: []string{"2028-02-29 00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+
+			at := date(tt.from)
+			for _, v := range tt.want {
+				at = s.Next(at)
+				if want := date(v); !at.Equal(want) {
+					t.Fatalf("Next = %s, want %s", at.Format("2006-01-02 15:04"), v)
+				}
+			}
+		})
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if at := s.Next(date("2026-01-01 00:00")); !at.IsZero() {
+		t.Errorf("Next = %s, want the zero time", at)
+	}
+}
+
+func TestPrev(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		at     string
+		window time.Duration
+		want   string
+	}{
+		{
+			name:   "activation at the time itself",
+			spec:   "0 0 1 * *",
+			at:     "2026-11-01 00:00",
+			window: time.Hour,
+			want:   "2026-11-01 00:00",
+		},
+		{
+			name:   "across a month",
+			spec:   "0 0 1 * *",
+			at:     "2026-11-03 12:00",
+			window: 72 * time.Hour,
+			want:   "2026-11-01 00:00",
+		},
+		{
+			name:   "across a year",
+			spec:   "0 0 31 12 *",
+			at:     "2027-01-02 08:00",
+			window: 7 * 24 * time.Hour,
+			want:   "2026-12-31 00:00",
+		},
+		{
+			name:   "annual reset",
+			spec:   "0 0 1 7 *",
+			at:     "2026-07-05 00:00",
+			window: 7 * 24 * time.Hour,
+			want:   "2026-07-01 00:00",
+		},
+		{
+			name:   "latest of several",
+			spec:   "0 */6 * * *",
+			at:     "2026-10-18 13:00",
+			window: 24 * time.Hour,
+			want:   "2026-10-18 12:00",
+		},
+		{
+			name:   "older than the window",
+			spec:   "0 0 1 7 *",
+			at:     "2026-07-05 00:00",
+			window: 72 * time.Hour,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+
+			got := s.Prev(date(tt.at), tt.window)
+			if tt.want == "" {
+				if !got.IsZero() {
+					t.Fatalf("Prev = %s, want the zero time", got.Format("2006-01-02 15:04"))
+				}
+				return
+			}
+			if want := date(tt.want); !got.Equal(want) {
+				t.Fatalf("Prev = %s, want %s", got.Format("2006-01-02 15:04"), tt.want)
+			}
+		})
+	}
+}
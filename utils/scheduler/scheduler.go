@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a function run at each activation of its schedule. It receives
+// the activation time so a run can be keyed by the period it belongs to,
+// and returns how many items it created for the run history.
+type Job func(at time.Time) (int, error)
+
+// Run is one attempt of a job activation.
+type Run struct {
+	Name       string
+	At         time.Time
+	Attempt    int
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Created    int
+	Err        error
+}
+
+// Recorder stores the history of job runs.
+type Recorder interface {
+	Record(run Run) error
+}
+
+// Locker makes sure an activation runs on a single instance when several
+// share a database. Acquire reports false when another instance is running
+// the job or already completed the activation, a lock not released within
+// ttl expires. Release marks the activation done when it succeeded.
+type Locker interface {
+	Acquire(name string, at time.Time, ttl time.Duration) (bool, error)
+	Release(name string, at time.Time, done bool) error
+}
+
+type entry struct {
+	name     string
+	schedule Schedule
+	job      Job
+	catchUp  time.Duration
+}
+
+// Scheduler runs jobs in process on cron schedules.
+type Scheduler struct {
+	location *time.Location
+	entries  []entry
+	stop     chan struct{}
+	wg       sync.WaitGroup
+
+	recorder Recorder
+	locker   Locker
+	lockTTL  time.Duration
+	retries  int
+	backoff  time.Duration
+}
+
+// New returns a scheduler evaluating schedules in location.
+func New(location *time.Location) *Scheduler {
+	if location == nil {
+		location = time.Local
+	}
+
+	return &Scheduler{
+		location: location,
+		stop:     make(chan struct{}),
+	}
+}
+
+// SetRecorder records every attempt with recorder.
+func (s *Scheduler) SetRecorder(recorder Recorder) {
+	s.recorder = recorder
+}
+
+// SetLocker guards every activation with locker, ttl must cover a run
+// including its retries.
+func (s *Scheduler) SetLocker(locker Locker, ttl time.Duration) {
+	s.locker = locker
+	s.lockTTL = ttl
+}
+
+// SetRetry retries a failed activation up to retries times, waiting
+// backoff before the first retry and doubling it for each next one.
+func (s *Scheduler) SetRetry(retries int, backoff time.Duration) {
+	s.retries = retries
+	s.backoff = backoff
+}
+
+// Add registers job under spec. When catchUp is positive an activation
+// missed within that window, e.g. while the server was down, is run once
+// on Start, so the job must be safe to run twice for the same activation.
+func (s *Scheduler) Add(name string, spec string, catchUp time.Duration, job Job) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+
+	s.entries = append(s.entries, entry{
+		name:     name,
+		schedule: schedule,
+		job:      job,
+		catchUp:  catchUp,
+	})
+	return nil
+}
+
+// Start runs every registered job in its own goroutine.
+func (s *Scheduler) Start() {
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.run(e)
+	}
+}
+
+// Stop stops the scheduler and waits for running jobs to finish.
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(e entry) {
+	defer s.wg.Done()
+
+	now := time.Now().In(s.location)
+	if e.catchUp > 0 {
+		if missed := e.schedule.Prev(now, e.catchUp); !missed.IsZero() {
+			s.exec(e, missed)
+		}
+	}
+
+	for {
+		next := e.schedule.Next(time.Now().In(s.location))
+		if next.IsZero() {
+			log.Printf("scheduler: %s has no next activation", e.name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.exec(e, next)
+		}
+	}
+}
+
+func (s *Scheduler) exec(e entry, at time.Time) {
+	if s.locker != nil {
+		acquired, err := s.locker.Acquire(e.name, at, s.lockTTL)
+		if err != nil {
+			log.Printf("scheduler: %s at %s lock failed: %v", e.name, at.Format(time.RFC3339), err)
+			return
+		}
+		if !acquired {
+			log.Printf("scheduler: %s at %s skipped, run by another instance", e.name, at.Format(time.RFC3339))
+			return
+		}
+	}
+
+	done := false
+	defer func() {
+		if s.locker == nil {
+			return
+		}
+		if err := s.locker.Release(e.name, at, done); err != nil {
+			log.Printf("scheduler: %s at %s unlock failed: %v", e.name, at.Format(time.RFC3339), err)
+		}
+	}()
+
+	wait := s.backoff
+	for attempt := 1; ; attempt++ {
+		run := s.attempt(e, at, attempt)
+		if run.Err == nil {
+			done = true
+			log.Printf("scheduler: %s at %s done, %d created", e.name, at.Format(time.RFC3339), run.Created)
+			return
+		}
+
+		log.Printf("scheduler: %s at %s attempt %d failed: %v", e.name, at.Format(time.RFC3339), attempt, run.Err)
+		if attempt > s.retries {
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+// attempt runs the job once and records the outcome.
+func (s *Scheduler) attempt(e entry, at time.Time, attempt int) (run Run) {
+	run = Run{Name: e.name, At: at, Attempt: attempt, StartedAt: time.Now()}
+
+	defer func() {
+		if r := recover(); r != nil {
+			run.Err = fmt.Errorf("panic: %v", r)
+		}
+		run.FinishedAt = time.Now()
+
+		if s.recorder != nil {
+			if err := s.recorder.Record(run); err != nil {
+				log.Printf("scheduler: %s record failed: %v", e.name, err)
+			}
+		}
+	}()
+
+	run.Created, run.Err = e.job(at)
+	return run
+}
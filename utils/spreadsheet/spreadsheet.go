@@ -0,0 +1,111 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Supported file formats.
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// FormatOf returns the format of a file name from its extension.
+func FormatOf(name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	}
+	return "", errors.New("file must be csv or xlsx")
+}
+
+// ReadFile reads every row of an uploaded csv file or of the first sheet
+// of an xlsx file.
+func ReadFile(file *multipart.FileHeader) ([][]string, error) {
+	format, err := FormatOf(file.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return Read(src, format)
+}
+
+// Read reads every row from r in the given format.
+func Read(r io.Reader, format string) ([][]string, error) {
+	switch format {
+	case CSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case XLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("xlsx file has no sheet")
+		}
+		return f.GetRows(sheets[0])
+	}
+	return nil, errors.New("unsupported format " + format)
+}
+
+// Write writes rows to w in the given format.
+func Write(w io.Writer, format string, rows [][]string) error {
+	switch format {
+	case CSV:
+		writer := csv.NewWriter(w)
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	case XLSX:
+		f := excelize.NewFile()
+		defer f.Close()
+
+		sheet := f.GetSheetName(0)
+		for i, row := range rows {
+			cell, err := excelize.CoordinatesToCellName(1, i+1)
+			if err != nil {
+				return err
+			}
+
+			values := make([]any, len(row))
+			for j, v := range row {
+				values[j] = v
+			}
+			if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+				return err
+			}
+		}
+		return f.Write(w)
+	}
+	return errors.New("unsupported format " + format)
+}
+
+// ContentType returns the mime type of a format.
+func ContentType(format string) string {
+	if format == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
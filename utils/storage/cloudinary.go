@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+// Cloudinary stores files as Cloudinary assets, the key without its
+// extension is the public id.
+type Cloudinary struct {
+	cld *cloudinary.Cloudinary
+}
+
+// NewCloudinary returns a storage using the account of cloud.
+func NewCloudinary(cloud string, apiKey string, apiSecret string) (*Cloudinary, error) {
+	cld, err := cloudinary.NewFromParams(cloud, apiKey, apiSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &Cloudinary{cld: cld}, nil
+}
+
+func publicId(key string) string {
+	return strings.TrimSuffix(key, path.Ext(key))
+}
+
+// Put implements Storage.
+func (c *Cloudinary) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	result, err := c.cld.Upload.Upload(ctx, r, uploader.UploadParams{PublicID: publicId(key)})
+	if err != nil {
+		return "", err
+	}
+	if result.Error.Message != "" {
+		return "", errors.New(result.Error.Message)
+	}
+	return result.SecureURL, nil
+}
+
+// Delete implements Storage.
+func (c *Cloudinary) Delete(ctx context.Context, key string) error {
+	_, err := c.cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: publicId(key)})
+	return err
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores files in Dir. URLs are BaseURL followed by the key, the
+// files have to be served from there.
+type Local struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewLocal returns a storage writing to dir.
+func NewLocal(dir string, baseURL string) *Local {
+	return &Local{Dir: dir, BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+func (l *Local) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid storage key")
+	}
+	return filepath.Join(l.Dir, filepath.FromSlash(clean)), nil
+}
+
+// Put implements Storage. The file is written next to its destination and
+// renamed so a failed upload never leaves a partial file behind.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	dst, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", err
+	}
+
+	if l.BaseURL == "" {
+		return key, nil
+	}
+	return l.BaseURL + "/" + key, nil
+}
+
+// Delete implements Storage.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	dst, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(dst); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
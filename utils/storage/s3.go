@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 stores files in a bucket of an S3 compatible service such as AWS S3
+// or MinIO. URLs start with PublicURL, or the bucket URL on the endpoint
+// when it is empty.
+type S3 struct {
+	client    *minio.Client
+	Bucket    string
+	PublicURL string
+}
+
+// NewS3 returns a storage using bucket on endpoint, a host with an
+// optional port.
+func NewS3(endpoint string, region string, bucket string, accessKey string, secretKey string, useSSL bool, publicURL string) (*S3, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+		Region: region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if publicURL == "" {
+		publicURL = client.EndpointURL().String() + "/" + bucket
+	}
+
+	return &S3{client: client, Bucket: bucket, PublicURL: strings.TrimSuffix(publicURL, "/")}, nil
+}
+
+// Put implements Storage.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	_, err := s.client.PutObject(ctx, s.Bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return "", err
+	}
+	return s.PublicURL + "/" + key, nil
+}
+
+// Delete implements Storage.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.Bucket, key, minio.RemoveObjectOptions{})
+}
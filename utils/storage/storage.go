@@ -0,0 +1,107 @@
+// Package storage keeps uploaded files on the local disk, an S3 compatible
+// object store or Cloudinary. Files are stored under random names so
+// uploads with the same client file name don't overwrite each other.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"path"
+	"regexp"
+	"strings"
+	"tugaskita/utils/imageproc"
+
+	"github.com/google/uuid"
+)
+
+// Storage stores files by key, a slash separated path such as
+// "images/user/<uuid>.png".
+type Storage interface {
+	// Put stores size bytes of r under key and returns the URL the file
+	// is served from.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error)
+	// Delete removes the file stored under key, a missing file is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
+
+var extPattern = regexp.MustCompile(`^\.[a-z0-9]{1,5}$`)
+
+// Key returns a new random key in folder keeping the extension of
+// filename when it looks like one.
+func Key(folder string, filename string) string {
+	ext := strings.ToLower(path.Ext(strings.ReplaceAll(filename, "\\", "/")))
+	if !extPattern.MatchString(ext) {
+		ext = ""
+	}
+	return path.Join(folder, uuid.NewString()+ext)
+}
+
+// StoredImage is an image saved by SaveImage.
+type StoredImage struct {
+	Key          string
+	URL          string
+	ThumbnailKey string
+	ThumbnailURL string
+	// Hash and DHash are the fingerprints of the upload, see
+	// imageproc.Match.
+	Hash  string
+	DHash string
+}
+
+// SaveImage checks, processes and stores an uploaded image in folder
+// together with its thumbnail, see imageproc.Process. The image is
+// removed again when its thumbnail can't be stored.
+func SaveImage(ctx context.Context, s Storage, folder string, header *multipart.FileHeader) (StoredImage, error) {
+	if header.Size > imageproc.MaxUploadSize {
+		return StoredImage{}, imageproc.ErrTooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return StoredImage{}, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, imageproc.MaxUploadSize+1))
+	file.Close()
+	if err != nil {
+		return StoredImage{}, err
+	}
+	if len(data) > imageproc.MaxUploadSize {
+		return StoredImage{}, imageproc.ErrTooLarge
+	}
+
+	processed, err := imageproc.Process(data)
+	if err != nil {
+		return StoredImage{}, err
+	}
+	img, thumbnail := processed.Image, processed.Thumbnail
+
+	result := StoredImage{Hash: processed.Hash, DHash: processed.DHash}
+	result.Key = Key(folder, img.Ext)
+	result.URL, err = s.Put(ctx, result.Key, bytes.NewReader(img.Data), int64(len(img.Data)), img.ContentType)
+	if err != nil {
+		return StoredImage{}, err
+	}
+
+	result.ThumbnailKey = strings.TrimSuffix(result.Key, img.Ext) + "_thumb" + thumbnail.Ext
+	result.ThumbnailURL, err = s.Put(ctx, result.ThumbnailKey, bytes.NewReader(thumbnail.Data), int64(len(thumbnail.Data)), thumbnail.ContentType)
+	if err != nil {
+		s.Delete(ctx, result.Key)
+		return StoredImage{}, err
+	}
+
+	return result, nil
+}
+
+// DeleteImage removes an image stored by SaveImage and its thumbnail, for
+// when what refers to it couldn't be saved. Failures are ignored, the
+// files are only left behind then. A zero img is skipped.
+func DeleteImage(ctx context.Context, s Storage, img StoredImage) {
+	for _, key := range []string{img.Key, img.ThumbnailKey} {
+		if key != "" {
+			s.Delete(ctx, key)
+		}
+	}
+}